@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -20,17 +21,29 @@ import (
 // Agent runs a set of plugins.
 type Agent struct {
 	Config *config.Config
+
+	reloadC chan *reloadRequest
+
+	// The routes of the running agent, read by the management API.
+	routesMu sync.Mutex
+	routes   []*config.Route
+
+	// The state file of the stateful plugins, nil when not configured.
+	state *statePersister
 }
 
 // NewAgent returns an Agent for the given Config.
 func NewAgent(config *config.Config) (*Agent, error) {
 	a := &Agent{
-		Config: config,
+		Config:  config,
+		reloadC: make(chan *reloadRequest),
 	}
 	return a, nil
 }
 
-// inputUnit is a group of input plugins and the shared channel they write to.
+// inputUnit is a group of input plugins and the route channels they write to.
+// An input that is part of several routes writes to a channel that is fanned
+// out to each route.
 //
 // ┌───────┐
 // │ Input │───┐
@@ -38,12 +51,26 @@ func NewAgent(config *config.Config) (*Agent, error) {
 // ┌───────┐   │     ______
 // │ Input │───┼──▶ ()_____)
 // └───────┘   │
-// ┌───────┐   │
-// │ Input │───┘
+// ┌───────┐   │     ______
+// │ Input │───┴──▶ ()_____)
 // └───────┘
 type inputUnit struct {
-	dst    chan<- telegraf.Metric
+	sync.Mutex
+	dsts   []chan<- telegraf.Metric
 	inputs []*models.RunningInput
+
+	inputDsts map[*models.RunningInput]chan<- telegraf.Metric
+	fanOuts   map[*models.RunningInput]chan telegraf.Metric
+	fanOutWg  sync.WaitGroup
+
+	// While running, each input has a gather loop; inputs can be added and
+	// removed without interrupting the others.  The loops are nil until the
+	// unit is run.
+	running   bool
+	ctx       context.Context
+	startTime time.Time
+	loops     map[*models.RunningInput]*pluginLoop
+	wg        sync.WaitGroup
 }
 
 //  ______     ┌───────────┐     ______
@@ -77,8 +104,8 @@ type aggregatorUnit struct {
 	aggregators []*models.RunningAggregator
 }
 
-// outputUnit is a group of Outputs and their source channel.  Metrics on the
-// channel are written to all outputs.
+// outputUnit is a group of Outputs and their source channels.  Metrics on a
+// source channel are written to all outputs of its route.
 //
 //                            ┌────────┐
 //                       ┌──▶ │ Output │
@@ -90,8 +117,100 @@ type aggregatorUnit struct {
 //                       └──▶ │ Output │
 //                            └────────┘
 type outputUnit struct {
-	src     <-chan telegraf.Metric
+	sync.RWMutex
+	sources []*outputSource
 	outputs []*models.RunningOutput
+
+	// While running, each output has a flush loop; outputs can be added and
+	// removed without interrupting the others.  The loops are nil until the
+	// unit is run.
+	running bool
+	ctx     context.Context
+	loops   map[*models.RunningOutput]*pluginLoop
+	wg      sync.WaitGroup
+}
+
+// outputSource is the channel of metrics from a route and the outputs of the
+// route.
+type outputSource struct {
+	src     chan telegraf.Metric
+	outputs []*models.RunningOutput
+}
+
+// routeUnit is the processing of a route and the channel its inputs write
+// to.  The processing unit can be replaced while running.
+type routeUnit struct {
+	route *config.Route
+	src   chan telegraf.Metric
+	pu    *processingUnit
+	swapC chan *processingSwap
+}
+
+// processingUnit is the chain of processors and aggregators between the
+// inputs and outputs.  The chain writes to its own sink channel which is
+// forwarded to the outputs channel, so that the chain can be closed and
+// replaced without closing the outputs channel.  When there are no
+// processors or aggregators the source is the sink channel.
+//
+//  ______     ┌────────────┐     ┌─────────────┐     ┌────────────┐     ______
+// ()_____)──▶ │ Processors │──▶ │ Aggregators │──▶ │ Processors │──▶ ()_____)
+//             └────────────┘     └─────────────┘     └────────────┘
+type processingUnit struct {
+	src  chan<- telegraf.Metric
+	sink <-chan telegraf.Metric
+	dst  chan<- telegraf.Metric
+
+	processors    models.RunningProcessors
+	aggProcessors models.RunningProcessors
+	aggregators   []*models.RunningAggregator
+
+	pu  []*processorUnit
+	apu []*processorUnit
+	au  *aggregatorUnit
+	wg  sync.WaitGroup
+}
+
+var errLoopStopped = errors.New("plugin is not running")
+
+// pluginLoop is a running gather or flush loop.
+type pluginLoop struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	trigger chan chan error
+}
+
+func newPluginLoop(cancel context.CancelFunc) *pluginLoop {
+	return &pluginLoop{
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		trigger: make(chan chan error),
+	}
+}
+
+// stop stops the loop and waits for it to return.
+func (l *pluginLoop) stop() {
+	l.cancel()
+	<-l.done
+}
+
+// runOnce has the loop gather or flush once, outside of its schedule, and
+// waits for the result.
+func (l *pluginLoop) runOnce(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case l.trigger <- reply:
+	case <-l.done:
+		return errLoopStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Run starts and runs the Agent until the context is done.
@@ -101,8 +220,17 @@ func (a *Agent) Run(ctx context.Context) error {
 		a.Config.Agent.Interval.Duration, a.Config.Agent.Quiet,
 		a.Config.Agent.Hostname, a.Config.Agent.FlushInterval.Duration)
 
+	routes, err := a.Config.Routes()
+	if err != nil {
+		return err
+	}
+
+	if a.Config.Agent.Statefile != "" {
+		a.state = newStatePersister(a.Config.Agent.Statefile)
+	}
+
 	log.Printf("D! [agent] Initializing plugins")
-	err := a.initPlugins()
+	err = a.initPlugins()
 	if err != nil {
 		return err
 	}
@@ -110,39 +238,28 @@ func (a *Agent) Run(ctx context.Context) error {
 	startTime := time.Now()
 
 	log.Printf("D! [agent] Connecting outputs")
-	next, ou, err := a.startOutputs(ctx, a.Config.Outputs)
+	ou, err := a.startOutputs(ctx, a.Config.Outputs)
 	if err != nil {
 		return err
 	}
 
-	var apu []*processorUnit
-	var au *aggregatorUnit
-	if len(a.Config.Aggregators) != 0 {
-		aggC := next
-		if len(a.Config.AggProcessors) != 0 {
-			aggC, apu, err = a.startProcessors(next, a.Config.AggProcessors)
-			if err != nil {
-				return err
-			}
-		}
+	ru, err := a.startRoutes(routes, ou.addSource)
+	if err != nil {
+		return err
+	}
 
-		next, au, err = a.startAggregators(aggC, next, a.Config.Aggregators)
-		if err != nil {
-			return err
-		}
+	iu, err := a.startInputs(ru, a.Config.Inputs)
+	if err != nil {
+		return err
 	}
+	a.setRoutes(routes)
 
-	var pu []*processorUnit
-	if len(a.Config.Processors) != 0 {
-		next, pu, err = a.startProcessors(next, a.Config.Processors)
+	if a.Config.Agent.APIAddress != "" {
+		server, err := a.startAPI(iu, ou)
 		if err != nil {
 			return err
 		}
-	}
-
-	iu, err := a.startInputs(next, a.Config.Inputs)
-	if err != nil {
-		return err
+		defer server.Close()
 	}
 
 	var wg sync.WaitGroup
@@ -155,35 +272,12 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 	}()
 
-	if au != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := a.runProcessors(apu)
-			if err != nil {
-				log.Printf("E! [agent] Error running processors: %v", err)
-			}
-		}()
-
+	for _, unit := range ru {
 		wg.Add(1)
-		go func() {
+		go func(unit *routeUnit) {
 			defer wg.Done()
-			err := a.runAggregators(startTime, au)
-			if err != nil {
-				log.Printf("E! [agent] Error running aggregators: %v", err)
-			}
-		}()
-	}
-
-	if pu != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := a.runProcessors(pu)
-			if err != nil {
-				log.Printf("E! [agent] Error running processors: %v", err)
-			}
-		}()
+			a.runProcessingSwitch(startTime, unit.src, unit.pu, unit.swapC)
+		}(unit)
 	}
 
 	wg.Add(1)
@@ -195,8 +289,14 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 	}()
 
+	a.serveReloads(ctx, iu, ou, ru)
+
 	wg.Wait()
 
+	// The state is saved once all plugins have stopped, so that it includes
+	// everything they processed.
+	a.saveState()
+
 	log.Printf("D! [agent] Stopped Successfully")
 	return err
 }
@@ -238,46 +338,131 @@ func (a *Agent) initPlugins() error {
 				output.Config.Name, err)
 		}
 	}
+
+	if a.state != nil {
+		a.state.restore(statefulPlugins(a.Config))
+	}
 	return nil
 }
 
 func (a *Agent) startInputs(
-	dst chan<- telegraf.Metric,
+	routes []*routeUnit,
 	inputs []*models.RunningInput,
 ) (*inputUnit, error) {
 	log.Printf("D! [agent] Starting service inputs")
 
+	unit := newInputUnit(routes)
+	for _, input := range inputs {
+		dst := a.routeInput(unit, input, routeDsts(routes, input))
+		err := a.startInput(dst, input)
+		if err != nil {
+			stopServiceInputs(unit.inputs)
+			return nil, err
+		}
+		unit.inputs = append(unit.inputs, input)
+	}
+
+	return unit, nil
+}
+
+// newInputUnit returns an input unit writing to the routes.
+func newInputUnit(routes []*routeUnit) *inputUnit {
 	unit := &inputUnit{
-		dst: dst,
+		inputDsts: make(map[*models.RunningInput]chan<- telegraf.Metric),
+		fanOuts:   make(map[*models.RunningInput]chan telegraf.Metric),
 	}
+	for _, ru := range routes {
+		unit.dsts = append(unit.dsts, ru.src)
+	}
+	return unit
+}
 
-	for _, input := range inputs {
-		if si, ok := input.Input.(telegraf.ServiceInput); ok {
-			// Service input plugins are not normally subject to timestamp
-			// rounding except for when precision is set on the input plugin.
-			//
-			// This only applies to the accumulator passed to Start(), the
-			// Gather() accumulator does apply rounding according to the
-			// precision and interval agent/plugin settings.
-			var interval time.Duration
-			var precision time.Duration
-			if input.Config.Precision != 0 {
-				precision = input.Config.Precision
-			}
+// routeDsts returns the channels of the routes the input is part of.
+func routeDsts(routes []*routeUnit, input *models.RunningInput) []chan<- telegraf.Metric {
+	var dsts []chan<- telegraf.Metric
+	for _, ru := range routes {
+		if ru.route.HasInput(input) {
+			dsts = append(dsts, ru.src)
+		}
+	}
+	return dsts
+}
 
-			acc := NewAccumulator(input, dst)
-			acc.SetPrecision(getPrecision(precision, interval))
+// routeInput sets and returns the channel for the input to write to.  When
+// the input is part of several routes the channel is fanned out to each.
+func (a *Agent) routeInput(
+	unit *inputUnit,
+	input *models.RunningInput,
+	dsts []chan<- telegraf.Metric,
+) chan<- telegraf.Metric {
+	if len(dsts) == 1 {
+		unit.inputDsts[input] = dsts[0]
+		return dsts[0]
+	}
 
-			err := si.Start(acc)
-			if err != nil {
-				stopServiceInputs(unit.inputs)
-				return nil, fmt.Errorf("starting input %s: %w", input.LogName(), err)
+	src := make(chan telegraf.Metric, 100)
+	unit.inputDsts[input] = src
+	unit.fanOuts[input] = src
+
+	unit.fanOutWg.Add(1)
+	go func() {
+		defer unit.fanOutWg.Done()
+		for metric := range src {
+			if len(dsts) == 0 {
+				metric.Drop()
+			}
+			for i, dst := range dsts {
+				if i == len(dsts)-1 {
+					dst <- metric
+				} else {
+					dst <- metric.Copy()
+				}
 			}
 		}
-		unit.inputs = append(unit.inputs, input)
+	}()
+	return src
+}
+
+// closeInputs closes the channels written to by the inputs, once all inputs
+// have stopped.
+func closeInputs(unit *inputUnit) {
+	for _, src := range unit.fanOuts {
+		close(src)
 	}
+	unit.fanOutWg.Wait()
 
-	return unit, nil
+	for _, dst := range unit.dsts {
+		close(dst)
+	}
+}
+
+// startInput calls Start on the input if it is a service input.
+func (a *Agent) startInput(
+	dst chan<- telegraf.Metric,
+	input *models.RunningInput,
+) error {
+	if si, ok := input.Input.(telegraf.ServiceInput); ok {
+		// Service input plugins are not normally subject to timestamp
+		// rounding except for when precision is set on the input plugin.
+		//
+		// This only applies to the accumulator passed to Start(), the
+		// Gather() accumulator does apply rounding according to the
+		// precision and interval agent/plugin settings.
+		var interval time.Duration
+		var precision time.Duration
+		if input.Config.Precision != 0 {
+			precision = input.Config.Precision
+		}
+
+		acc := NewAccumulator(input, dst)
+		acc.SetPrecision(getPrecision(precision, interval))
+
+		err := si.Start(acc)
+		if err != nil {
+			return fmt.Errorf("starting input %s: %w", input.LogName(), err)
+		}
+	}
+	return nil
 }
 
 // runInputs starts and triggers the periodic gather for Inputs.
@@ -289,69 +474,151 @@ func (a *Agent) runInputs(
 	startTime time.Time,
 	unit *inputUnit,
 ) error {
-	var wg sync.WaitGroup
+	unit.Lock()
+	unit.running = true
+	unit.ctx = ctx
+	unit.startTime = startTime
+	unit.loops = make(map[*models.RunningInput]*pluginLoop)
 	for _, input := range unit.inputs {
-		// Overwrite agent interval if this plugin has its own.
-		interval := a.Config.Agent.Interval.Duration
-		if input.Config.Interval != 0 {
-			interval = input.Config.Interval
-		}
+		a.runInput(unit, input)
+	}
+	unit.Unlock()
 
-		// Overwrite agent precision if this plugin has its own.
-		precision := a.Config.Agent.Precision.Duration
-		if input.Config.Precision != 0 {
-			precision = input.Config.Precision
-		}
+	<-ctx.Done()
 
-		// Overwrite agent collection_jitter if this plugin has its own.
-		jitter := a.Config.Agent.CollectionJitter.Duration
-		if input.Config.CollectionJitter != 0 {
-			jitter = input.Config.CollectionJitter
-		}
+	unit.Lock()
+	unit.running = false
+	unit.Unlock()
 
-		var ticker Ticker
-		if a.Config.Agent.RoundInterval {
-			ticker = NewAlignedTicker(startTime, interval, jitter)
-		} else {
-			ticker = NewUnalignedTicker(interval, jitter)
-		}
-		defer ticker.Stop()
+	unit.wg.Wait()
 
-		acc := NewAccumulator(input, unit.dst)
-		acc.SetPrecision(getPrecision(precision, interval))
+	log.Printf("D! [agent] Stopping service inputs")
+	unit.Lock()
+	stopServiceInputs(unit.inputs)
+	closeInputs(unit)
+	unit.Unlock()
+	log.Printf("D! [agent] Input channel closed")
 
-		wg.Add(1)
-		go func(input *models.RunningInput) {
-			defer wg.Done()
-			a.gatherLoop(ctx, acc, input, ticker, interval)
-		}(input)
+	return nil
+}
+
+// runInput starts the gather loop for an input.  Must be called with the unit
+// locked.
+func (a *Agent) runInput(unit *inputUnit, input *models.RunningInput) {
+	// Overwrite agent interval if this plugin has its own.
+	interval := a.Config.Agent.Interval.Duration
+	if input.Config.Interval != 0 {
+		interval = input.Config.Interval
 	}
 
-	wg.Wait()
+	// Overwrite agent precision if this plugin has its own.
+	precision := a.Config.Agent.Precision.Duration
+	if input.Config.Precision != 0 {
+		precision = input.Config.Precision
+	}
 
-	log.Printf("D! [agent] Stopping service inputs")
-	stopServiceInputs(unit.inputs)
+	// Overwrite agent collection_jitter if this plugin has its own.
+	jitter := a.Config.Agent.CollectionJitter.Duration
+	if input.Config.CollectionJitter != 0 {
+		jitter = input.Config.CollectionJitter
+	}
 
-	close(unit.dst)
-	log.Printf("D! [agent] Input channel closed")
+	var ticker Ticker
+	if a.Config.Agent.RoundInterval {
+		ticker = NewAlignedTicker(unit.startTime, interval, jitter)
+	} else {
+		ticker = NewUnalignedTicker(interval, jitter)
+	}
+
+	acc := NewAccumulator(input, unit.inputDsts[input])
+	acc.SetPrecision(getPrecision(precision, interval))
+
+	ctx, cancel := context.WithCancel(unit.ctx)
+	loop := newPluginLoop(cancel)
+	unit.loops[input] = loop
+
+	unit.wg.Add(1)
+	go func() {
+		defer unit.wg.Done()
+		defer close(loop.done)
+		defer ticker.Stop()
+		a.gatherLoop(ctx, acc, input, ticker, interval, loop.trigger)
+	}()
+}
+
+// addInput starts the input and adds it to a running input unit, writing to
+// the route channels.
+func (a *Agent) addInput(
+	unit *inputUnit,
+	input *models.RunningInput,
+	dsts []chan<- telegraf.Metric,
+) error {
+	unit.Lock()
+	defer unit.Unlock()
+
+	if !unit.running && unit.loops != nil {
+		return errors.New("agent is not running")
+	}
+
+	err := a.startInput(a.routeInput(unit, input, dsts), input)
+	if err != nil {
+		unit.unrouteInput(input)
+		return err
+	}
 
+	unit.inputs = append(unit.inputs, input)
+	if unit.running {
+		a.runInput(unit, input)
+	}
 	return nil
 }
 
+// removeInput stops the gather loop of the input, waiting for any ongoing
+// Gather to complete, and stops the input if it is a service input.
+func (a *Agent) removeInput(unit *inputUnit, input *models.RunningInput) {
+	unit.Lock()
+	for i, ri := range unit.inputs {
+		if ri == input {
+			unit.inputs = append(unit.inputs[:i], unit.inputs[i+1:]...)
+			break
+		}
+	}
+	loop := unit.loops[input]
+	delete(unit.loops, input)
+	unit.Unlock()
+
+	if loop != nil {
+		loop.stop()
+	}
+	stopServiceInputs([]*models.RunningInput{input})
+
+	unit.Lock()
+	unit.unrouteInput(input)
+	unit.Unlock()
+}
+
+// unrouteInput removes the channel of an input that no longer writes to it.
+// Must be called with the unit locked.
+func (unit *inputUnit) unrouteInput(input *models.RunningInput) {
+	if src, ok := unit.fanOuts[input]; ok {
+		close(src)
+		delete(unit.fanOuts, input)
+	}
+	delete(unit.inputDsts, input)
+}
+
 // testStartInputs is a variation of startInputs for use in --test and --once
 // mode.  It differs by logging Start errors and returning only plugins
 // successfully started.
 func (a *Agent) testStartInputs(
-	dst chan<- telegraf.Metric,
+	routes []*routeUnit,
 	inputs []*models.RunningInput,
 ) (*inputUnit, error) {
 	log.Printf("D! [agent] Starting service inputs")
 
-	unit := &inputUnit{
-		dst: dst,
-	}
-
+	unit := newInputUnit(routes)
 	for _, input := range inputs {
+		dst := a.routeInput(unit, input, routeDsts(routes, input))
 		if si, ok := input.Input.(telegraf.ServiceInput); ok {
 			// Service input plugins are not subject to timestamp rounding.
 			// This only applies to the accumulator passed to Start(), the
@@ -418,7 +685,7 @@ func (a *Agent) testRunInputs(
 				time.Sleep(500 * time.Millisecond)
 			}
 
-			acc := NewAccumulator(input, unit.dst)
+			acc := NewAccumulator(input, unit.inputDsts[input])
 			acc.SetPrecision(getPrecision(precision, interval))
 
 			if err := input.Input.Gather(acc); err != nil {
@@ -433,7 +700,7 @@ func (a *Agent) testRunInputs(
 	log.Printf("D! [agent] Stopping service inputs")
 	stopServiceInputs(unit.inputs)
 
-	close(unit.dst)
+	closeInputs(unit)
 	log.Printf("D! [agent] Input channel closed")
 	return nil
 }
@@ -448,13 +715,15 @@ func stopServiceInputs(inputs []*models.RunningInput) {
 }
 
 // gather runs an input's gather function periodically until the context is
-// done.
+// done.  A gather can also be triggered, the result is sent on the channel
+// received.
 func (a *Agent) gatherLoop(
 	ctx context.Context,
 	acc telegraf.Accumulator,
 	input *models.RunningInput,
 	ticker Ticker,
 	interval time.Duration,
+	trigger <-chan chan error,
 ) {
 	defer panicRecover(input)
 
@@ -465,6 +734,12 @@ func (a *Agent) gatherLoop(
 			if err != nil {
 				acc.AddError(err)
 			}
+		case reply := <-trigger:
+			err := a.gatherOnce(acc, input, ticker, interval)
+			if err != nil {
+				acc.AddError(err)
+			}
+			reply <- err
 		case <-ctx.Done():
 			return
 		}
@@ -504,6 +779,30 @@ func (a *Agent) gatherOnce(
 	}
 }
 
+// startRoutes sets up the processing of each route, writing to the channel
+// returned by dst for the route.
+func (a *Agent) startRoutes(
+	routes []*config.Route,
+	dst func(route *config.Route) chan<- telegraf.Metric,
+) ([]*routeUnit, error) {
+	units := make([]*routeUnit, 0, len(routes))
+	for _, route := range routes {
+		pu, err := a.startProcessing(dst(route),
+			route.Processors, route.AggProcessors, route.Aggregators)
+		if err != nil {
+			return nil, err
+		}
+
+		units = append(units, &routeUnit{
+			route: route,
+			src:   make(chan telegraf.Metric, 100),
+			pu:    pu,
+			swapC: make(chan *processingSwap),
+		})
+	}
+	return units, nil
+}
+
 // startProcessors sets up the processor chain and calls Start on all
 // processors.  If an error occurs any started processors are Stopped.
 func (a *Agent) startProcessors(
@@ -597,7 +896,7 @@ func (a *Agent) runAggregators(
 
 	// Before calling Add, initialize the aggregation window.  This ensures
 	// that any metric created after start time will be aggregated.
-	for _, agg := range a.Config.Aggregators {
+	for _, agg := range unit.aggregators {
 		since, until := updateWindow(startTime, a.Config.Agent.RoundInterval, agg.Period())
 		agg.UpdateWindow(since, until)
 	}
@@ -608,7 +907,7 @@ func (a *Agent) runAggregators(
 		defer wg.Done()
 		for metric := range unit.src {
 			var dropOriginal bool
-			for _, agg := range a.Config.Aggregators {
+			for _, agg := range unit.aggregators {
 				if ok := agg.Add(metric); ok {
 					dropOriginal = true
 				}
@@ -623,7 +922,7 @@ func (a *Agent) runAggregators(
 		cancel()
 	}()
 
-	for _, agg := range a.Config.Aggregators {
+	for _, agg := range unit.aggregators {
 		wg.Add(1)
 		go func(agg *models.RunningAggregator) {
 			defer wg.Done()
@@ -693,22 +992,34 @@ func (a *Agent) push(
 func (a *Agent) startOutputs(
 	ctx context.Context,
 	outputs []*models.RunningOutput,
-) (chan<- telegraf.Metric, *outputUnit, error) {
-	src := make(chan telegraf.Metric, 100)
-	unit := &outputUnit{src: src}
+) (*outputUnit, error) {
+	unit := &outputUnit{}
 	for _, output := range outputs {
 		err := a.connectOutput(ctx, output)
 		if err != nil {
 			for _, output := range unit.outputs {
 				output.Close()
 			}
-			return nil, nil, fmt.Errorf("connecting output %s: %w", output.LogName(), err)
+			return nil, fmt.Errorf("connecting output %s: %w", output.LogName(), err)
 		}
 
 		unit.outputs = append(unit.outputs, output)
 	}
 
-	return src, unit, nil
+	return unit, nil
+}
+
+// addSource adds a source channel for the outputs of the route.  Sources must
+// be added before the unit is run.
+func (unit *outputUnit) addSource(route *config.Route) chan<- telegraf.Metric {
+	src := make(chan telegraf.Metric, 100)
+	outputs := make([]*models.RunningOutput, len(route.Outputs))
+	copy(outputs, route.Outputs)
+	unit.sources = append(unit.sources, &outputSource{
+		src:     src,
+		outputs: outputs,
+	})
+	return src
 }
 
 // connectOutputs connects to all outputs.
@@ -739,61 +1050,150 @@ func (a *Agent) connectOutput(ctx context.Context, output *models.RunningOutput)
 func (a *Agent) runOutputs(
 	unit *outputUnit,
 ) error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Start flush loops
+	unit.Lock()
+	unit.running = true
+	unit.ctx = ctx
+	unit.loops = make(map[*models.RunningOutput]*pluginLoop)
+	for _, output := range unit.outputs {
+		a.runOutput(unit, output)
+	}
+	unit.Unlock()
+
 	var wg sync.WaitGroup
+	for _, source := range unit.sources {
+		wg.Add(1)
+		go func(source *outputSource) {
+			defer wg.Done()
+			for metric := range source.src {
+				unit.RLock()
+				if len(source.outputs) == 0 {
+					metric.Drop()
+				}
+				for i, output := range source.outputs {
+					if i == len(source.outputs)-1 {
+						output.AddMetric(metric)
+					} else {
+						output.AddMetric(metric.Copy())
+					}
+				}
+				unit.RUnlock()
+			}
+		}(source)
+	}
+	wg.Wait()
+
+	log.Println("I! [agent] Hang on, flushing any cached metrics before shutdown")
+	unit.Lock()
+	unit.running = false
+	unit.Unlock()
+
+	cancel()
+	unit.wg.Wait()
+
+	unit.Lock()
+	for _, output := range unit.outputs {
+		output.Close()
+	}
+	unit.Unlock()
+
+	return nil
+}
 
-	// Start flush loop
+// runOutput starts the flush loop for an output.  Must be called with the
+// unit locked.
+func (a *Agent) runOutput(unit *outputUnit, output *models.RunningOutput) {
+	// Overwrite agent flush_interval if this plugin has its own.
 	interval := a.Config.Agent.FlushInterval.Duration
+	if output.Config.FlushInterval != 0 {
+		interval = output.Config.FlushInterval
+	}
+
+	// Overwrite agent flush_jitter if this plugin has its own.
 	jitter := a.Config.Agent.FlushJitter.Duration
+	if output.Config.FlushJitter != 0 {
+		jitter = output.Config.FlushJitter
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(unit.ctx)
+	loop := newPluginLoop(cancel)
+	unit.loops[output] = loop
 
-	for _, output := range unit.outputs {
-		interval := interval
-		// Overwrite agent flush_interval if this plugin has its own.
-		if output.Config.FlushInterval != 0 {
-			interval = output.Config.FlushInterval
-		}
+	// Waiting for the rate limits of the output is interrupted once the loop
+	// is stopped, so that a write does not delay stopping or a reload.
+	go func() {
+		<-ctx.Done()
+		output.Stop()
+	}()
 
-		jitter := jitter
-		// Overwrite agent flush_jitter if this plugin has its own.
-		if output.Config.FlushJitter != 0 {
-			jitter = output.Config.FlushJitter
-		}
+	unit.wg.Add(1)
+	go func() {
+		defer unit.wg.Done()
+		defer close(loop.done)
 
-		wg.Add(1)
-		go func(output *models.RunningOutput) {
-			defer wg.Done()
+		ticker := NewRollingTicker(interval, jitter)
+		defer ticker.Stop()
 
-			ticker := NewRollingTicker(interval, jitter)
-			defer ticker.Stop()
+		a.flushLoop(ctx, output, ticker, loop.trigger)
+	}()
+}
 
-			a.flushLoop(ctx, output, ticker)
-		}(output)
+// addOutput adds a connected output to a running output unit, receiving
+// metrics from the routes it is part of.  The routes are in the order of the
+// unit's sources.
+func (a *Agent) addOutput(
+	unit *outputUnit,
+	output *models.RunningOutput,
+	routes []*config.Route,
+) error {
+	unit.Lock()
+	defer unit.Unlock()
+
+	if !unit.running && unit.loops != nil {
+		return errors.New("agent is not running")
 	}
 
-	for metric := range unit.src {
-		for i, output := range unit.outputs {
-			if i == len(a.Config.Outputs)-1 {
-				output.AddMetric(metric)
-			} else {
-				output.AddMetric(metric.Copy())
-			}
+	for i, route := range routes {
+		if route.HasOutput(output) {
+			source := unit.sources[i]
+			source.outputs = append(source.outputs, output)
 		}
 	}
+	unit.outputs = append(unit.outputs, output)
+	if unit.running {
+		a.runOutput(unit, output)
+	}
+	return nil
+}
 
-	log.Println("I! [agent] Hang on, flushing any cached metrics before shutdown")
-	cancel()
-	wg.Wait()
+// removeOutput stops sending metrics to the output, flushes it one last time
+// and closes it.
+func (a *Agent) removeOutput(unit *outputUnit, output *models.RunningOutput) {
+	unit.Lock()
+	unit.outputs = removeOutputFrom(unit.outputs, output)
+	for _, source := range unit.sources {
+		source.outputs = removeOutputFrom(source.outputs, output)
+	}
+	loop := unit.loops[output]
+	delete(unit.loops, output)
+	unit.Unlock()
 
-	return nil
+	if loop != nil {
+		loop.stop()
+	}
+	output.Close()
 }
 
 // flushLoop runs an output's flush function periodically until the context is
-// done.
+// done.  A flush can also be triggered, the result is sent on the channel
+// received.
 func (a *Agent) flushLoop(
 	ctx context.Context,
 	output *models.RunningOutput,
 	ticker Ticker,
+	trigger <-chan chan error,
 ) {
 	logError := func(err error) {
 		if err != nil {
@@ -801,11 +1201,33 @@ func (a *Agent) flushLoop(
 		}
 	}
 
+	// Scheduled flushes are skipped while the output is backing off after a
+	// failure; flushes that are requested are always attempted.
+	scheduledFlush := func(writeFunc func() error) {
+		if !output.WriteAllowed() {
+			failures, open := output.Health()
+			if open {
+				log.Printf("D! [agent] Skipping flush of %s: circuit breaker open", output.LogName())
+			} else {
+				log.Printf("D! [agent] Skipping flush of %s: backing off after %d failures",
+					output.LogName(), failures)
+			}
+			return
+		}
+		logError(a.flushOnce(output, ticker, writeFunc))
+	}
+
 	// watch for flush requests
 	flushRequested := make(chan os.Signal, 1)
 	watchForFlushSignal(flushRequested)
 	defer stopListeningForFlushSignal(flushRequested)
 
+	// Metrics restored from the buffer log are written right away instead of
+	// waiting for the first flush interval.
+	if output.BufferLength() > 0 {
+		logError(a.flushOnce(output, ticker, output.Write))
+	}
+
 	for {
 		// Favor shutdown over other methods.
 		select {
@@ -820,16 +1242,20 @@ func (a *Agent) flushLoop(
 			logError(a.flushOnce(output, ticker, output.Write))
 			return
 		case <-ticker.Elapsed():
-			logError(a.flushOnce(output, ticker, output.Write))
+			scheduledFlush(output.Write)
 		case <-flushRequested:
 			logError(a.flushOnce(output, ticker, output.Write))
+		case reply := <-trigger:
+			err := a.flushOnce(output, ticker, output.Write)
+			logError(err)
+			reply <- err
 		case <-output.BatchReady:
 			// Favor the ticker over batch ready
 			select {
 			case <-ticker.Elapsed():
-				logError(a.flushOnce(output, ticker, output.Write))
+				scheduledFlush(output.Write)
 			default:
-				logError(a.flushOnce(output, ticker, output.WriteBatch))
+				scheduledFlush(output.WriteBatch)
 			}
 		}
 	}
@@ -898,77 +1324,49 @@ func (a *Agent) Test(ctx context.Context, wait time.Duration) error {
 // outputF.  After gathering pauses for the wait duration to allow service
 // inputs to run.
 func (a *Agent) test(ctx context.Context, wait time.Duration, outputC chan<- telegraf.Metric) error {
+	routes, err := a.Config.Routes()
+	if err != nil {
+		return err
+	}
+
 	log.Printf("D! [agent] Initializing plugins")
-	err := a.initPlugins()
+	err = a.initPlugins()
 	if err != nil {
 		return err
 	}
 
 	startTime := time.Now()
 
-	next := outputC
-
-	var apu []*processorUnit
-	var au *aggregatorUnit
-	if len(a.Config.Aggregators) != 0 {
-		procC := next
-		if len(a.Config.AggProcessors) != 0 {
-			procC, apu, err = a.startProcessors(next, a.Config.AggProcessors)
-			if err != nil {
-				return err
+	// Each route writes to its own channel, merged into the output channel.
+	var mergeWg sync.WaitGroup
+	ru, err := a.startRoutes(routes, func(*config.Route) chan<- telegraf.Metric {
+		dst := make(chan telegraf.Metric, 100)
+		mergeWg.Add(1)
+		go func() {
+			defer mergeWg.Done()
+			for metric := range dst {
+				outputC <- metric
 			}
-		}
-
-		next, au, err = a.startAggregators(procC, next, a.Config.Aggregators)
-		if err != nil {
-			return err
-		}
-	}
-
-	var pu []*processorUnit
-	if len(a.Config.Processors) != 0 {
-		next, pu, err = a.startProcessors(next, a.Config.Processors)
-		if err != nil {
-			return err
-		}
+		}()
+		return dst
+	})
+	if err != nil {
+		return err
 	}
 
-	iu, err := a.testStartInputs(next, a.Config.Inputs)
+	iu, err := a.testStartInputs(ru, a.Config.Inputs)
 	if err != nil {
 		return err
 	}
 
 	var wg sync.WaitGroup
 
-	if au != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := a.runProcessors(apu)
-			if err != nil {
-				log.Printf("E! [agent] Error running processors: %v", err)
-			}
-		}()
-
+	for _, unit := range ru {
 		wg.Add(1)
-		go func() {
+		go func(unit *routeUnit) {
 			defer wg.Done()
-			err := a.runAggregators(startTime, au)
-			if err != nil {
-				log.Printf("E! [agent] Error running aggregators: %v", err)
-			}
-		}()
-	}
-
-	if pu != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := a.runProcessors(pu)
-			if err != nil {
-				log.Printf("E! [agent] Error running processors: %v", err)
-			}
-		}()
+			a.runProcessingSwitch(startTime, unit.src, unit.pu, nil)
+		}(unit)
 	}
 
 	wg.Add(1)
@@ -981,6 +1379,8 @@ func (a *Agent) test(ctx context.Context, wait time.Duration, outputC chan<- tel
 	}()
 
 	wg.Wait()
+	mergeWg.Wait()
+	close(outputC)
 
 	log.Printf("D! [agent] Stopped Successfully")
 
@@ -1012,8 +1412,13 @@ func (a *Agent) Once(ctx context.Context, wait time.Duration) error {
 // outputF.  After gathering pauses for the wait duration to allow service
 // inputs to run.
 func (a *Agent) once(ctx context.Context, wait time.Duration) error {
+	routes, err := a.Config.Routes()
+	if err != nil {
+		return err
+	}
+
 	log.Printf("D! [agent] Initializing plugins")
-	err := a.initPlugins()
+	err = a.initPlugins()
 	if err != nil {
 		return err
 	}
@@ -1021,37 +1426,17 @@ func (a *Agent) once(ctx context.Context, wait time.Duration) error {
 	startTime := time.Now()
 
 	log.Printf("D! [agent] Connecting outputs")
-	next, ou, err := a.startOutputs(ctx, a.Config.Outputs)
+	ou, err := a.startOutputs(ctx, a.Config.Outputs)
 	if err != nil {
 		return err
 	}
 
-	var apu []*processorUnit
-	var au *aggregatorUnit
-	if len(a.Config.Aggregators) != 0 {
-		procC := next
-		if len(a.Config.AggProcessors) != 0 {
-			procC, apu, err = a.startProcessors(next, a.Config.AggProcessors)
-			if err != nil {
-				return err
-			}
-		}
-
-		next, au, err = a.startAggregators(procC, next, a.Config.Aggregators)
-		if err != nil {
-			return err
-		}
-	}
-
-	var pu []*processorUnit
-	if len(a.Config.Processors) != 0 {
-		next, pu, err = a.startProcessors(next, a.Config.Processors)
-		if err != nil {
-			return err
-		}
+	ru, err := a.startRoutes(routes, ou.addSource)
+	if err != nil {
+		return err
 	}
 
-	iu, err := a.testStartInputs(next, a.Config.Inputs)
+	iu, err := a.testStartInputs(ru, a.Config.Inputs)
 	if err != nil {
 		return err
 	}
@@ -1066,35 +1451,12 @@ func (a *Agent) once(ctx context.Context, wait time.Duration) error {
 		}
 	}()
 
-	if au != nil {
+	for _, unit := range ru {
 		wg.Add(1)
-		go func() {
+		go func(unit *routeUnit) {
 			defer wg.Done()
-			err := a.runProcessors(apu)
-			if err != nil {
-				log.Printf("E! [agent] Error running processors: %v", err)
-			}
-		}()
-
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := a.runAggregators(startTime, au)
-			if err != nil {
-				log.Printf("E! [agent] Error running aggregators: %v", err)
-			}
-		}()
-	}
-
-	if pu != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			err := a.runProcessors(pu)
-			if err != nil {
-				log.Printf("E! [agent] Error running processors: %v", err)
-			}
-		}()
+			a.runProcessingSwitch(startTime, unit.src, unit.pu, nil)
+		}(unit)
 	}
 
 	wg.Add(1)
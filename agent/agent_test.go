@@ -166,3 +166,84 @@ func TestWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestAgent_Routes(t *testing.T) {
+	c := config.NewConfig()
+	err := c.LoadConfigData([]byte(`
+[agent]
+  interval = "10ms"
+  flush_interval = "10ms"
+  omit_hostname = true
+
+[[route]]
+  name = "app"
+  inputs = ["app", "shared"]
+  outputs = ["app"]
+
+[[inputs.reload_test]]
+  alias = "app"
+  name = "app"
+[[inputs.reload_test]]
+  alias = "shared"
+  name = "shared"
+[[inputs.reload_test]]
+  name = "other"
+
+[[outputs.reload_test]]
+  alias = "app"
+[[outputs.reload_test]]
+`))
+	require.NoError(t, err)
+	app := c.Outputs[0].Output.(*reloadOutput)
+	other := c.Outputs[1].Output.(*reloadOutput)
+
+	// The shared input is only part of the app route, so the default route
+	// only has the other input.
+	_, stop := runReloadAgent(t, c)
+	require.Eventually(t, func() bool {
+		return app.hasMetric("app") && app.hasMetric("shared") && other.hasMetric("other")
+	}, 5*time.Second, 10*time.Millisecond)
+	stop()
+
+	require.False(t, app.hasMetric("other"))
+	require.False(t, other.hasMetric("app"))
+	require.False(t, other.hasMetric("shared"))
+}
+
+func TestAgent_RoutesFanOut(t *testing.T) {
+	c := config.NewConfig()
+	err := c.LoadConfigData([]byte(`
+[agent]
+  interval = "10ms"
+  flush_interval = "10ms"
+  omit_hostname = true
+
+[[route]]
+  name = "a"
+  inputs = ["reload_test"]
+  outputs = ["a"]
+
+[[route]]
+  name = "b"
+  inputs = ["reload_test"]
+  outputs = ["b"]
+
+[[inputs.reload_test]]
+  name = "shared"
+
+[[outputs.reload_test]]
+  alias = "a"
+[[outputs.reload_test]]
+  alias = "b"
+`))
+	require.NoError(t, err)
+	a := c.Outputs[0].Output.(*reloadOutput)
+	b := c.Outputs[1].Output.(*reloadOutput)
+
+	_, stop := runReloadAgent(t, c)
+	defer stop()
+
+	require.Eventually(t, func() bool {
+		return a.hasMetric("shared") && b.hasMetric("shared")
+	}, 5*time.Second, 10*time.Millisecond)
+}
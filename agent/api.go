@@ -0,0 +1,416 @@
+package agent
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/models"
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+)
+
+// api serves the HTTP management API of a running agent:
+//
+//   GET  /plugins              plugins and the routes they are part of
+//   GET  /inputs               status of the inputs
+//   POST /inputs/<ref>/gather  gather the referred inputs once
+//   GET  /outputs              status of the outputs
+//   POST /outputs/<ref>/flush  flush the referred outputs
+//   POST /flush                flush all outputs
+//
+// Plugins are referred to by their alias, or by their name if they have no
+// alias, the same as in a [[route]].
+type api struct {
+	agent *Agent
+	iu    *inputUnit
+	ou    *outputUnit
+}
+
+type apiPlugin struct {
+	Name   string   `json:"name"`
+	Alias  string   `json:"alias,omitempty"`
+	ID     string   `json:"id"`
+	Routes []string `json:"routes"`
+}
+
+type apiPlugins struct {
+	Inputs      []apiPlugin `json:"inputs"`
+	Processors  []apiPlugin `json:"processors"`
+	Aggregators []apiPlugin `json:"aggregators"`
+	Outputs     []apiPlugin `json:"outputs"`
+}
+
+type apiInput struct {
+	Name             string     `json:"name"`
+	Alias            string     `json:"alias,omitempty"`
+	MetricsGathered  int64      `json:"metrics_gathered"`
+	LastGather       *time.Time `json:"last_gather,omitempty"`
+	LastGatherTimeNs int64      `json:"last_gather_time_ns"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastErrorTime    *time.Time `json:"last_error_time,omitempty"`
+}
+
+type apiOutput struct {
+	Name                string     `json:"name"`
+	Alias               string     `json:"alias,omitempty"`
+	BufferLength        int        `json:"buffer_length"`
+	BufferLimit         int        `json:"buffer_limit"`
+	MetricsFiltered     int64      `json:"metrics_filtered"`
+	LastWrite           *time.Time `json:"last_write,omitempty"`
+	LastWriteTimeNs     int64      `json:"last_write_time_ns"`
+	LastWriteError      string     `json:"last_write_error,omitempty"`
+	LastWriteErrorTime  *time.Time `json:"last_write_error_time,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorTime       *time.Time `json:"last_error_time,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CircuitOpen         bool       `json:"circuit_open"`
+}
+
+// apiResult is the result of a triggered gather or flush of a plugin.
+type apiResult struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// startAPI serves the management API on the configured address until the
+// returned server is closed.
+func (a *Agent) startAPI(iu *inputUnit, ou *outputUnit) (*http.Server, error) {
+	conf := a.Config.Agent
+	serverConfig := &tlsint.ServerConfig{
+		TLSCert:           conf.APITLSCert,
+		TLSKey:            conf.APITLSKey,
+		TLSAllowedCACerts: conf.APITLSAllowedCACerts,
+	}
+	tlsConfig, err := serverConfig.TLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("API TLS configuration: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", apiListenAddress(conf.APIAddress))
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	api := &api{agent: a, iu: iu, ou: ou}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/plugins", api.servePlugins)
+	mux.HandleFunc("/inputs", api.serveInputs)
+	mux.HandleFunc("/inputs/", api.serveGather)
+	mux.HandleFunc("/outputs", api.serveOutputs)
+	mux.HandleFunc("/outputs/", api.serveFlush)
+	mux.HandleFunc("/flush", api.serveFlush)
+
+	authHandler := internal.AuthHandler(conf.APIBasicUsername, conf.APIBasicPassword, "telegraf",
+		func(_ http.ResponseWriter) {})
+	server := &http.Server{Handler: authHandler(mux)}
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			log.Printf("E! [agent] Error serving API: %v", err)
+		}
+	}()
+
+	log.Printf("I! [agent] Serving API on %s", listener.Addr())
+	return server, nil
+}
+
+// apiListenAddress returns the address to listen on, on localhost if the
+// address has no host.
+func apiListenAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host != "" {
+		return address
+	}
+	return net.JoinHostPort("localhost", port)
+}
+
+func (a *Agent) setRoutes(routes []*config.Route) {
+	a.routesMu.Lock()
+	a.routes = routes
+	a.routesMu.Unlock()
+}
+
+func (a *Agent) getRoutes() []*config.Route {
+	a.routesMu.Lock()
+	defer a.routesMu.Unlock()
+	return a.routes
+}
+
+func (api *api) servePlugins(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	routes := api.agent.getRoutes()
+	plugins := apiPlugins{
+		Inputs:      []apiPlugin{},
+		Processors:  []apiPlugin{},
+		Aggregators: []apiPlugin{},
+		Outputs:     []apiPlugin{},
+	}
+
+	api.iu.Lock()
+	for _, input := range api.iu.inputs {
+		var names []string
+		for _, route := range routes {
+			if route.HasInput(input) {
+				names = append(names, route.Name)
+			}
+		}
+		plugins.Inputs = append(plugins.Inputs, apiPlugin{
+			Name:   input.Config.Name,
+			Alias:  input.Config.Alias,
+			ID:     input.ID,
+			Routes: names,
+		})
+	}
+	api.iu.Unlock()
+
+	for _, route := range routes {
+		for _, processor := range route.Processors {
+			plugins.Processors = append(plugins.Processors, apiPlugin{
+				Name:   processor.Config.Name,
+				Alias:  processor.Config.Alias,
+				ID:     processor.ID,
+				Routes: []string{route.Name},
+			})
+		}
+		for _, aggregator := range route.Aggregators {
+			plugins.Aggregators = append(plugins.Aggregators, apiPlugin{
+				Name:   aggregator.Config.Name,
+				Alias:  aggregator.Config.Alias,
+				ID:     aggregator.ID,
+				Routes: []string{route.Name},
+			})
+		}
+	}
+
+	api.ou.RLock()
+	for _, output := range api.ou.outputs {
+		var names []string
+		for _, route := range routes {
+			if route.HasOutput(output) {
+				names = append(names, route.Name)
+			}
+		}
+		plugins.Outputs = append(plugins.Outputs, apiPlugin{
+			Name:   output.Config.Name,
+			Alias:  output.Config.Alias,
+			ID:     output.ID,
+			Routes: names,
+		})
+	}
+	api.ou.RUnlock()
+
+	writeJSON(w, http.StatusOK, plugins)
+}
+
+func (api *api) serveInputs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	api.iu.Lock()
+	inputs := make([]*models.RunningInput, len(api.iu.inputs))
+	copy(inputs, api.iu.inputs)
+	api.iu.Unlock()
+
+	status := make([]apiInput, 0, len(inputs))
+	for _, input := range inputs {
+		lastGather, gatherTime := input.LastGather()
+		lastErr, lastErrTime := input.LastError()
+		status = append(status, apiInput{
+			Name:             input.Config.Name,
+			Alias:            input.Config.Alias,
+			MetricsGathered:  input.MetricsGathered.Get(),
+			LastGather:       optionalTime(lastGather),
+			LastGatherTimeNs: gatherTime.Nanoseconds(),
+			LastError:        lastErr,
+			LastErrorTime:    optionalTime(lastErrTime),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (api *api) serveOutputs(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	api.ou.RLock()
+	outputs := make([]*models.RunningOutput, len(api.ou.outputs))
+	copy(outputs, api.ou.outputs)
+	api.ou.RUnlock()
+
+	status := make([]apiOutput, 0, len(outputs))
+	for _, output := range outputs {
+		lastWrite, writeTime := output.LastWrite()
+		lastWriteErr, lastWriteErrTime := output.LastWriteError()
+		lastErr, lastErrTime := output.LastError()
+		failures, open := output.Health()
+		status = append(status, apiOutput{
+			Name:                output.Config.Name,
+			Alias:               output.Config.Alias,
+			BufferLength:        output.BufferLength(),
+			BufferLimit:         output.MetricBufferLimit,
+			MetricsFiltered:     output.MetricsFiltered.Get(),
+			LastWrite:           optionalTime(lastWrite),
+			LastWriteTimeNs:     writeTime.Nanoseconds(),
+			LastWriteError:      lastWriteErr,
+			LastWriteErrorTime:  optionalTime(lastWriteErrTime),
+			LastError:           lastErr,
+			LastErrorTime:       optionalTime(lastErrTime),
+			ConsecutiveFailures: failures,
+			CircuitOpen:         open,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// serveGather gathers the inputs referred to by a /inputs/<ref>/gather
+// request once and waits for the gathers to complete.
+func (api *api) serveGather(w http.ResponseWriter, r *http.Request) {
+	ref, ok := pluginAction(r.URL.Path, "/inputs/", "/gather")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var results []apiResult
+	var loops []*pluginLoop
+	api.iu.Lock()
+	for _, input := range api.iu.inputs {
+		if !config.RefersTo(ref, input.Config.Name, input.Config.Alias) {
+			continue
+		}
+		results = append(results, apiResult{Name: input.Config.Name, Alias: input.Config.Alias})
+		loops = append(loops, api.iu.loops[input])
+	}
+	api.iu.Unlock()
+
+	log.Printf("D! [agent] Gathering %q requested by API", ref)
+	api.runOnce(w, r, results, loops)
+}
+
+// serveFlush flushes the outputs referred to by a /outputs/<ref>/flush
+// request, or all outputs for a /flush request, and waits for the flushes to
+// complete.
+func (api *api) serveFlush(w http.ResponseWriter, r *http.Request) {
+	ref, ok := "", r.URL.Path == "/flush"
+	if !ok {
+		ref, ok = pluginAction(r.URL.Path, "/outputs/", "/flush")
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var results []apiResult
+	var loops []*pluginLoop
+	api.ou.RLock()
+	for _, output := range api.ou.outputs {
+		if ref != "" && !config.RefersTo(ref, output.Config.Name, output.Config.Alias) {
+			continue
+		}
+		results = append(results, apiResult{Name: output.Config.Name, Alias: output.Config.Alias})
+		loops = append(loops, api.ou.loops[output])
+	}
+	api.ou.RUnlock()
+
+	if ref == "" {
+		log.Printf("D! [agent] Flushing outputs requested by API")
+	} else {
+		log.Printf("D! [agent] Flushing %q requested by API", ref)
+	}
+	api.runOnce(w, r, results, loops)
+}
+
+// runOnce runs the loops once concurrently and writes the results.  The
+// response status is 404 if there are no loops and 500 if any failed.
+func (api *api) runOnce(
+	w http.ResponseWriter,
+	r *http.Request,
+	results []apiResult,
+	loops []*pluginLoop,
+) {
+	if len(loops) == 0 {
+		writeJSON(w, http.StatusNotFound, []apiResult{})
+		return
+	}
+
+	errs := make([]chan error, len(loops))
+	for i, loop := range loops {
+		errs[i] = make(chan error, 1)
+		go func(loop *pluginLoop, errC chan<- error) {
+			if loop == nil {
+				errC <- errLoopStopped
+				return
+			}
+			errC <- loop.runOnce(r.Context())
+		}(loop, errs[i])
+	}
+
+	status := http.StatusOK
+	for i := range results {
+		if err := <-errs[i]; err != nil {
+			results[i].Error = err.Error()
+			status = http.StatusInternalServerError
+		}
+	}
+	writeJSON(w, status, results)
+}
+
+// pluginAction returns the plugin reference of a <prefix><ref><suffix> path.
+func pluginAction(path, prefix, suffix string) (string, bool) {
+	if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, suffix) {
+		return "", false
+	}
+	ref := path[len(prefix) : len(path)-len(suffix)]
+	if ref == "" || strings.Contains(ref, "/") {
+		return "", false
+	}
+	return ref, true
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("E! [agent] Error writing API response: %v", err)
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
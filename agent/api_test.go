@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func getJSON(t *testing.T, url string, v interface{}) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func TestAgent_API(t *testing.T) {
+	address := freeAddress(t)
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+  alias = "a"
+[[outputs.reload_test]]
+`)
+	c.Agent.Interval.Duration = time.Hour
+	c.Agent.FlushInterval.Duration = time.Hour
+	c.Agent.APIAddress = address
+	output := c.Outputs[0].Output.(*reloadOutput)
+
+	_, stop := runReloadAgent(t, c)
+	defer stop()
+
+	url := "http://" + address
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(url + "/plugins")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var plugins apiPlugins
+	getJSON(t, url+"/plugins", &plugins)
+	require.Len(t, plugins.Inputs, 1)
+	require.Equal(t, "reload_test", plugins.Inputs[0].Name)
+	require.Equal(t, "a", plugins.Inputs[0].Alias)
+	require.Equal(t, []string{""}, plugins.Inputs[0].Routes)
+	require.Len(t, plugins.Outputs, 1)
+
+	resp, err := http.Post(url+"/inputs/a/gather", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var inputs []apiInput
+	getJSON(t, url+"/inputs", &inputs)
+	require.Len(t, inputs, 1)
+	require.NotNil(t, inputs[0].LastGather)
+
+	require.Eventually(t, func() bool {
+		var outputs []apiOutput
+		getJSON(t, url+"/outputs", &outputs)
+		return outputs[0].BufferLength > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp, err = http.Post(url+"/flush", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, output.hasMetric("a"))
+
+	var outputs []apiOutput
+	getJSON(t, url+"/outputs", &outputs)
+	require.Equal(t, 0, outputs[0].BufferLength)
+	require.NotNil(t, outputs[0].LastWrite)
+
+	resp, err = http.Post(url+"/inputs/missing/gather", "", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = http.Get(url + "/flush")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestAgent_APIBasicAuth(t *testing.T) {
+	address := freeAddress(t)
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+[[outputs.reload_test]]
+`)
+	c.Agent.Interval.Duration = time.Hour
+	c.Agent.FlushInterval.Duration = time.Hour
+	c.Agent.APIAddress = address
+	c.Agent.APIBasicUsername = "user"
+	c.Agent.APIBasicPassword = "secret"
+
+	_, stop := runReloadAgent(t, c)
+	defer stop()
+
+	get := func(username, password string) int {
+		req, err := http.NewRequest(http.MethodGet, "http://"+address+"/plugins", nil)
+		require.NoError(t, err)
+		if username != "" {
+			req.SetBasicAuth(username, password)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Eventually(t, func() bool {
+		return get("user", "secret") == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, http.StatusUnauthorized, get("", ""))
+	require.Equal(t, http.StatusUnauthorized, get("user", "wrong"))
+}
+
+func TestAPIListenAddress(t *testing.T) {
+	require.Equal(t, "localhost:8095", apiListenAddress(":8095"))
+	require.Equal(t, "0.0.0.0:8095", apiListenAddress("0.0.0.0:8095"))
+	require.Equal(t, "127.0.0.1:8095", apiListenAddress("127.0.0.1:8095"))
+}
+
+func TestPluginAction(t *testing.T) {
+	ref, ok := pluginAction("/inputs/cpu/gather", "/inputs/", "/gather")
+	require.True(t, ok)
+	require.Equal(t, "cpu", ref)
+
+	_, ok = pluginAction("/inputs//gather", "/inputs/", "/gather")
+	require.False(t, ok)
+	_, ok = pluginAction("/inputs/a/b/gather", "/inputs/", "/gather")
+	require.False(t, ok)
+	_, ok = pluginAction("/inputs/cpu", "/inputs/", "/gather")
+	require.False(t, ok)
+}
@@ -0,0 +1,600 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+)
+
+// ErrRestartRequired is returned by Reload when the new configuration cannot
+// be applied to the running agent, such as when the agent settings or global
+// tags have changed.
+var ErrRestartRequired = errors.New("configuration change requires a restart")
+
+type reloadRequest struct {
+	config *config.Config
+	err    chan error
+}
+
+// processingSwap requests that the processing unit be replaced by a new
+// chain of processors and aggregators.
+type processingSwap struct {
+	processors    models.RunningProcessors
+	aggProcessors models.RunningProcessors
+	aggregators   []*models.RunningAggregator
+	err           chan error
+}
+
+// Reload applies a new configuration to the running agent.  Plugins with an
+// unchanged configuration keep running undisturbed, removed plugins are
+// stopped and added plugins are started.  If the processors or aggregators of
+// a route change the processing chain of the route is drained and replaced.
+//
+// If a new plugin cannot be initialized no changes are made and an error is
+// returned.  If the new processing of a route cannot be started the route
+// keeps its previous processing, the inputs and outputs are left unchanged,
+// and an error is returned; the other routes keep their new processing.
+// Errors starting new inputs and outputs are returned once the rest of the
+// config is applied.  If the agent settings, global tags or routes differ
+// ErrRestartRequired is returned.  Reload must only be called while Run is
+// running.
+func (a *Agent) Reload(ctx context.Context, c *config.Config) error {
+	req := &reloadRequest{
+		config: c,
+		err:    make(chan error, 1),
+	}
+
+	select {
+	case a.reloadC <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return <-req.err
+}
+
+// serveReloads applies reload requests until the context is done.  The state
+// file is saved periodically from the same loop, so that it is never saved
+// while the plugins are being replaced.
+func (a *Agent) serveReloads(
+	ctx context.Context,
+	iu *inputUnit,
+	ou *outputUnit,
+	ru []*routeUnit,
+) {
+	saveC, stop := a.statefileTicker()
+	defer stop()
+
+	for {
+		select {
+		case req := <-a.reloadC:
+			req.err <- a.reload(ctx, req.config, iu, ou, ru)
+		case <-saveC:
+			a.saveState()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *Agent) reload(
+	ctx context.Context,
+	c *config.Config,
+	iu *inputUnit,
+	ou *outputUnit,
+	ru []*routeUnit,
+) error {
+	if !reflect.DeepEqual(a.Config.Agent, c.Agent) ||
+		!reflect.DeepEqual(a.Config.Tags, c.Tags) ||
+		!reflect.DeepEqual(a.Config.RouteConfigs, c.RouteConfigs) {
+		return ErrRestartRequired
+	}
+
+	// Reuse the running instance of each plugin with an unchanged config.
+	var addedInputs, removedInputs []*models.RunningInput
+	match, removed := matchPlugins(inputIDs(a.Config.Inputs), inputIDs(c.Inputs))
+	for i, j := range match {
+		if j < 0 {
+			addedInputs = append(addedInputs, c.Inputs[i])
+			continue
+		}
+		c.Inputs[i] = a.Config.Inputs[j]
+	}
+	for _, j := range removed {
+		removedInputs = append(removedInputs, a.Config.Inputs[j])
+	}
+
+	var addedOutputs, removedOutputs []*models.RunningOutput
+	match, removed = matchPlugins(outputIDs(a.Config.Outputs), outputIDs(c.Outputs))
+	for i, j := range match {
+		if j < 0 {
+			addedOutputs = append(addedOutputs, c.Outputs[i])
+			continue
+		}
+		c.Outputs[i] = a.Config.Outputs[j]
+	}
+	for _, j := range removed {
+		removedOutputs = append(removedOutputs, a.Config.Outputs[j])
+	}
+
+	// Routes are resolved with the reused plugins.  As the route configs are
+	// unchanged the routes are in the same order as the running routes.
+	routes, err := c.Routes()
+	if err != nil {
+		return err
+	}
+
+	// The processing chain of a route is replaced as a whole when any part
+	// changes, as the order of processors is significant.
+	var changedRoutes []int
+	for i, route := range routes {
+		running := ru[i].route
+		if equalIDs(processorIDs(running.Processors), processorIDs(route.Processors)) &&
+			equalIDs(processorIDs(running.AggProcessors), processorIDs(route.AggProcessors)) &&
+			equalIDs(aggregatorIDs(running.Aggregators), aggregatorIDs(route.Aggregators)) {
+			route.Processors = running.Processors
+			route.AggProcessors = running.AggProcessors
+			route.Aggregators = running.Aggregators
+			continue
+		}
+		changedRoutes = append(changedRoutes, i)
+	}
+
+	log.Printf("I! [agent] Reloading config: %d inputs added, %d removed; %d outputs added, %d removed; %d routes with changed processing",
+		len(addedInputs), len(removedInputs), len(addedOutputs), len(removedOutputs), len(changedRoutes))
+
+	// Initialize new plugins before making any change, so that a bad config
+	// leaves the agent as it was.  Outputs are initialized only after the
+	// outputs they replace have been closed.
+	for _, input := range addedInputs {
+		err := input.Init()
+		if err != nil {
+			return fmt.Errorf("could not initialize input %s: %v",
+				input.LogName(), err)
+		}
+	}
+	for _, i := range changedRoutes {
+		route := routes[i]
+		for _, processor := range route.Processors {
+			err := processor.Init()
+			if err != nil {
+				return fmt.Errorf("could not initialize processor %s: %v",
+					processor.Config.Name, err)
+			}
+		}
+		for _, aggregator := range route.Aggregators {
+			err := aggregator.Init()
+			if err != nil {
+				return fmt.Errorf("could not initialize aggregator %s: %v",
+					aggregator.Config.Name, err)
+			}
+		}
+		for _, processor := range route.AggProcessors {
+			err := processor.Init()
+			if err != nil {
+				return fmt.Errorf("could not initialize processor %s: %v",
+					processor.Config.Name, err)
+			}
+		}
+	}
+
+	// The state of added outputs is restored once they are initialized.
+	var addedOutputState []statefulPlugin
+	if a.state != nil {
+		addedOutputState = a.restoreAdded(c)
+	}
+
+	// The processing of the routes is replaced first, as it can fail once
+	// the new processors are started.  If it does, the inputs and outputs
+	// are left unchanged, and the routes replaced keep their new processing.
+	var errs []string
+	for _, i := range changedRoutes {
+		route := routes[i]
+		swap := &processingSwap{
+			processors:    route.Processors,
+			aggProcessors: route.AggProcessors,
+			aggregators:   route.Aggregators,
+			err:           make(chan error, 1),
+		}
+		ru[i].swapC <- swap
+		if err := <-swap.err; err != nil {
+			errs = append(errs, err.Error())
+			route.Processors = ru[i].route.Processors
+			route.AggProcessors = ru[i].route.AggProcessors
+			route.Aggregators = ru[i].route.Aggregators
+		}
+	}
+	if len(errs) != 0 {
+		for _, i := range changedRoutes {
+			running := *ru[i].route
+			running.Processors = routes[i].Processors
+			running.AggProcessors = routes[i].AggProcessors
+			running.Aggregators = routes[i].Aggregators
+			ru[i].route = &running
+		}
+		a.setRoutes(unitRoutes(ru))
+		a.setProcessingConfig(unitRoutes(ru))
+		return fmt.Errorf("reloading config: %s", strings.Join(errs, "; "))
+	}
+	for i, route := range routes {
+		ru[i].route = route
+	}
+	a.setRoutes(routes)
+
+	for _, input := range removedInputs {
+		log.Printf("D! [agent] Removing input %s", input.LogName())
+		a.removeInput(iu, input)
+	}
+
+	for _, output := range removedOutputs {
+		log.Printf("D! [agent] Removing output %s", output.LogName())
+		a.removeOutput(ou, output)
+	}
+
+	for _, output := range addedOutputs {
+		err := a.startReloadedOutput(ctx, ou, output, routes, addedOutputState)
+		if err != nil {
+			errs = append(errs, err.Error())
+			c.Outputs = removeOutputFrom(c.Outputs, output)
+		}
+	}
+
+	for _, input := range addedInputs {
+		log.Printf("D! [agent] Adding input %s", input.LogName())
+		err := a.addInput(iu, input, routeDsts(ru, input))
+		if err != nil {
+			errs = append(errs, err.Error())
+			c.Inputs = removeInputFrom(c.Inputs, input)
+		}
+	}
+
+	// Only the plugin lists are replaced; the agent settings are unchanged
+	// and may be in use by running plugins.
+	a.Config.Inputs = c.Inputs
+	a.Config.Outputs = c.Outputs
+	a.setProcessingConfig(routes)
+
+	if len(errs) != 0 {
+		return fmt.Errorf("reloading config: %s", strings.Join(errs, "; "))
+	}
+	log.Printf("I! [agent] Config reloaded")
+	return nil
+}
+
+// setProcessingConfig replaces the processors and aggregators of the agent
+// config with the ones of the routes.
+func (a *Agent) setProcessingConfig(routes []*config.Route) {
+	a.Config.Processors = nil
+	a.Config.AggProcessors = nil
+	a.Config.Aggregators = nil
+	for _, route := range routes {
+		a.Config.Processors = append(a.Config.Processors, route.Processors...)
+		a.Config.AggProcessors = append(a.Config.AggProcessors, route.AggProcessors...)
+		a.Config.Aggregators = append(a.Config.Aggregators, route.Aggregators...)
+	}
+}
+
+// unitRoutes returns the routes of the running route units.
+func unitRoutes(ru []*routeUnit) []*config.Route {
+	routes := make([]*config.Route, 0, len(ru))
+	for _, unit := range ru {
+		routes = append(routes, unit.route)
+	}
+	return routes
+}
+
+// restoreAdded restores the state of the plugins of the new config that are
+// not running, from the current state of the running plugins or else from the
+// state file.  The state of the processors of a changed route may miss the
+// last metrics they process before the route is swapped.  As outputs are
+// initialized later, the added outputs are returned instead.
+func (a *Agent) restoreAdded(c *config.Config) []statefulPlugin {
+	a.state.collect(statefulPlugins(a.Config))
+
+	running := make(map[telegraf.StatefulPlugin]bool)
+	for _, sp := range statefulPlugins(a.Config) {
+		running[sp.plugin] = true
+	}
+	var added, outputs []statefulPlugin
+	for _, sp := range statefulPlugins(c) {
+		switch {
+		case running[sp.plugin]:
+		case sp.kind == "outputs":
+			outputs = append(outputs, sp)
+		default:
+			added = append(added, sp)
+		}
+	}
+	a.state.restore(added)
+	return outputs
+}
+
+// startReloadedOutput initializes and connects a new output and adds it to
+// the running output unit.
+func (a *Agent) startReloadedOutput(
+	ctx context.Context,
+	unit *outputUnit,
+	output *models.RunningOutput,
+	routes []*config.Route,
+	state []statefulPlugin,
+) error {
+	log.Printf("D! [agent] Adding output %s", output.LogName())
+	err := output.Init()
+	if err != nil {
+		return fmt.Errorf("could not initialize output %s: %v",
+			output.Config.Name, err)
+	}
+
+	if p, ok := output.Output.(telegraf.StatefulPlugin); ok {
+		for _, sp := range state {
+			if sp.plugin == p {
+				a.state.restore([]statefulPlugin{sp})
+			}
+		}
+	}
+
+	err = a.connectOutput(ctx, output)
+	if err != nil {
+		output.Close()
+		return fmt.Errorf("connecting output %s: %w", output.LogName(), err)
+	}
+
+	err = a.addOutput(unit, output, routes)
+	if err != nil {
+		output.Close()
+		return err
+	}
+	return nil
+}
+
+// startProcessing sets up the processor and aggregator chain writing to dst
+// and calls Start on all processors.
+func (a *Agent) startProcessing(
+	dst chan<- telegraf.Metric,
+	processors models.RunningProcessors,
+	aggProcessors models.RunningProcessors,
+	aggregators []*models.RunningAggregator,
+) (*processingUnit, error) {
+	sink := make(chan telegraf.Metric, 100)
+	unit := &processingUnit{
+		sink:          sink,
+		dst:           dst,
+		processors:    processors,
+		aggProcessors: aggProcessors,
+		aggregators:   aggregators,
+	}
+
+	var err error
+	var next chan<- telegraf.Metric = sink
+	if len(aggregators) != 0 {
+		aggC := next
+		if len(aggProcessors) != 0 {
+			aggC, unit.apu, err = a.startProcessors(next, aggProcessors)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		next, unit.au, err = a.startAggregators(aggC, next, aggregators)
+		if err != nil {
+			stopProcessorUnits(unit.apu)
+			return nil, err
+		}
+	}
+
+	if len(processors) != 0 {
+		next, unit.pu, err = a.startProcessors(next, processors)
+		if err != nil {
+			stopProcessorUnits(unit.apu)
+			return nil, err
+		}
+	}
+
+	unit.src = next
+	return unit, nil
+}
+
+// stopProcessorUnits stops the processors of units that have been started
+// but not run.
+func stopProcessorUnits(units []*processorUnit) {
+	for _, u := range units {
+		u.processor.Stop()
+	}
+}
+
+// runProcessing runs the processing unit in the background until its source
+// channel is closed and all metrics have been forwarded.
+func (a *Agent) runProcessing(startTime time.Time, unit *processingUnit) {
+	if unit.au != nil {
+		unit.wg.Add(1)
+		go func() {
+			defer unit.wg.Done()
+			err := a.runProcessors(unit.apu)
+			if err != nil {
+				log.Printf("E! [agent] Error running processors: %v", err)
+			}
+		}()
+
+		unit.wg.Add(1)
+		go func() {
+			defer unit.wg.Done()
+			err := a.runAggregators(startTime, unit.au)
+			if err != nil {
+				log.Printf("E! [agent] Error running aggregators: %v", err)
+			}
+		}()
+	}
+
+	if unit.pu != nil {
+		unit.wg.Add(1)
+		go func() {
+			defer unit.wg.Done()
+			err := a.runProcessors(unit.pu)
+			if err != nil {
+				log.Printf("E! [agent] Error running processors: %v", err)
+			}
+		}()
+	}
+
+	unit.wg.Add(1)
+	go func() {
+		defer unit.wg.Done()
+		for metric := range unit.sink {
+			unit.dst <- metric
+		}
+	}()
+}
+
+// runProcessingSwitch forwards metrics from the inputs to the processing unit
+// and replaces the unit on request.  When the source channel is closed the
+// processing unit is drained and the outputs channel is closed.
+func (a *Agent) runProcessingSwitch(
+	startTime time.Time,
+	src <-chan telegraf.Metric,
+	unit *processingUnit,
+	swapC <-chan *processingSwap,
+) {
+	a.runProcessing(startTime, unit)
+
+	for {
+		select {
+		case metric, ok := <-src:
+			if !ok {
+				close(unit.src)
+				unit.wg.Wait()
+				close(unit.dst)
+				log.Printf("D! [agent] Processing channel closed")
+				return
+			}
+			unit.src <- metric
+		case swap := <-swapC:
+			// Drain the current chain so that aggregators push their final
+			// values and processors are stopped.
+			close(unit.src)
+			unit.wg.Wait()
+
+			next, err := a.startProcessing(unit.dst,
+				swap.processors, swap.aggProcessors, swap.aggregators)
+			if err != nil {
+				log.Printf("E! [agent] Error starting new processors, keeping previous: %v", err)
+				var restartErr error
+				next, restartErr = a.startProcessing(unit.dst,
+					unit.processors, unit.aggProcessors, unit.aggregators)
+				if restartErr != nil {
+					log.Printf("E! [agent] Error restarting previous processors, metrics will not be processed: %v", restartErr)
+					next, _ = a.startProcessing(unit.dst, nil, nil, nil)
+				}
+			}
+
+			a.runProcessing(time.Now(), next)
+			unit = next
+			swap.err <- err
+		}
+	}
+}
+
+// matchPlugins pairs each new plugin with an unpaired old plugin with the same
+// ID.  It returns for each new plugin the index of its old plugin, or -1 if
+// there is none, and the indexes of the old plugins left unpaired.
+func matchPlugins(oldIDs, newIDs []string) ([]int, []int) {
+	byID := make(map[string][]int)
+	for i, id := range oldIDs {
+		byID[id] = append(byID[id], i)
+	}
+
+	paired := make([]bool, len(oldIDs))
+	match := make([]int, len(newIDs))
+	for i, id := range newIDs {
+		match[i] = -1
+		if idx := byID[id]; len(idx) != 0 {
+			match[i] = idx[0]
+			paired[idx[0]] = true
+			byID[id] = idx[1:]
+		}
+	}
+
+	var unpaired []int
+	for i := range oldIDs {
+		if !paired[i] {
+			unpaired = append(unpaired, i)
+		}
+	}
+	return match, unpaired
+}
+
+func equalIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func inputIDs(inputs []*models.RunningInput) []string {
+	ids := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		ids = append(ids, input.ID)
+	}
+	return ids
+}
+
+func outputIDs(outputs []*models.RunningOutput) []string {
+	ids := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		ids = append(ids, output.ID)
+	}
+	return ids
+}
+
+// processorIDs returns the IDs of the processors in the order they are run.
+// Running processors are sorted in place, so the order of the slice is not
+// significant.
+func processorIDs(processors models.RunningProcessors) []string {
+	sorted := make(models.RunningProcessors, len(processors))
+	copy(sorted, processors)
+	sort.Stable(sorted)
+
+	ids := make([]string, 0, len(sorted))
+	for _, processor := range sorted {
+		ids = append(ids, processor.ID)
+	}
+	return ids
+}
+
+func aggregatorIDs(aggregators []*models.RunningAggregator) []string {
+	ids := make([]string, 0, len(aggregators))
+	for _, aggregator := range aggregators {
+		ids = append(ids, aggregator.ID)
+	}
+	return ids
+}
+
+func removeInputFrom(inputs []*models.RunningInput, input *models.RunningInput) []*models.RunningInput {
+	for i, ri := range inputs {
+		if ri == input {
+			return append(inputs[:i], inputs[i+1:]...)
+		}
+	}
+	return inputs
+}
+
+func removeOutputFrom(outputs []*models.RunningOutput, output *models.RunningOutput) []*models.RunningOutput {
+	for i, ro := range outputs {
+		if ro == output {
+			return append(outputs[:i], outputs[i+1:]...)
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,274 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadInput struct {
+	Name string `toml:"name"`
+}
+
+func (i *reloadInput) SampleConfig() string { return "" }
+func (i *reloadInput) Description() string  { return "" }
+func (i *reloadInput) Gather(acc telegraf.Accumulator) error {
+	acc.AddFields(i.Name, map[string]interface{}{"value": 42}, nil)
+	return nil
+}
+
+type reloadOutput struct {
+	sync.Mutex
+	metrics []telegraf.Metric
+	closed  bool
+}
+
+func (o *reloadOutput) SampleConfig() string { return "" }
+func (o *reloadOutput) Description() string  { return "" }
+func (o *reloadOutput) Connect() error       { return nil }
+func (o *reloadOutput) Close() error {
+	o.Lock()
+	defer o.Unlock()
+	o.closed = true
+	return nil
+}
+func (o *reloadOutput) Write(metrics []telegraf.Metric) error {
+	o.Lock()
+	defer o.Unlock()
+	o.metrics = append(o.metrics, metrics...)
+	return nil
+}
+
+// hasMetric reports if a metric with the name has been written.
+func (o *reloadOutput) hasMetric(name string) bool {
+	o.Lock()
+	defer o.Unlock()
+	for _, m := range o.metrics {
+		if m.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *reloadOutput) isClosed() bool {
+	o.Lock()
+	defer o.Unlock()
+	return o.closed
+}
+
+// reloadProcessor is a streaming processor that can fail to start.
+type reloadProcessor struct {
+	Fail bool `toml:"fail"`
+}
+
+func (p *reloadProcessor) SampleConfig() string { return "" }
+func (p *reloadProcessor) Description() string  { return "" }
+func (p *reloadProcessor) Start(acc telegraf.Accumulator) error {
+	if p.Fail {
+		return errors.New("start failed")
+	}
+	return nil
+}
+func (p *reloadProcessor) Add(m telegraf.Metric, acc telegraf.Accumulator) error {
+	acc.AddMetric(m)
+	return nil
+}
+func (p *reloadProcessor) Stop() error { return nil }
+
+func init() {
+	inputs.Add("reload_test", func() telegraf.Input { return &reloadInput{} })
+	outputs.Add("reload_test", func() telegraf.Output { return &reloadOutput{} })
+	processors.AddStreaming("reload_test", func() telegraf.StreamingProcessor { return &reloadProcessor{} })
+}
+
+const reloadAgentConfig = `
+[agent]
+  interval = "10ms"
+  flush_interval = "10ms"
+  omit_hostname = true
+`
+
+func loadReloadConfig(t *testing.T, data string) *config.Config {
+	c := config.NewConfig()
+	require.NoError(t, c.LoadConfigData([]byte(reloadAgentConfig+data)))
+	return c
+}
+
+func runReloadAgent(t *testing.T, c *config.Config) (*Agent, func()) {
+	a, err := NewAgent(c)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- a.Run(ctx)
+	}()
+	return a, func() {
+		cancel()
+		require.NoError(t, <-done)
+	}
+}
+
+func TestAgent_ReloadKeepsUnchangedPlugins(t *testing.T) {
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+[[outputs.reload_test]]
+`)
+	input := c.Inputs[0]
+	output := c.Outputs[0].Output.(*reloadOutput)
+
+	a, stop := runReloadAgent(t, c)
+	defer stop()
+
+	require.Eventually(t, func() bool { return output.hasMetric("a") },
+		5*time.Second, 10*time.Millisecond)
+
+	c = loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+[[inputs.reload_test]]
+  name = "b"
+[[outputs.reload_test]]
+`)
+	require.NoError(t, a.Reload(context.Background(), c))
+
+	require.Len(t, a.Config.Inputs, 2)
+	require.Same(t, input, a.Config.Inputs[0])
+	require.Len(t, a.Config.Outputs, 1)
+	require.Same(t, output, a.Config.Outputs[0].Output)
+	require.False(t, output.isClosed())
+
+	require.Eventually(t, func() bool { return output.hasMetric("b") },
+		5*time.Second, 10*time.Millisecond)
+}
+
+func TestAgent_ReloadReplacesChangedOutput(t *testing.T) {
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+[[outputs.reload_test]]
+  alias = "old"
+`)
+	output := c.Outputs[0].Output.(*reloadOutput)
+
+	a, stop := runReloadAgent(t, c)
+	defer stop()
+
+	c = loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+[[outputs.reload_test]]
+  alias = "new"
+`)
+	newOutput := c.Outputs[0].Output.(*reloadOutput)
+	require.NoError(t, a.Reload(context.Background(), c))
+
+	require.True(t, output.isClosed())
+	require.Eventually(t, func() bool { return newOutput.hasMetric("a") },
+		5*time.Second, 10*time.Millisecond)
+}
+
+func TestAgent_ReloadAgentChangeRequiresRestart(t *testing.T) {
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+[[outputs.reload_test]]
+`)
+	a, stop := runReloadAgent(t, c)
+	defer stop()
+
+	c = loadReloadConfig(t, `
+[global_tags]
+  dc = "us-east-1"
+[[inputs.reload_test]]
+[[outputs.reload_test]]
+`)
+	require.Equal(t, ErrRestartRequired, a.Reload(context.Background(), c))
+}
+
+func TestAgent_ReloadFailedProcessingKeepsInputs(t *testing.T) {
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "a"
+[[outputs.reload_test]]
+`)
+	input := c.Inputs[0]
+	output := c.Outputs[0].Output.(*reloadOutput)
+
+	a, stop := runReloadAgent(t, c)
+	defer stop()
+
+	c = loadReloadConfig(t, `
+[[inputs.reload_test]]
+  name = "b"
+[[processors.reload_test]]
+  fail = true
+[[outputs.reload_test]]
+`)
+	require.Error(t, a.Reload(context.Background(), c))
+
+	// The input is not replaced, and its metrics are still processed by
+	// the previous processing.
+	require.Len(t, a.Config.Inputs, 1)
+	require.Same(t, input, a.Config.Inputs[0])
+	require.Len(t, a.Config.Processors, 0)
+	output.Lock()
+	output.metrics = nil
+	output.Unlock()
+	require.Eventually(t, func() bool { return output.hasMetric("a") },
+		5*time.Second, 10*time.Millisecond)
+	require.False(t, output.hasMetric("b"))
+}
+
+func TestMatchPlugins(t *testing.T) {
+	match, unpaired := matchPlugins(
+		[]string{"a", "b", "b", "c"},
+		[]string{"b", "d", "a", "b", "b"},
+	)
+	require.Equal(t, []int{1, -1, 0, 2, -1}, match)
+	require.Equal(t, []int{3}, unpaired)
+}
+
+func TestProcessorIDs_RunOrder(t *testing.T) {
+	p1 := &models.RunningProcessor{ID: "1", Config: &models.ProcessorConfig{Order: 1}}
+	p2 := &models.RunningProcessor{ID: "2", Config: &models.ProcessorConfig{Order: 2}}
+	p3 := &models.RunningProcessor{ID: "3", Config: &models.ProcessorConfig{Order: 2}}
+
+	// Processors are sorted in place from last to first when started.
+	require.Equal(t,
+		processorIDs(models.RunningProcessors{p1, p2, p3}),
+		processorIDs(models.RunningProcessors{p2, p3, p1}))
+	require.NotEqual(t,
+		processorIDs(models.RunningProcessors{p1, p2, p3}),
+		processorIDs(models.RunningProcessors{p1, p3, p2}))
+}
+
+func TestAgent_ReloadRouteChangeRequiresRestart(t *testing.T) {
+	c := loadReloadConfig(t, `
+[[inputs.reload_test]]
+[[outputs.reload_test]]
+`)
+	a, stop := runReloadAgent(t, c)
+	defer stop()
+
+	c = loadReloadConfig(t, `
+[[route]]
+  name = "app"
+  inputs = ["reload_test"]
+  outputs = ["reload_test"]
+[[inputs.reload_test]]
+[[outputs.reload_test]]
+`)
+	require.Equal(t, ErrRestartRequired, a.Reload(context.Background(), c))
+}
@@ -0,0 +1,212 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/internal"
+)
+
+// statePersister keeps the state of the stateful plugins in the state file.
+// The state file is a JSON object with the state of each plugin by key.
+type statePersister struct {
+	path string
+
+	// states is the state of each plugin as last loaded or collected, from
+	// which plugins added while running are restored.
+	mu     sync.Mutex
+	states map[string]json.RawMessage
+}
+
+// statefulPlugin is a stateful plugin and the key of its state.
+type statefulPlugin struct {
+	kind    string
+	key     string
+	logName string
+	plugin  telegraf.StatefulPlugin
+}
+
+// unwrappable is a processor wrapped as a streaming processor.
+type unwrappable interface {
+	Unwrap() telegraf.Processor
+}
+
+// newStatePersister returns a persister with the states loaded from the
+// state file.  A missing or unreadable file is an empty state, so that a bad
+// state file does not prevent Telegraf from starting.
+func newStatePersister(path string) *statePersister {
+	p := &statePersister{
+		path:   path,
+		states: make(map[string]json.RawMessage),
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p
+	}
+	if err == nil {
+		err = json.Unmarshal(buf, &p.states)
+	}
+	if err != nil {
+		log.Printf("W! [agent] Reading state file %s failed, starting without state: %v", path, err)
+		p.states = make(map[string]json.RawMessage)
+	}
+	return p
+}
+
+// restore sets the saved state of each plugin that has one.
+func (p *statePersister) restore(plugins []statefulPlugin) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, sp := range plugins {
+		data, ok := p.states[sp.key]
+		if !ok {
+			continue
+		}
+		if err := restoreState(sp.plugin, data); err != nil {
+			log.Printf("E! [agent] Restoring state of %s failed: %v", sp.logName, err)
+			continue
+		}
+		log.Printf("D! [agent] Restored state of %s", sp.logName)
+	}
+}
+
+// restoreState decodes the state into a value of the type returned by
+// GetState and passes it to SetState.  Numbers in values of interface types
+// are decoded as json.Number, so that their type can be kept.
+func restoreState(plugin telegraf.StatefulPlugin, data json.RawMessage) error {
+	current := plugin.GetState()
+	if current == nil {
+		return nil
+	}
+
+	v := reflect.New(reflect.TypeOf(current))
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v.Interface()); err != nil {
+		return err
+	}
+	return plugin.SetState(v.Elem().Interface())
+}
+
+// collect updates the states with the state of the plugins.  The previous
+// state of a plugin is kept if it returns no state or its state cannot be
+// encoded.
+func (p *statePersister) collect(plugins []statefulPlugin) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, sp := range plugins {
+		state := sp.plugin.GetState()
+		if state == nil {
+			continue
+		}
+		data, err := json.Marshal(state)
+		if err != nil {
+			log.Printf("E! [agent] Saving state of %s failed: %v", sp.logName, err)
+			continue
+		}
+		p.states[sp.key] = data
+	}
+}
+
+// save collects the state of the plugins and writes it to the state file,
+// replacing the file only once it has been written completely.  The states
+// of other plugins are removed.
+func (p *statePersister) save(plugins []statefulPlugin) error {
+	p.collect(plugins)
+
+	p.mu.Lock()
+	states := make(map[string]json.RawMessage, len(plugins))
+	for _, sp := range plugins {
+		if data, ok := p.states[sp.key]; ok {
+			states[sp.key] = data
+		}
+	}
+	p.states = states
+	buf, err := json.Marshal(states)
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return internal.WriteFileAtomic(p.path, buf)
+}
+
+// statefulPlugins returns the stateful plugins of the config.  The key of a
+// plugin is made of its kind, name and ID, so that a plugin whose config
+// changed starts without state.  Plugins with the same config are told apart
+// by their order.
+func statefulPlugins(c *config.Config) []statefulPlugin {
+	var plugins []statefulPlugin
+	seen := make(map[interface{}]bool)
+	count := make(map[string]int)
+	add := func(kind, name, id, logName string, plugin interface{}) {
+		if u, ok := plugin.(unwrappable); ok {
+			plugin = u.Unwrap()
+		}
+		sp, ok := plugin.(telegraf.StatefulPlugin)
+		if !ok || seen[plugin] {
+			return
+		}
+		seen[plugin] = true
+
+		key := kind + "." + name + "." + id
+		count[key]++
+		if n := count[key]; n > 1 {
+			key += "." + strconv.Itoa(n)
+		}
+		plugins = append(plugins, statefulPlugin{kind: kind, key: key, logName: logName, plugin: sp})
+	}
+
+	for _, input := range c.Inputs {
+		add("inputs", input.Config.Name, input.ID, input.LogName(), input.Input)
+	}
+	for _, processor := range c.Processors {
+		add("processors", processor.Config.Name, processor.ID, processor.LogName(), processor.Processor)
+	}
+	for _, aggregator := range c.Aggregators {
+		add("aggregators", aggregator.Config.Name, aggregator.ID, aggregator.LogName(), aggregator.Aggregator)
+	}
+	for _, processor := range c.AggProcessors {
+		add("aggprocessors", processor.Config.Name, processor.ID, processor.LogName(), processor.Processor)
+	}
+	for _, output := range c.Outputs {
+		add("outputs", output.Config.Name, output.ID, output.LogName(), output.Output)
+	}
+	return plugins
+}
+
+// saveState saves the state of the plugins of the running config to the
+// state file.
+func (a *Agent) saveState() {
+	if a.state == nil {
+		return
+	}
+	err := a.state.save(statefulPlugins(a.Config))
+	if err != nil {
+		log.Printf("E! [agent] Saving state file %s failed: %v", a.state.path, err)
+		return
+	}
+	log.Printf("D! [agent] Saved state file %s", a.state.path)
+}
+
+// statefileTicker returns a channel ticking at the interval of saving the
+// state file, or a nil channel when no state file is configured.
+func (a *Agent) statefileTicker() (<-chan time.Time, func()) {
+	if a.state == nil || a.Config.Agent.StatefileInterval.Duration <= 0 {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(a.Config.Agent.StatefileInterval.Duration)
+	return ticker.C, ticker.Stop
+}
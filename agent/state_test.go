@@ -0,0 +1,207 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/stretchr/testify/require"
+)
+
+type counterState struct {
+	Count int `json:"count"`
+}
+
+// stateInput counts its gathers, keeping the count across restarts.
+type stateInput struct {
+	sync.Mutex
+	Name     string `toml:"name"`
+	count    int
+	restored bool
+}
+
+func (i *stateInput) SampleConfig() string { return "" }
+func (i *stateInput) Description() string  { return "" }
+func (i *stateInput) Gather(acc telegraf.Accumulator) error {
+	i.Lock()
+	defer i.Unlock()
+	i.count++
+	return nil
+}
+
+func (i *stateInput) GetState() interface{} {
+	i.Lock()
+	defer i.Unlock()
+	return counterState{Count: i.count}
+}
+
+func (i *stateInput) SetState(state interface{}) error {
+	s, ok := state.(counterState)
+	if !ok {
+		return fmt.Errorf("invalid state of type %T", state)
+	}
+	i.Lock()
+	defer i.Unlock()
+	i.count = s.Count
+	i.restored = true
+	return nil
+}
+
+func (i *stateInput) getCount() int {
+	i.Lock()
+	defer i.Unlock()
+	return i.count
+}
+
+// stateProcessor is a plain processor, wrapped as a streaming processor.
+type stateProcessor struct {
+	stateInput
+}
+
+func (p *stateProcessor) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	return in
+}
+
+func init() {
+	inputs.Add("state_test", func() telegraf.Input { return &stateInput{} })
+	processors.Add("state_test", func() telegraf.Processor { return &stateProcessor{} })
+}
+
+func TestAgent_StateRestoredAfterRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	statefile := filepath.Join(dir, "state.json")
+
+	data := fmt.Sprintf(`
+  statefile = %q
+[[inputs.state_test]]
+  name = "a"
+[[outputs.reload_test]]
+`, statefile)
+
+	c := loadReloadConfig(t, data)
+	input := c.Inputs[0].Input.(*stateInput)
+	_, stop := runReloadAgent(t, c)
+	require.Eventually(t, func() bool { return input.getCount() >= 2 },
+		5*time.Second, 10*time.Millisecond)
+	stop()
+	count := input.getCount()
+	require.FileExists(t, statefile)
+
+	c = loadReloadConfig(t, data)
+	input = c.Inputs[0].Input.(*stateInput)
+	a, err := NewAgent(c)
+	require.NoError(t, err)
+	a.state = newStatePersister(statefile)
+	require.NoError(t, a.initPlugins())
+	require.True(t, input.restored)
+	require.Equal(t, count, input.getCount())
+}
+
+func TestAgent_StateChangedConfigNotRestored(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	statefile := filepath.Join(dir, "state.json")
+
+	c := loadReloadConfig(t, `
+[[inputs.state_test]]
+  name = "a"
+`)
+	c.Inputs[0].Input.(*stateInput).count = 3
+	p := newStatePersister(statefile)
+	require.NoError(t, p.save(statefulPlugins(c)))
+
+	c = loadReloadConfig(t, `
+[[inputs.state_test]]
+  name = "b"
+`)
+	p = newStatePersister(statefile)
+	p.restore(statefulPlugins(c))
+	require.False(t, c.Inputs[0].Input.(*stateInput).restored)
+}
+
+func TestStatefulPlugins(t *testing.T) {
+	c := loadReloadConfig(t, `
+[[inputs.state_test]]
+  name = "a"
+[[inputs.state_test]]
+  name = "a"
+[[processors.state_test]]
+`)
+	plugins := statefulPlugins(c)
+	require.Len(t, plugins, 4)
+
+	// Plugins with the same config are told apart by their order.
+	require.Equal(t, "inputs", plugins[0].kind)
+	require.Equal(t, plugins[0].key+".2", plugins[1].key)
+	require.Same(t, c.Inputs[0].Input, plugins[0].plugin)
+	require.Same(t, c.Inputs[1].Input, plugins[1].plugin)
+
+	// Processors are unwrapped, and the copy running after the aggregators
+	// has its own state.
+	require.Equal(t, "processors", plugins[2].kind)
+	require.IsType(t, &stateProcessor{}, plugins[2].plugin)
+	require.Equal(t, "aggprocessors", plugins[3].kind)
+	require.NotSame(t, plugins[2].plugin, plugins[3].plugin)
+}
+
+func TestStatePersister_InvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	statefile := filepath.Join(dir, "state.json")
+	require.NoError(t, ioutil.WriteFile(statefile, []byte("{"), 0640))
+
+	p := newStatePersister(statefile)
+	require.Empty(t, p.states)
+}
+
+func TestAgent_StateRestoredOnReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	statefile := filepath.Join(dir, "state.json")
+
+	newConfig := fmt.Sprintf(`
+  statefile = %q
+[[inputs.state_test]]
+  name = "a"
+[[inputs.state_test]]
+  name = "b"
+[[outputs.reload_test]]
+`, statefile)
+
+	// The state file has the state of an input not yet running.
+	c := loadReloadConfig(t, newConfig)
+	key := statefulPlugins(c)[1].key
+	require.NoError(t, ioutil.WriteFile(statefile,
+		[]byte(fmt.Sprintf(`{%q: {"count": 5}}`, key)), 0640))
+
+	c = loadReloadConfig(t, fmt.Sprintf(`
+  statefile = %q
+[[inputs.state_test]]
+  name = "a"
+[[outputs.reload_test]]
+`, statefile))
+	input := c.Inputs[0].Input.(*stateInput)
+	a, stop := runReloadAgent(t, c)
+	defer stop()
+	require.Eventually(t, func() bool { return input.getCount() > 0 },
+		5*time.Second, 10*time.Millisecond)
+
+	c = loadReloadConfig(t, newConfig)
+	added := c.Inputs[1].Input.(*stateInput)
+	require.NoError(t, a.Reload(context.Background(), c))
+	require.True(t, added.restored)
+	require.GreaterOrEqual(t, added.getCount(), 5)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
 	_ "net/http/pprof" // Comment this line to disable pprof endpoint.
@@ -26,6 +27,7 @@ import (
 	"github.com/influxdata/telegraf/plugins/outputs"
 	_ "github.com/influxdata/telegraf/plugins/outputs/all"
 	_ "github.com/influxdata/telegraf/plugins/processors/all"
+	_ "github.com/influxdata/telegraf/plugins/secretstores/all"
 )
 
 // If you update these, update usage.go and usage_windows.go
@@ -68,6 +70,8 @@ var fRunAsConsole = flag.Bool("console", false, "run as console application (win
 var fPlugins = flag.String("plugin-directory", "",
 	"path to directory containing external plugins")
 var fRunOnce = flag.Bool("once", false, "run one gather and exit")
+var fValidate = flag.Bool("validate", false,
+	"validate the configuration, print all errors found, and exit")
 
 var (
 	version string
@@ -90,51 +94,74 @@ func reloadLoop(
 
 		ctx, cancel := context.WithCancel(context.Background())
 
+		// SIGHUP reloads the config into the running agent; a full restart
+		// is only done when the config change requires it.
+		hup := make(chan struct{}, 1)
+		restart := func() {
+			<-reload
+			reload <- true
+			cancel()
+		}
+
 		signals := make(chan os.Signal, 1)
 		signal.Notify(signals, os.Interrupt, syscall.SIGHUP,
 			syscall.SIGTERM, syscall.SIGINT)
 		go func() {
-			select {
-			case sig := <-signals:
-				if sig == syscall.SIGHUP {
-					log.Printf("I! Reloading Telegraf config")
-					<-reload
-					reload <- true
+			for {
+				select {
+				case sig := <-signals:
+					if sig == syscall.SIGHUP {
+						log.Printf("I! Reloading Telegraf config")
+						select {
+						case hup <- struct{}{}:
+						default:
+						}
+						continue
+					}
+					cancel()
+				case <-stop:
+					cancel()
+				case <-ctx.Done():
 				}
-				cancel()
-			case <-stop:
-				cancel()
+				return
 			}
 		}()
 
-		err := runAgent(ctx, inputFilters, outputFilters)
+		err := runAgent(ctx, inputFilters, outputFilters, hup, restart)
+		signal.Stop(signals)
 		if err != nil && err != context.Canceled {
 			log.Fatalf("E! [telegraf] Error running agent: %v", err)
 		}
 	}
 }
 
-func runAgent(ctx context.Context,
+// loadConfig loads the config file and directory.
+func loadConfig(
 	inputFilters []string,
 	outputFilters []string,
-) error {
-	log.Printf("I! Starting Telegraf %s", version)
-
-	// If no other options are specified, load the config file and run.
+) (*config.Config, error) {
 	c := config.NewConfig()
 	c.OutputFilters = outputFilters
 	c.InputFilters = inputFilters
 	err := c.LoadConfig(*fConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if *fConfigDirectory != "" {
 		err = c.LoadDirectory(*fConfigDirectory)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
+	if err := checkConfig(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// checkConfig checks the loaded config can be run.
+func checkConfig(c *config.Config) error {
 	if !*fTest && len(c.Outputs) == 0 {
 		return errors.New("Error: no outputs found, did you provide a valid config file?")
 	}
@@ -151,6 +178,129 @@ func runAgent(ctx context.Context,
 		return fmt.Errorf("Agent flush_interval must be positive; found %s",
 			c.Agent.Interval.Duration)
 	}
+	return nil
+}
+
+// validateConfig loads the config file and directory, initializing the
+// plugins without starting them, and returns all errors found.
+func validateConfig(inputFilters []string, outputFilters []string) []error {
+	c := config.NewConfig()
+	c.OutputFilters = outputFilters
+	c.InputFilters = inputFilters
+
+	var dirs []string
+	if *fConfigDirectory != "" {
+		dirs = append(dirs, *fConfigDirectory)
+	}
+	errs := c.Validate([]string{*fConfig}, dirs)
+	if len(errs) == 0 {
+		if err := checkConfig(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// secretsCommand runs the secrets subcommand, managing the secrets of a
+// writable secret store, such as the keyring:
+//
+//   telegraf secrets set <store-id> <name>   reads the secret from stdin
+//   telegraf secrets list <store-id>
+func secretsCommand(args []string) error {
+	if len(args) < 2 {
+		return errors.New("usage: telegraf secrets set <store-id> <name> | list <store-id>")
+	}
+
+	c := config.NewConfig()
+	if err := c.LoadSecretStores(*fConfig); err != nil {
+		return err
+	}
+	if *fConfigDirectory != "" {
+		if err := c.LoadSecretStoresDirectory(*fConfigDirectory); err != nil {
+			return err
+		}
+	}
+	store, ok := c.SecretStores[args[1]]
+	if !ok {
+		return fmt.Errorf("no secret store %q", args[1])
+	}
+
+	switch {
+	case args[0] == "set" && len(args) == 3:
+		setter, ok := store.(interface{ Set(name, value string) error })
+		if !ok {
+			return fmt.Errorf("secret store %q is read-only", args[1])
+		}
+		value, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		return setter.Set(args[2], strings.TrimRight(string(value), "\r\n"))
+	case args[0] == "list" && len(args) == 2:
+		lister, ok := store.(interface{ List() ([]string, error) })
+		if !ok {
+			return fmt.Errorf("secret store %q cannot list its secrets", args[1])
+		}
+		names, err := lister.List()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	return errors.New("usage: telegraf secrets set <store-id> <name> | list <store-id>")
+}
+
+// reloadAgent applies the config to the running agent on each signal from
+// hup, calling restart if the change cannot be applied in place.  An invalid
+// config is logged and the agent keeps running with its current config.
+func reloadAgent(
+	ctx context.Context,
+	ag *agent.Agent,
+	inputFilters []string,
+	outputFilters []string,
+	hup <-chan struct{},
+	restart func(),
+) {
+	for {
+		select {
+		case <-hup:
+			c, err := loadConfig(inputFilters, outputFilters)
+			if err != nil {
+				log.Printf("E! [telegraf] Error loading config, keeping current config: %v", err)
+				continue
+			}
+
+			err = ag.Reload(ctx, c)
+			if err == agent.ErrRestartRequired {
+				log.Printf("I! [telegraf] Restarting agent: %v", err)
+				restart()
+				return
+			}
+			if err != nil {
+				log.Printf("E! [telegraf] Error reloading config: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func runAgent(ctx context.Context,
+	inputFilters []string,
+	outputFilters []string,
+	hup <-chan struct{},
+	restart func(),
+) error {
+	log.Printf("I! Starting Telegraf %s", version)
+
+	// If no other options are specified, load the config file and run.
+	c, err := loadConfig(inputFilters, outputFilters)
+	if err != nil {
+		return err
+	}
 
 	ag, err := agent.NewAgent(c)
 	if err != nil {
@@ -204,6 +354,8 @@ func runAgent(ctx context.Context,
 		}
 	}
 
+	go reloadAgent(ctx, ag, inputFilters, outputFilters, hup, restart)
+
 	return ag.Run(ctx)
 }
 
@@ -300,6 +452,11 @@ func main() {
 				processorFilters,
 			)
 			return
+		case "secrets":
+			if err := secretsCommand(args[1:]); err != nil {
+				log.Fatalf("E! %v", err)
+			}
+			return
 		}
 	}
 
@@ -346,6 +503,16 @@ func main() {
 			log.Fatalf("E! %s and %s", err, err2)
 		}
 		return
+	case *fValidate:
+		errs := validateConfig(inputFilters, outputFilters)
+		for _, err := range errs {
+			log.Printf("E! %v", err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		log.Printf("I! Configuration is valid")
+		return
 	}
 
 	shortVersion := version
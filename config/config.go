@@ -2,7 +2,10 @@ package config
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -70,6 +73,12 @@ type Config struct {
 	// Processors have a slice wrapper type because they need to be sorted
 	Processors    models.RunningProcessors
 	AggProcessors models.RunningProcessors
+
+	RouteConfigs []*RouteConfig
+
+	// SecretStores are the secret stores by their id.
+	SecretStores map[string]telegraf.SecretStore
+	secretRefs   map[*ast.String]string // secret references by their resolved string
 }
 
 // NewConfig creates a new struct to hold the Telegraf config.
@@ -86,6 +95,9 @@ func NewConfig() *Config {
 			FlushInterval:              internal.Duration{Duration: 10 * time.Second},
 			LogTarget:                  "file",
 			LogfileRotationMaxArchives: 5,
+			BufferMaxBytes:             internal.Size{Size: models.DEFAULT_BUFFER_MAX_BYTES},
+			BufferFsync:                models.FsyncFlush,
+			StatefileInterval:          internal.Duration{Duration: time.Minute},
 		},
 
 		Tags:          make(map[string]string),
@@ -95,6 +107,8 @@ func NewConfig() *Config {
 		AggProcessors: make([]*models.RunningProcessor, 0),
 		InputFilters:  make([]string, 0),
 		OutputFilters: make([]string, 0),
+		SecretStores:  make(map[string]telegraf.SecretStore),
+		secretRefs:    make(map[*ast.String]string),
 	}
 
 	tomlCfg := &toml.Config{
@@ -155,6 +169,39 @@ type AgentConfig struct {
 	// does _not_ deactivate FlushInterval.
 	FlushBufferWhenFull bool // deprecated in 0.13; has no effect
 
+	// BufferDirectory is the directory used to store a write-ahead log of each
+	// output's buffer.  When set, metrics not yet written to an output are
+	// kept across restarts.  When empty the buffers are held in memory only.
+	BufferDirectory string `toml:"buffer_directory"`
+
+	// BufferMaxBytes is the maximum size of the metrics kept in each output's
+	// buffer log, when exceeded the oldest metrics are dropped.
+	BufferMaxBytes internal.Size `toml:"buffer_max_bytes"`
+
+	// BufferFsync controls when the buffer log is synced to disk and can be
+	// one of "always", "flush" or "never".
+	BufferFsync string `toml:"buffer_fsync"`
+
+	// RetryInitialInterval is the delay before retrying a failed write to an
+	// output, doubling after each consecutive failure up to RetryMaxInterval.
+	// When zero, failed writes are retried on the next flush.
+	RetryInitialInterval internal.Duration `toml:"retry_initial_interval"`
+	RetryMaxInterval     internal.Duration `toml:"retry_max_interval"`
+
+	// CircuitBreakerThreshold is the number of consecutive failed writes
+	// after which writes to an output are paused for CircuitBreakerTimeout.
+	CircuitBreakerThreshold int               `toml:"circuit_breaker_threshold"`
+	CircuitBreakerTimeout   internal.Duration `toml:"circuit_breaker_timeout"`
+
+	// Statefile is the file the state of stateful plugins is saved to, and
+	// restored from when the agent starts.  When empty the state of plugins
+	// is not kept across restarts.
+	Statefile string `toml:"statefile"`
+
+	// StatefileInterval is the interval at which the state file is saved
+	// while running, in addition to when the agent stops.
+	StatefileInterval internal.Duration `toml:"statefile_interval"`
+
 	// TODO(cam): Remove UTC and parameter, they are no longer
 	// valid for the agent config. Leaving them here for now for backwards-
 	// compatibility
@@ -189,6 +236,21 @@ type AgentConfig struct {
 
 	Hostname     string
 	OmitHostname bool
+
+	// Address to serve the HTTP management API on.  When empty the API is
+	// disabled, and when the host is empty the API is served on localhost.
+	APIAddress string `toml:"api_address"`
+
+	// Credentials required by the management API with HTTP basic
+	// authentication.  When empty the API has no authentication.
+	APIBasicUsername string `toml:"api_basic_username"`
+	APIBasicPassword string `toml:"api_basic_password"`
+
+	// TLS certificate and key of the management API, and the CAs of the
+	// client certificates allowed.  When empty the API is served over HTTP.
+	APITLSCert           string   `toml:"api_tls_cert"`
+	APITLSKey            string   `toml:"api_tls_key"`
+	APITLSAllowedCACerts []string `toml:"api_tls_allowed_cacerts"`
 }
 
 // InputNames returns a list of strings of the configured inputs.
@@ -305,6 +367,39 @@ var agentConfig = `
   ## cost of higher maximum memory usage.
   metric_buffer_limit = 10000
 
+  ## Directory in which a write-ahead log of each output's buffer is kept.
+  ## When set, metrics not yet written to an output survive a restart and are
+  ## written on startup.  When empty, buffers are only held in memory.
+  # buffer_directory = ""
+
+  ## Maximum size of the unwritten metrics kept in each output's buffer log.
+  ## When exceeded the oldest metrics are dropped.
+  # buffer_max_bytes = "64MB"
+
+  ## When to sync the buffer log to disk; can be "always" to sync on every
+  ## change, "flush" to sync after each write to the output, or "never" to
+  ## leave it to the operating system.
+  # buffer_fsync = "flush"
+
+  ## Delay before retrying a failed write to an output, doubling after each
+  ## consecutive failure up to retry_max_interval, with random jitter.  When
+  ## unset, failed writes are retried on the next flush.
+  # retry_initial_interval = "0s"
+  # retry_max_interval = "5m"
+
+  ## Number of consecutive failed writes after which writes to an output are
+  ## paused for circuit_breaker_timeout, while its buffer keeps filling.  Zero
+  ## disables the circuit breaker.
+  # circuit_breaker_threshold = 0
+  # circuit_breaker_timeout = "1m"
+
+  ## File the state of plugins, such as the offsets of tailed files, is saved
+  ## to when Telegraf stops and restored from when it starts.  When empty the
+  ## state is not kept across restarts.
+  # statefile = ""
+  ## Interval at which the state file is also saved while running.
+  # statefile_interval = "1m"
+
   ## Collection jitter is used to jitter the collection by a random amount.
   ## Each plugin will sleep for a random time within jitter before collecting.
   ## This can be used to avoid many plugins querying things like sysfs at the
@@ -360,6 +455,19 @@ var agentConfig = `
   ## If set to true, do no set the "host" tag in the telegraf agent.
   omit_hostname = false
 
+  ## Address to serve the HTTP management API on, for example
+  ## "localhost:8095"; an address without host, such as ":8095", is served
+  ## on localhost.  When empty the API is disabled.
+  # api_address = ""
+  ## HTTP basic authentication of the API requests.
+  # api_basic_username = "telegraf"
+  # api_basic_password = "metricsmetricsmetrics"
+  ## TLS certificate and key of the API, and the CAs of the client
+  ## certificates allowed.
+  # api_tls_cert = "/etc/telegraf/cert.pem"
+  # api_tls_key = "/etc/telegraf/key.pem"
+  # api_tls_allowed_cacerts = ["/etc/telegraf/clientca.pem"]
+
 `
 
 var outputHeader = `
@@ -657,6 +765,12 @@ func PrintOutputConfig(name string) error {
 
 // LoadDirectory loads all toml config files found in the specified path, recursively.
 func (c *Config) LoadDirectory(path string) error {
+	return walkConfigDirectory(path, c.LoadConfig)
+}
+
+// walkConfigDirectory calls fn for all toml config files found in the
+// specified path, recursively.
+func walkConfigDirectory(path string, fn func(path string) error) error {
 	walkfn := func(thispath string, info os.FileInfo, _ error) error {
 		if info == nil {
 			log.Printf("W! Telegraf is not permitted to read %s", thispath)
@@ -675,7 +789,7 @@ func (c *Config) LoadDirectory(path string) error {
 		if len(name) < 6 || name[len(name)-5:] != ".conf" {
 			return nil
 		}
-		err := c.LoadConfig(thispath)
+		err := fn(thispath)
 		if err != nil {
 			return err
 		}
@@ -737,8 +851,42 @@ func (c *Config) LoadConfigData(data []byte) error {
 	if err != nil {
 		return fmt.Errorf("Error parsing data: %s", err)
 	}
+	return c.loadTable(tbl)
+}
+
+// loadTable loads the parsed config data.
+func (c *Config) loadTable(tbl *ast.Table) error {
+	var err error
 
-	// Parse tags tables first:
+	// Parse secret stores first, so the secrets can be resolved:
+	if val, ok := tbl.Fields["secretstores"]; ok {
+		subTable, ok := val.(*ast.Table)
+		if !ok {
+			return fmt.Errorf("invalid configuration, error parsing secretstores table")
+		}
+		for pluginName, pluginVal := range subTable.Fields {
+			pluginSubTables, ok := pluginVal.([]*ast.Table)
+			if !ok {
+				return fmt.Errorf("Unsupported config format: %s", pluginName)
+			}
+			for _, t := range pluginSubTables {
+				if err = c.addSecretStore(pluginName, t); err != nil {
+					return fmt.Errorf("error parsing secret store %s, %w", pluginName, err)
+				}
+			}
+		}
+	}
+	for name, val := range tbl.Fields {
+		if name == "secretstores" {
+			continue
+		}
+		err = c.resolveSecrets(&ast.Table{Fields: map[string]interface{}{name: val}})
+		if err != nil {
+			return fmt.Errorf("error resolving secrets in %s, %w", name, err)
+		}
+	}
+
+	// Parse tags tables:
 	for _, tableName := range []string{"tags", "global_tags"} {
 		if val, ok := tbl.Fields[tableName]; ok {
 			subTable, ok := val.(*ast.Table)
@@ -779,15 +927,32 @@ func (c *Config) LoadConfigData(data []byte) error {
 		return fmt.Errorf("line %d: configuration specified the fields %q, but they weren't used", tbl.Line, keys(c.UnusedFields))
 	}
 
+	// Parse route tables:
+	if val, ok := tbl.Fields["route"]; ok {
+		subTables, ok := val.([]*ast.Table)
+		if !ok {
+			return fmt.Errorf("invalid configuration, routes must be an array of tables [[route]]")
+		}
+		for _, t := range subTables {
+			if err = c.addRoute(t); err != nil {
+				return fmt.Errorf("error parsing route, %w", err)
+			}
+		}
+	}
+
 	// Parse all the rest of the plugins:
 	for name, val := range tbl.Fields {
+		if name == "route" {
+			continue
+		}
+
 		subTable, ok := val.(*ast.Table)
 		if !ok {
 			return fmt.Errorf("invalid configuration, error parsing field %q as table", name)
 		}
 
 		switch name {
-		case "agent", "global_tags", "tags":
+		case "agent", "global_tags", "tags", "secretstores":
 		case "outputs":
 			for pluginName, pluginVal := range subTable.Fields {
 				switch pluginSubTable := pluginVal.(type) {
@@ -981,7 +1146,9 @@ func (c *Config) addAggregator(name string, table *ast.Table) error {
 		return err
 	}
 
-	c.Aggregators = append(c.Aggregators, models.NewRunningAggregator(aggregator, conf))
+	ra := models.NewRunningAggregator(aggregator, conf)
+	ra.ID = c.pluginID("aggregators", name, table)
+	c.Aggregators = append(c.Aggregators, ra)
 	return nil
 }
 
@@ -1031,6 +1198,7 @@ func (c *Config) newRunningProcessor(
 	}
 
 	rf := models.NewRunningProcessor(processor, processorConfig)
+	rf.ID = c.pluginID("processors", name, table)
 	return rf, nil
 }
 
@@ -1038,35 +1206,106 @@ func (c *Config) addOutput(name string, table *ast.Table) error {
 	if len(c.OutputFilters) > 0 && !sliceContains(name, c.OutputFilters) {
 		return nil
 	}
+
+	ro, err := c.newRunningOutput(name, table, false)
+	if err != nil {
+		return err
+	}
+	c.Outputs = append(c.Outputs, ro)
+	return nil
+}
+
+// newRunningOutput builds an output, or a child output of a group when
+// inGroup is set.
+func (c *Config) newRunningOutput(name string, table *ast.Table, inGroup bool) (*models.RunningOutput, error) {
 	creator, ok := outputs.Outputs[name]
 	if !ok {
-		return fmt.Errorf("Undefined but requested output: %s", name)
+		return nil, fmt.Errorf("Undefined but requested output: %s", name)
 	}
 	output := creator()
 
+	// The ID covers the child outputs of a group, which are removed from the
+	// table when they are built.
+	id := c.pluginID("outputs", name, table)
+
 	// If the output has a SetSerializer function, then this means it can write
 	// arbitrary types of output, so build the serializer and set it.
 	switch t := output.(type) {
 	case serializers.SerializerOutput:
 		serializer, err := c.buildSerializer(name, table)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		t.SetSerializer(serializer)
 	}
 
-	outputConfig, err := c.buildOutput(name, table)
+	outputConfig, err := c.buildOutput(name, table, inGroup)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if group, ok := output.(outputs.Group); ok {
+		if err := c.addGroupOutputs(group, table); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := c.toml.UnmarshalTable(table, output); err != nil {
-		return err
+		return nil, err
 	}
 
 	ro := models.NewRunningOutput(name, output, outputConfig,
 		c.Agent.MetricBatchSize, c.Agent.MetricBufferLimit)
-	c.Outputs = append(c.Outputs, ro)
+	ro.ID = id
+	return ro, nil
+}
+
+// addGroupOutputs builds the child outputs of a group from the "outputs"
+// sub-table, in the order they appear in the configuration.
+func (c *Config) addGroupOutputs(group outputs.Group, table *ast.Table) error {
+	val, ok := table.Fields["outputs"]
+	if !ok {
+		return nil
+	}
+	delete(table.Fields, "outputs")
+
+	subTable, ok := val.(*ast.Table)
+	if !ok {
+		return fmt.Errorf("line %d: outputs must be a table of outputs", fieldLine(val))
+	}
+
+	type child struct {
+		name  string
+		table *ast.Table
+	}
+	var children []child
+	for name, val := range subTable.Fields {
+		switch t := val.(type) {
+		case *ast.Table:
+			children = append(children, child{name, t})
+		case []*ast.Table:
+			for _, t := range t {
+				children = append(children, child{name, t})
+			}
+		default:
+			return fmt.Errorf("line %d: unsupported config format: %s", fieldLine(val), name)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].table.Line < children[j].table.Line
+	})
+
+	for _, child := range children {
+		ro, err := c.newRunningOutput(child.name, child.table, true)
+		if err != nil {
+			return err
+		}
+		if len(c.UnusedFields) > 0 {
+			return fmt.Errorf("line %d: configuration specified the fields %q, but they weren't used",
+				child.table.Line, keys(c.UnusedFields))
+		}
+		group.AddOutput(ro)
+	}
 	return nil
 }
 
@@ -1115,6 +1354,7 @@ func (c *Config) addInput(name string, table *ast.Table) error {
 	}
 
 	rp := models.NewRunningInput(input, pluginConfig)
+	rp.ID = c.pluginID("inputs", name, table)
 	rp.SetDefaultTags(c.Tags)
 	c.Inputs = append(c.Inputs, rp)
 	return nil
@@ -1183,7 +1423,7 @@ func (c *Config) buildProcessor(name string, tbl *ast.Table) (*models.ProcessorC
 }
 
 // buildFilter builds a Filter
-// (tagpass/tagdrop/namepass/namedrop/fieldpass/fielddrop) to
+// (tagpass/tagdrop/namepass/namedrop/fieldpass/fielddrop/metricpass) to
 // be inserted into the models.OutputConfig/models.InputConfig
 // to be used for glob filtering on tags and measurements
 func (c *Config) buildFilter(tbl *ast.Table) (models.Filter, error) {
@@ -1204,6 +1444,8 @@ func (c *Config) buildFilter(tbl *ast.Table) (models.Filter, error) {
 	c.getFieldStringSlice(tbl, "tagexclude", &f.TagExclude)
 	c.getFieldStringSlice(tbl, "taginclude", &f.TagInclude)
 
+	c.getFieldString(tbl, "metricpass", &f.MetricPass)
+
 	if c.hasErrs() {
 		return f, c.firstErr()
 	}
@@ -1378,7 +1620,7 @@ func (c *Config) buildSerializer(name string, tbl *ast.Table) (serializers.Seria
 // builds the filter and returns an
 // models.OutputConfig to be inserted into models.RunningInput
 // Note: error exists in the return for future calls that might require error
-func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig, error) {
+func (c *Config) buildOutput(name string, tbl *ast.Table, inGroup bool) (*models.OutputConfig, error) {
 	filter, err := c.buildFilter(tbl)
 	if err != nil {
 		return nil, err
@@ -1395,6 +1637,35 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 
 	c.getFieldInt(tbl, "metric_buffer_limit", &oc.MetricBufferLimit)
 	c.getFieldInt(tbl, "metric_batch_size", &oc.MetricBatchSize)
+
+	// The outputs of a group are written the batches of the group's buffer,
+	// and have no buffer of their own.
+	if inGroup {
+		if _, ok := tbl.Fields["buffer_directory"]; ok {
+			return nil, fmt.Errorf("buffer_directory cannot be set on the outputs of a group")
+		}
+	} else {
+		oc.BufferDirectory = c.Agent.BufferDirectory
+		oc.BufferMaxBytes = c.Agent.BufferMaxBytes.Size
+		oc.BufferFsync = c.Agent.BufferFsync
+		c.getFieldString(tbl, "buffer_directory", &oc.BufferDirectory)
+		c.getFieldSize(tbl, "buffer_max_bytes", &oc.BufferMaxBytes)
+		c.getFieldString(tbl, "buffer_fsync", &oc.BufferFsync)
+	}
+
+	c.getFieldInt(tbl, "max_metrics_per_second", &oc.MaxMetricsPerSecond)
+	c.getFieldSize(tbl, "max_bytes_per_second", &oc.MaxBytesPerSecond)
+	c.getFieldBool(tbl, "adaptive_batch_size", &oc.AdaptiveBatchSize)
+
+	oc.RetryInitialInterval = c.Agent.RetryInitialInterval.Duration
+	oc.RetryMaxInterval = c.Agent.RetryMaxInterval.Duration
+	oc.CircuitBreakerThreshold = c.Agent.CircuitBreakerThreshold
+	oc.CircuitBreakerTimeout = c.Agent.CircuitBreakerTimeout.Duration
+	c.getFieldDuration(tbl, "retry_initial_interval", &oc.RetryInitialInterval)
+	c.getFieldDuration(tbl, "retry_max_interval", &oc.RetryMaxInterval)
+	c.getFieldInt(tbl, "circuit_breaker_threshold", &oc.CircuitBreakerThreshold)
+	c.getFieldDuration(tbl, "circuit_breaker_timeout", &oc.CircuitBreakerTimeout)
+
 	c.getFieldString(tbl, "alias", &oc.Alias)
 	c.getFieldString(tbl, "name_override", &oc.NameOverride)
 	c.getFieldString(tbl, "name_suffix", &oc.NameSuffix)
@@ -1404,12 +1675,27 @@ func (c *Config) buildOutput(name string, tbl *ast.Table) (*models.OutputConfig,
 		return nil, c.firstErr()
 	}
 
+	if oc.BufferDirectory != "" {
+		if err := models.ValidFsyncPolicy(oc.BufferFsync); err != nil {
+			return nil, err
+		}
+
+		// Each output's buffer log is named after the plugin and alias.
+		for _, ro := range c.Outputs {
+			if ro.Config.BufferDirectory == oc.BufferDirectory &&
+				ro.Config.Name == oc.Name && ro.Config.Alias == oc.Alias {
+				return nil, fmt.Errorf("outputs sharing a buffer_directory must have a unique alias")
+			}
+		}
+	}
+
 	return oc, nil
 }
 
 func (c *Config) missingTomlField(typ reflect.Type, key string) error {
 	switch key {
-	case "alias", "carbon2_format", "collectd_auth_file", "collectd_parse_multivalue",
+	case "adaptive_batch_size", "alias", "buffer_directory", "buffer_fsync", "buffer_max_bytes", "carbon2_format",
+		"circuit_breaker_threshold", "circuit_breaker_timeout", "collectd_auth_file", "collectd_parse_multivalue",
 		"collectd_security_level", "collectd_typesdb", "collection_jitter", "csv_column_names",
 		"csv_column_types", "csv_comment", "csv_delimiter", "csv_header_row_count",
 		"csv_measurement_column", "csv_skip_columns", "csv_skip_rows", "csv_tag_columns",
@@ -1422,9 +1708,11 @@ func (c *Config) missingTomlField(typ reflect.Type, key string) error {
 		"grok_unique_timestamp", "influx_max_line_bytes", "influx_sort_fields", "influx_uint_support",
 		"interval", "json_name_key", "json_query", "json_strict", "json_string_fields",
 		"json_time_format", "json_time_key", "json_timestamp_units", "json_timezone",
-		"metric_batch_size", "metric_buffer_limit", "name_override", "name_prefix",
+		"max_bytes_per_second", "max_metrics_per_second", "metric_batch_size", "metric_buffer_limit",
+		"metricpass", "name_override", "name_prefix",
 		"name_suffix", "namedrop", "namepass", "order", "pass", "period", "precision",
 		"prefix", "prometheus_export_timestamp", "prometheus_sort_metrics", "prometheus_string_as_label",
+		"retry_initial_interval", "retry_max_interval",
 		"separator", "splunkmetric_hec_routing", "splunkmetric_multimetric", "tag_keys",
 		"tagdrop", "tagexclude", "taginclude", "tagpass", "tags", "template", "templates",
 		"wavefront_source_override", "wavefront_use_strict":
@@ -1462,6 +1750,19 @@ func (c *Config) getFieldDuration(tbl *ast.Table, fieldName string, target inter
 	}
 }
 
+func (c *Config) getFieldSize(tbl *ast.Table, fieldName string, target *int64) {
+	if node, ok := tbl.Fields[fieldName]; ok {
+		if kv, ok := node.(*ast.KeyValue); ok {
+			var size internal.Size
+			if err := size.UnmarshalTOML([]byte(kv.Value.Source())); err != nil {
+				c.addError(tbl, fmt.Errorf("error parsing size: %w", err))
+				return
+			}
+			*target = size.Size
+		}
+	}
+}
+
 func (c *Config) getFieldBool(tbl *ast.Table, fieldName string, target *bool) {
 	var err error
 	if node, ok := tbl.Fields[fieldName]; ok {
@@ -1588,6 +1889,78 @@ func (c *Config) addError(tbl *ast.Table, err error) {
 	c.errs = append(c.errs, fmt.Errorf("line %d:%d: %w", tbl.Line, tbl.Position, err))
 }
 
+// PluginID returns an identifier for a plugin derived from its type, name and
+// configuration table.  Plugins configured identically have the same ID,
+// regardless of formatting, comments or the order of the settings.
+func PluginID(kind, name string, tbl *ast.Table) string {
+	return pluginID(kind, name, tbl, nil)
+}
+
+// pluginID returns the ID of a plugin loaded into the config.  Resolved
+// secrets are identified by their reference and a keyed digest of the secret.
+func (c *Config) pluginID(kind, name string, tbl *ast.Table) string {
+	return pluginID(kind, name, tbl, c.secretRefs)
+}
+
+func pluginID(kind, name string, tbl *ast.Table, refs map[*ast.String]string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s.%s\n", kind, name)
+	writeTable(h, tbl, refs)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// writeTable writes a canonical form of the table to w.
+func writeTable(w io.Writer, tbl *ast.Table, refs map[*ast.String]string) {
+	names := make([]string, 0, len(tbl.Fields))
+	for name := range tbl.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch v := tbl.Fields[name].(type) {
+		case *ast.KeyValue:
+			fmt.Fprintf(w, "%s=", name)
+			writeValue(w, v.Value, refs)
+			fmt.Fprintln(w)
+		case *ast.Table:
+			fmt.Fprintf(w, "[%s]\n", name)
+			writeTable(w, v, refs)
+			fmt.Fprintln(w, "[]")
+		case []*ast.Table:
+			for _, t := range v {
+				fmt.Fprintf(w, "[[%s]]\n", name)
+				writeTable(w, t, refs)
+				fmt.Fprintln(w, "[[]]")
+			}
+		}
+	}
+}
+
+func writeValue(w io.Writer, value ast.Value, refs map[*ast.String]string) {
+	switch v := value.(type) {
+	case *ast.Array:
+		fmt.Fprint(w, "[")
+		for _, elem := range v.Value {
+			writeValue(w, elem, refs)
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprint(w, "]")
+	case *ast.Table:
+		fmt.Fprint(w, "{")
+		writeTable(w, v, refs)
+		fmt.Fprint(w, "}")
+	case *ast.String:
+		if ref, ok := refs[v]; ok {
+			fmt.Fprintf(w, "%q#%x", ref, secretDigest(v.Value))
+			break
+		}
+		fmt.Fprintf(w, "%q", v.Value)
+	default:
+		fmt.Fprint(w, v.Source())
+	}
+}
+
 // unwrappable lets you retrieve the original telegraf.Processor from the
 // StreamingProcessor. This is necessary because the toml Unmarshaller won't
 // look inside composed types.
@@ -278,3 +278,149 @@ func TestConfig_AzureMonitorNamespacePrefix(t *testing.T) {
 	assert.Equal(t, "", azureMonitor.NamespacePrefix)
 	assert.Equal(t, true, ok)
 }
+
+func TestConfig_OutputBufferLog(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[agent]
+  buffer_directory = "/var/lib/telegraf/buffer"
+  buffer_max_bytes = "16MB"
+
+[[outputs.http]]
+
+[[outputs.http]]
+  alias = "override"
+  buffer_directory = "/tmp/buffer"
+  buffer_max_bytes = 1024
+  buffer_fsync = "always"
+`))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(c.Outputs))
+
+	require.Equal(t, "/var/lib/telegraf/buffer", c.Outputs[0].Config.BufferDirectory)
+	require.Equal(t, int64(16*1000*1000), c.Outputs[0].Config.BufferMaxBytes)
+	require.Equal(t, "flush", c.Outputs[0].Config.BufferFsync)
+
+	require.Equal(t, "/tmp/buffer", c.Outputs[1].Config.BufferDirectory)
+	require.Equal(t, int64(1024), c.Outputs[1].Config.BufferMaxBytes)
+	require.Equal(t, "always", c.Outputs[1].Config.BufferFsync)
+
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[agent]
+  buffer_directory = "/var/lib/telegraf/buffer"
+
+[[outputs.http]]
+[[outputs.http]]
+`))
+	require.Error(t, err)
+
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[outputs.http]]
+  buffer_directory = "/var/lib/telegraf/buffer"
+  buffer_fsync = "sometimes"
+`))
+	require.Error(t, err)
+}
+
+func TestConfig_OutputRateLimit(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[outputs.http]]
+
+[[outputs.http]]
+  max_metrics_per_second = 1000
+  max_bytes_per_second = "1MB"
+  adaptive_batch_size = true
+`))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(c.Outputs))
+
+	require.Equal(t, 0, c.Outputs[0].Config.MaxMetricsPerSecond)
+	require.Equal(t, int64(0), c.Outputs[0].Config.MaxBytesPerSecond)
+	require.False(t, c.Outputs[0].Config.AdaptiveBatchSize)
+
+	require.Equal(t, 1000, c.Outputs[1].Config.MaxMetricsPerSecond)
+	require.Equal(t, int64(1000*1000), c.Outputs[1].Config.MaxBytesPerSecond)
+	require.True(t, c.Outputs[1].Config.AdaptiveBatchSize)
+}
+
+func TestConfig_OutputCircuitBreaker(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[agent]
+  retry_initial_interval = "1s"
+  circuit_breaker_threshold = 5
+
+[[outputs.http]]
+
+[[outputs.http]]
+  retry_max_interval = "1m"
+  circuit_breaker_threshold = 10
+  circuit_breaker_timeout = "30s"
+`))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(c.Outputs))
+
+	require.Equal(t, time.Second, c.Outputs[0].Config.RetryInitialInterval)
+	require.Equal(t, time.Duration(0), c.Outputs[0].Config.RetryMaxInterval)
+	require.Equal(t, 5, c.Outputs[0].Config.CircuitBreakerThreshold)
+	require.Equal(t, time.Duration(0), c.Outputs[0].Config.CircuitBreakerTimeout)
+
+	require.Equal(t, time.Second, c.Outputs[1].Config.RetryInitialInterval)
+	require.Equal(t, time.Minute, c.Outputs[1].Config.RetryMaxInterval)
+	require.Equal(t, 10, c.Outputs[1].Config.CircuitBreakerThreshold)
+	require.Equal(t, 30*time.Second, c.Outputs[1].Config.CircuitBreakerTimeout)
+}
+
+func TestConfig_PluginID(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.http_listener_v2]]
+  service_address = ":8080"
+  methods = ["POST", "PUT"]
+  [inputs.http_listener_v2.tags]
+    a = "1"
+    b = "2"
+
+[[inputs.http_listener_v2]]
+  methods = ["POST", "PUT"]
+  service_address = ":8080"
+  [inputs.http_listener_v2.tags]
+    b = "2"
+    a = "1"
+
+[[inputs.http_listener_v2]]
+  service_address = ":8081"
+  methods = ["POST", "PUT"]
+  [inputs.http_listener_v2.tags]
+    a = "1"
+    b = "2"
+`))
+	require.NoError(t, err)
+	require.Equal(t, 3, len(c.Inputs))
+
+	require.NotEmpty(t, c.Inputs[0].ID)
+	require.Equal(t, c.Inputs[0].ID, c.Inputs[1].ID)
+	require.NotEqual(t, c.Inputs[0].ID, c.Inputs[2].ID)
+}
+
+func TestConfig_MetricPass(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  metricpass = 'fields.value > 1 && tags.host != "a"'
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Inputs, 1)
+	require.Equal(t, `fields.value > 1 && tags.host != "a"`, c.Inputs[0].Config.Filter.MetricPass)
+	require.True(t, c.Inputs[0].Config.Filter.IsActive())
+
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  metricpass = 'fields.value >'
+`))
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/stretchr/testify/require"
+)
+
+type groupOutput struct {
+	Retries int `toml:"retries"`
+
+	outputs []*models.RunningOutput
+}
+
+func (g *groupOutput) SampleConfig() string                  { return "" }
+func (g *groupOutput) Description() string                   { return "" }
+func (g *groupOutput) Connect() error                        { return nil }
+func (g *groupOutput) Close() error                          { return nil }
+func (g *groupOutput) Write(metrics []telegraf.Metric) error { return nil }
+func (g *groupOutput) Stop()                                 {}
+func (g *groupOutput) AddOutput(output *models.RunningOutput) {
+	g.outputs = append(g.outputs, output)
+}
+
+func init() {
+	outputs.Add("group_test", func() telegraf.Output { return &groupOutput{} })
+}
+
+func TestConfig_OutputGroup(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[agent]
+  buffer_directory = "/var/lib/telegraf/buffer"
+
+[[outputs.group_test]]
+  retries = 2
+  namepass = ["cpu"]
+
+  [[outputs.group_test.outputs.http]]
+    alias = "primary"
+    url = "http://primary.example.com"
+
+  [[outputs.group_test.outputs.group_test]]
+    [[outputs.group_test.outputs.group_test.outputs.http]]
+      alias = "nested"
+
+  [[outputs.group_test.outputs.http]]
+    alias = "secondary"
+    url = "http://secondary.example.com"
+    circuit_breaker_threshold = 3
+`))
+	require.NoError(t, err)
+	require.Len(t, c.Outputs, 1)
+
+	ro := c.Outputs[0]
+	require.Equal(t, "/var/lib/telegraf/buffer", ro.Config.BufferDirectory)
+	require.Equal(t, []string{"cpu"}, ro.Config.Filter.NamePass)
+
+	group := ro.Output.(*groupOutput)
+	require.Equal(t, 2, group.Retries)
+	require.Len(t, group.outputs, 3)
+	require.Equal(t, "primary", group.outputs[0].Config.Alias)
+	require.Equal(t, "", group.outputs[0].Config.BufferDirectory)
+	require.Equal(t, "group_test", group.outputs[1].Config.Name)
+	require.Len(t, group.outputs[1].Output.(*groupOutput).outputs, 1)
+	require.Equal(t, "secondary", group.outputs[2].Config.Alias)
+	require.Equal(t, 3, group.outputs[2].Config.CircuitBreakerThreshold)
+
+	// The ID of the group changes with its outputs.
+	id := ro.ID
+	c = NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[outputs.group_test]]
+  retries = 2
+  namepass = ["cpu"]
+
+  [[outputs.group_test.outputs.http]]
+    alias = "primary"
+    url = "http://primary.example.com"
+`))
+	require.NoError(t, err)
+	require.NotEqual(t, id, c.Outputs[0].ID)
+}
+
+func TestConfig_OutputGroupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "unknown output",
+			data: `
+[[outputs.group_test]]
+  [[outputs.group_test.outputs.nonexistent]]
+`,
+		},
+		{
+			name: "unused field",
+			data: `
+[[outputs.group_test]]
+  [[outputs.group_test.outputs.http]]
+    nonexistent = true
+`,
+		},
+		{
+			name: "buffer directory",
+			data: `
+[[outputs.group_test]]
+  [[outputs.group_test.outputs.http]]
+    buffer_directory = "/tmp"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			require.Error(t, c.LoadConfigData([]byte(tt.data)))
+		})
+	}
+}
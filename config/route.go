@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/toml/ast"
+)
+
+// RouteConfig is the configuration of a [[route]].  Plugins are referred to
+// by their alias, or by their name if they have no alias.
+type RouteConfig struct {
+	Name        string   `toml:"name"`
+	Inputs      []string `toml:"inputs"`
+	Processors  []string `toml:"processors"`
+	Aggregators []string `toml:"aggregators"`
+	Outputs     []string `toml:"outputs"`
+}
+
+// Route is a pipeline of plugins: metrics from the inputs are processed by
+// the processors and aggregators and written to the outputs.
+type Route struct {
+	Name          string
+	Inputs        []*models.RunningInput
+	Processors    models.RunningProcessors
+	AggProcessors models.RunningProcessors
+	Aggregators   []*models.RunningAggregator
+	Outputs       []*models.RunningOutput
+}
+
+// HasInput returns true if the input is part of the route.
+func (r *Route) HasInput(input *models.RunningInput) bool {
+	for _, ri := range r.Inputs {
+		if ri == input {
+			return true
+		}
+	}
+	return false
+}
+
+// HasOutput returns true if the output is part of the route.
+func (r *Route) HasOutput(output *models.RunningOutput) bool {
+	for _, ro := range r.Outputs {
+		if ro == output {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) addRoute(table *ast.Table) error {
+	rc := &RouteConfig{}
+	if err := c.toml.UnmarshalTable(table, rc); err != nil {
+		return err
+	}
+	if len(c.UnusedFields) > 0 {
+		return fmt.Errorf("line %d: configuration specified the fields %q, but they weren't used", table.Line, keys(c.UnusedFields))
+	}
+
+	if rc.Name == "" {
+		return fmt.Errorf("line %d: route must have a name", table.Line)
+	}
+	for _, r := range c.RouteConfigs {
+		if r.Name == rc.Name {
+			return fmt.Errorf("line %d: duplicate route %q", table.Line, rc.Name)
+		}
+	}
+	if len(rc.Inputs) == 0 {
+		return fmt.Errorf("route %q has no inputs", rc.Name)
+	}
+	if len(rc.Outputs) == 0 {
+		return fmt.Errorf("route %q has no outputs", rc.Name)
+	}
+
+	c.RouteConfigs = append(c.RouteConfigs, rc)
+	return nil
+}
+
+// Routes returns the routes between the plugins.  The first route is the
+// default route, named "", made up of the plugins not referred to by any
+// [[route]]; without any [[route]] it holds all plugins.  It is an error for
+// the default route to have inputs but no outputs.
+//
+// Inputs and outputs may be part of several routes, while processors and
+// aggregators may only be part of one.
+func (c *Config) Routes() ([]*Route, error) {
+	routedInputs := make(map[*models.RunningInput]bool)
+	routedOutputs := make(map[*models.RunningOutput]bool)
+	routedProcessors := make(map[*models.RunningProcessor]string)
+	routedAggregators := make(map[*models.RunningAggregator]string)
+
+	routes := make([]*Route, 0, len(c.RouteConfigs)+1)
+	for _, rc := range c.RouteConfigs {
+		route := &Route{Name: rc.Name}
+
+		for _, ref := range rc.Inputs {
+			var found bool
+			for _, input := range c.Inputs {
+				if RefersTo(ref, input.Config.Name, input.Config.Alias) {
+					route.Inputs = append(route.Inputs, input)
+					routedInputs[input] = true
+					found = true
+				}
+			}
+			if !found && len(c.InputFilters) == 0 {
+				return nil, fmt.Errorf("route %q: no input %q", rc.Name, ref)
+			}
+		}
+
+		for _, ref := range rc.Outputs {
+			var found bool
+			for _, output := range c.Outputs {
+				if RefersTo(ref, output.Config.Name, output.Config.Alias) {
+					route.Outputs = append(route.Outputs, output)
+					routedOutputs[output] = true
+					found = true
+				}
+			}
+			if !found && len(c.OutputFilters) == 0 {
+				return nil, fmt.Errorf("route %q: no output %q", rc.Name, ref)
+			}
+		}
+
+		for _, ref := range rc.Processors {
+			var found bool
+			for _, processors := range []models.RunningProcessors{c.Processors, c.AggProcessors} {
+				for _, processor := range processors {
+					if !RefersTo(ref, processor.Config.Name, processor.Config.Alias) {
+						continue
+					}
+					if other, ok := routedProcessors[processor]; ok {
+						return nil, fmt.Errorf("route %q: processor %q is already part of route %q",
+							rc.Name, ref, other)
+					}
+					routedProcessors[processor] = rc.Name
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("route %q: no processor %q", rc.Name, ref)
+			}
+		}
+		route.Processors = routeProcessors(c.Processors, routedProcessors, rc.Name)
+		route.AggProcessors = routeProcessors(c.AggProcessors, routedProcessors, rc.Name)
+
+		for _, ref := range rc.Aggregators {
+			var found bool
+			for _, aggregator := range c.Aggregators {
+				if !RefersTo(ref, aggregator.Config.Name, aggregator.Config.Alias) {
+					continue
+				}
+				if other, ok := routedAggregators[aggregator]; ok {
+					return nil, fmt.Errorf("route %q: aggregator %q is already part of route %q",
+						rc.Name, ref, other)
+				}
+				routedAggregators[aggregator] = rc.Name
+				route.Aggregators = append(route.Aggregators, aggregator)
+				found = true
+			}
+			if !found {
+				return nil, fmt.Errorf("route %q: no aggregator %q", rc.Name, ref)
+			}
+		}
+
+		routes = append(routes, route)
+	}
+
+	// The default route has the plugins not part of any other route.
+	route := &Route{}
+	for _, input := range c.Inputs {
+		if !routedInputs[input] {
+			route.Inputs = append(route.Inputs, input)
+		}
+	}
+	for _, output := range c.Outputs {
+		if !routedOutputs[output] {
+			route.Outputs = append(route.Outputs, output)
+		}
+	}
+	// Metrics of inputs not part of any route would be dropped when all
+	// outputs are part of a route.
+	if len(route.Inputs) > 0 && len(route.Outputs) == 0 && len(c.Outputs) > 0 && len(c.OutputFilters) == 0 {
+		names := make([]string, 0, len(route.Inputs))
+		for _, input := range route.Inputs {
+			names = append(names, input.LogName())
+		}
+		return nil, fmt.Errorf("inputs %s are not part of any route and all outputs are part of a route",
+			strings.Join(names, ", "))
+	}
+	route.Processors = routeProcessors(c.Processors, routedProcessors, "")
+	route.AggProcessors = routeProcessors(c.AggProcessors, routedProcessors, "")
+	for _, aggregator := range c.Aggregators {
+		if _, ok := routedAggregators[aggregator]; !ok {
+			route.Aggregators = append(route.Aggregators, aggregator)
+		}
+	}
+
+	return append([]*Route{route}, routes...), nil
+}
+
+// routeProcessors returns the processors that are part of the named route,
+// in the order of the processors.
+func routeProcessors(
+	processors models.RunningProcessors,
+	routed map[*models.RunningProcessor]string,
+	name string,
+) models.RunningProcessors {
+	result := make(models.RunningProcessors, 0, len(processors))
+	for _, processor := range processors {
+		if routed[processor] == name {
+			result = append(result, processor)
+		}
+	}
+	return result
+}
+
+// RefersTo returns true if the reference refers to a plugin with the name and
+// alias.
+func RefersTo(ref, name, alias string) bool {
+	if alias != "" {
+		return ref == alias
+	}
+	return ref == name
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"testing"
+
+	_ "github.com/influxdata/telegraf/plugins/aggregators/minmax"
+	_ "github.com/influxdata/telegraf/plugins/processors/rename"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_RoutesDefault(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+[[inputs.exec]]
+[[processors.rename]]
+[[aggregators.minmax]]
+[[outputs.http]]
+`))
+	require.NoError(t, err)
+
+	routes, err := c.Routes()
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+
+	route := routes[0]
+	require.Equal(t, "", route.Name)
+	require.Equal(t, c.Inputs, route.Inputs)
+	require.Equal(t, c.Processors, route.Processors)
+	require.Equal(t, c.AggProcessors, route.AggProcessors)
+	require.Equal(t, c.Aggregators, route.Aggregators)
+	require.Equal(t, c.Outputs, route.Outputs)
+}
+
+func TestConfig_Routes(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[route]]
+  name = "app"
+  inputs = ["memcached", "app"]
+  processors = ["rename"]
+  aggregators = ["minmax"]
+  outputs = ["app"]
+
+[[route]]
+  name = "system"
+  inputs = ["memcached"]
+  outputs = ["system"]
+
+[[inputs.memcached]]
+[[inputs.exec]]
+  alias = "app"
+[[inputs.exec]]
+
+[[processors.rename]]
+[[processors.rename]]
+  alias = "default"
+
+[[aggregators.minmax]]
+
+[[outputs.http]]
+  alias = "app"
+[[outputs.http]]
+  alias = "system"
+[[outputs.http]]
+`))
+	require.NoError(t, err)
+
+	routes, err := c.Routes()
+	require.NoError(t, err)
+	require.Len(t, routes, 3)
+
+	def, app, system := routes[0], routes[1], routes[2]
+
+	require.Equal(t, "", def.Name)
+	require.Len(t, def.Inputs, 1)
+	require.Equal(t, "exec", def.Inputs[0].Config.Name)
+	require.Equal(t, "", def.Inputs[0].Config.Alias)
+	require.Len(t, def.Processors, 1)
+	require.Equal(t, "default", def.Processors[0].Config.Alias)
+	require.Len(t, def.AggProcessors, 1)
+	require.Equal(t, "default", def.AggProcessors[0].Config.Alias)
+	require.Len(t, def.Aggregators, 0)
+	require.Len(t, def.Outputs, 1)
+	require.Equal(t, "", def.Outputs[0].Config.Alias)
+
+	require.Equal(t, "app", app.Name)
+	require.Len(t, app.Inputs, 2)
+	require.Equal(t, "memcached", app.Inputs[0].Config.Name)
+	require.Equal(t, "app", app.Inputs[1].Config.Alias)
+	require.Len(t, app.Processors, 1)
+	require.Equal(t, "", app.Processors[0].Config.Alias)
+	require.Len(t, app.AggProcessors, 1)
+	require.Len(t, app.Aggregators, 1)
+	require.Len(t, app.Outputs, 1)
+	require.Equal(t, "app", app.Outputs[0].Config.Alias)
+
+	require.Equal(t, "system", system.Name)
+	require.Len(t, system.Inputs, 1)
+	require.Same(t, app.Inputs[0], system.Inputs[0])
+	require.True(t, system.HasInput(app.Inputs[0]))
+	require.False(t, system.HasInput(app.Inputs[1]))
+	require.Len(t, system.Processors, 0)
+	require.Len(t, system.Outputs, 1)
+	require.True(t, system.HasOutput(system.Outputs[0]))
+	require.False(t, system.HasOutput(app.Outputs[0]))
+}
+
+func TestConfig_RouteErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "missing name",
+			data: `
+[[route]]
+  inputs = ["memcached"]
+  outputs = ["http"]
+`,
+		},
+		{
+			name: "duplicate name",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["memcached"]
+  outputs = ["http"]
+[[route]]
+  name = "app"
+  inputs = ["memcached"]
+  outputs = ["http"]
+`,
+		},
+		{
+			name: "no outputs",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["memcached"]
+`,
+		},
+		{
+			name: "unknown field",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["memcached"]
+  outputs = ["http"]
+  processor = ["rename"]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			err := c.LoadConfigData([]byte(tt.data + `
+[[inputs.memcached]]
+[[outputs.http]]
+`))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestConfig_RoutesInvalidReference(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "unknown input",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["exec"]
+  outputs = ["http"]
+`,
+		},
+		{
+			name: "unknown processor",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["memcached"]
+  processors = ["printer"]
+  outputs = ["http"]
+`,
+		},
+		{
+			name: "default route without outputs",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["exec"]
+  outputs = ["http"]
+[[inputs.exec]]
+`,
+		},
+		{
+			name: "processor in two routes",
+			data: `
+[[route]]
+  name = "app"
+  inputs = ["memcached"]
+  processors = ["rename"]
+  outputs = ["http"]
+[[route]]
+  name = "system"
+  inputs = ["memcached"]
+  processors = ["rename"]
+  outputs = ["http"]
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			err := c.LoadConfigData([]byte(tt.data + `
+[[inputs.memcached]]
+[[processors.rename]]
+[[outputs.http]]
+`))
+			require.NoError(t, err)
+
+			_, err = c.Routes()
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+	"github.com/influxdata/toml/ast"
+)
+
+// secretRefRe matches a secret reference, @{id:path} or @{id:path#key}, or
+// the escape @@{ of a literal @{.
+var secretRefRe = regexp.MustCompile(`@@\{|@\{([\w.-]+):([^#}]+)(?:#([^}]*))?\}`)
+
+// secretIDKey keys the digest of the secrets in plugin IDs, so the ID of a
+// plugin changes when a secret does without revealing the secret.
+var secretIDKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}()
+
+// LoadSecretStores loads only the secret stores of the config file, without
+// resolving secrets or loading any other plugins.
+func (c *Config) LoadSecretStores(path string) error {
+	var err error
+	if path == "" {
+		if path, err = getDefaultConfigPath(); err != nil {
+			return err
+		}
+	}
+	data, err := loadConfig(path)
+	if err != nil {
+		return fmt.Errorf("Error loading config file %s: %w", path, err)
+	}
+	tbl, err := parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("Error loading config file %s: %w", path, err)
+	}
+
+	val, ok := tbl.Fields["secretstores"]
+	if !ok {
+		return nil
+	}
+	err = c.loadTable(&ast.Table{Fields: map[string]interface{}{"secretstores": val}})
+	if err != nil {
+		return fmt.Errorf("Error loading config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadSecretStoresDirectory loads only the secret stores of the config files
+// found in the specified path, recursively.
+func (c *Config) LoadSecretStoresDirectory(path string) error {
+	return walkConfigDirectory(path, c.LoadSecretStores)
+}
+
+func (c *Config) addSecretStore(name string, table *ast.Table) error {
+	creator, ok := secretstores.SecretStores[name]
+	if !ok {
+		return fmt.Errorf("Undefined but requested secret store: %s", name)
+	}
+
+	id := name
+	c.getFieldString(table, "id", &id)
+	delete(table.Fields, "id")
+	if _, ok := c.SecretStores[id]; ok {
+		return fmt.Errorf("duplicate secret store %q", id)
+	}
+
+	store := creator()
+	if err := c.toml.UnmarshalTable(table, store); err != nil {
+		return err
+	}
+	if len(c.UnusedFields) > 0 {
+		return fmt.Errorf("line %d: configuration specified the fields %q, but they weren't used", table.Line, keys(c.UnusedFields))
+	}
+
+	if init, ok := store.(telegraf.Initializer); ok {
+		if err := init.Init(); err != nil {
+			return fmt.Errorf("could not initialize secret store %s: %v", id, err)
+		}
+	}
+
+	c.SecretStores[id] = store
+	return nil
+}
+
+// getSecret returns a secret from the store with the id.  The file store is
+// available as "file" without being configured.
+func (c *Config) getSecret(id, path, key string) (string, error) {
+	store, ok := c.SecretStores[id]
+	if !ok {
+		creator, ok := secretstores.SecretStores[id]
+		if id != "file" || !ok {
+			return "", fmt.Errorf("unknown secret store %q", id)
+		}
+		store = creator()
+		c.SecretStores[id] = store
+	}
+	return store.Get(path, key)
+}
+
+// resolveSecrets replaces the secret references in the strings of the table
+// by the secrets.
+func (c *Config) resolveSecrets(tbl *ast.Table) error {
+	for _, val := range tbl.Fields {
+		switch v := val.(type) {
+		case *ast.Table:
+			if err := c.resolveSecrets(v); err != nil {
+				return err
+			}
+		case []*ast.Table:
+			for _, t := range v {
+				if err := c.resolveSecrets(t); err != nil {
+					return err
+				}
+			}
+		case *ast.KeyValue:
+			if err := c.resolveSecretValue(v.Value); err != nil {
+				return fmt.Errorf("line %d: %v", v.Line, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) resolveSecretValue(value ast.Value) error {
+	switch v := value.(type) {
+	case *ast.Array:
+		for _, elem := range v.Value {
+			if err := c.resolveSecretValue(elem); err != nil {
+				return err
+			}
+		}
+	case *ast.Table:
+		return c.resolveSecrets(v)
+	case *ast.String:
+		if !strings.Contains(v.Value, "@{") {
+			return nil
+		}
+
+		var err error
+		var hasSecret bool
+		resolved := secretRefRe.ReplaceAllStringFunc(v.Value, func(ref string) string {
+			if ref == "@@{" {
+				return "@{"
+			}
+			hasSecret = true
+			m := secretRefRe.FindStringSubmatch(ref)
+			secret, serr := c.getSecret(m[1], m[2], m[3])
+			if serr != nil && err == nil {
+				err = fmt.Errorf("secret %s: %v", ref, serr)
+			}
+			return secret
+		})
+		if err != nil {
+			return err
+		}
+		if hasSecret {
+			c.secretRefs[v] = v.Value
+		}
+		v.Value = resolved
+	}
+	return nil
+}
+
+// secretDigest returns a keyed digest of a secret.
+func secretDigest(secret string) []byte {
+	mac := hmac.New(sha256.New, secretIDKey)
+	mac.Write([]byte(secret))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/plugins/inputs/memcached"
+	_ "github.com/influxdata/telegraf/plugins/secretstores/file"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSecret(t *testing.T, dir, name, value string) {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(value), 0600))
+}
+
+func TestConfig_Secrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeSecret(t, dir, "password", "s3cr3t\n")
+	writeSecret(t, dir, "creds.json", `{"user": "telegraf"}`)
+
+	c := NewConfig()
+	err = c.LoadConfigData([]byte(`
+[[secretstores.file]]
+  id = "secrets"
+  directory = "` + dir + `"
+
+[[inputs.memcached]]
+  servers = ["@{secrets:creds.json#user}:@{file:` + filepath.Join(dir, "password") + `}@localhost"]
+`))
+	require.NoError(t, err)
+	require.Len(t, c.SecretStores, 2)
+	require.Contains(t, c.SecretStores, "secrets")
+	require.Contains(t, c.SecretStores, "file")
+
+	require.Len(t, c.Inputs, 1)
+	input := c.Inputs[0].Input.(*memcached.Memcached)
+	require.Equal(t, []string{"telegraf:s3cr3t@localhost"}, input.Servers)
+}
+
+func TestConfig_SecretsEscape(t *testing.T) {
+	c := NewConfig()
+	err := c.LoadConfigData([]byte(`
+[[inputs.memcached]]
+  servers = ["@@{x:y}", "@@{@{file:/nonexistent"]
+`))
+	require.NoError(t, err)
+	input := c.Inputs[0].Input.(*memcached.Memcached)
+	require.Equal(t, []string{"@{x:y}", "@{@{file:/nonexistent"}, input.Servers)
+}
+
+func TestConfig_SecretsPluginID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	load := func() string {
+		c := NewConfig()
+		err := c.LoadConfigData([]byte(`
+[[secretstores.file]]
+  directory = "` + dir + `"
+
+[[inputs.memcached]]
+  servers = ["@{file:server}"]
+`))
+		require.NoError(t, err)
+		return c.Inputs[0].ID
+	}
+
+	writeSecret(t, dir, "server", "localhost:11211")
+	id := load()
+	require.Equal(t, id, load())
+
+	writeSecret(t, dir, "server", "localhost:11212")
+	require.NotEqual(t, id, load())
+}
+
+func TestConfig_SecretErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "unknown store",
+			data: `
+[[inputs.memcached]]
+  servers = ["@{vault:secret#key}"]
+`,
+		},
+		{
+			name: "missing secret",
+			data: `
+[[inputs.memcached]]
+  servers = ["@{file:/nonexistent/secret}"]
+`,
+		},
+		{
+			name: "duplicate store",
+			data: `
+[[secretstores.file]]
+[[secretstores.file]]
+`,
+		},
+		{
+			name: "unknown field",
+			data: `
+[[secretstores.file]]
+  path = "/run/secrets"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfig()
+			require.Error(t, c.LoadConfigData([]byte(tt.data)))
+		})
+	}
+}
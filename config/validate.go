@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/toml/ast"
+)
+
+// ValidationError is an error in a configuration file.  Line is zero if the
+// error is not specific to a line.
+type ValidationError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate loads the config files, and the config files found in the
+// directories, and initializes the plugins without starting them.  Unlike
+// LoadConfig, which stops at the first error, each plugin is loaded on its own
+// and all errors found are returned.
+func (c *Config) Validate(files []string, dirs []string) []error {
+	v := &validator{config: c, lines: make(map[interface{}]*ValidationError)}
+
+	for _, path := range files {
+		if path == "" {
+			var err error
+			if path, err = getDefaultConfigPath(); err != nil {
+				v.errs = append(v.errs, err)
+				continue
+			}
+		}
+		v.validateFile(path)
+	}
+	for _, dir := range dirs {
+		err := walkConfigDirectory(dir, func(path string) error {
+			v.validateFile(path)
+			return nil
+		})
+		if err != nil {
+			v.errs = append(v.errs, err)
+		}
+	}
+
+	v.initPlugins()
+
+	if _, err := c.Routes(); err != nil {
+		v.errs = append(v.errs, err)
+	}
+	return v.errs
+}
+
+// validator records the file and line each plugin was loaded from, to report
+// errors initializing the plugin.
+type validator struct {
+	config *Config
+	lines  map[interface{}]*ValidationError
+	errs   []error
+}
+
+func (v *validator) validateFile(path string) {
+	c := v.config
+
+	data, err := loadConfig(path)
+	if err != nil {
+		v.errs = append(v.errs, &ValidationError{File: path, Err: err})
+		return
+	}
+	tbl, err := parseConfig(data)
+	if err != nil {
+		v.errs = append(v.errs, &ValidationError{File: path, Err: err})
+		return
+	}
+
+	for _, part := range splitTable(tbl) {
+		inputs, outputs := len(c.Inputs), len(c.Outputs)
+		processors, aggregators := len(c.Processors), len(c.Aggregators)
+
+		err := c.loadTable(part)
+		if err != nil {
+			v.errs = append(v.errs, &ValidationError{File: path, Line: part.Line, Err: err})
+
+			// Clear the errors of the part, so they are not reported for
+			// the next part.
+			c.UnusedFields = map[string]bool{}
+			c.errs = nil
+		}
+
+		// The processors are sorted after each part; the new processor may
+		// be anywhere in the list.
+		location := func() *ValidationError {
+			return &ValidationError{File: path, Line: part.Line}
+		}
+		for _, input := range c.Inputs[inputs:] {
+			v.lines[input] = location()
+		}
+		for _, output := range c.Outputs[outputs:] {
+			v.lines[output] = location()
+		}
+		if len(c.Processors) > processors {
+			for _, processor := range c.Processors {
+				if _, ok := v.lines[processor]; !ok {
+					v.lines[processor] = location()
+				}
+			}
+			for _, processor := range c.AggProcessors {
+				if _, ok := v.lines[processor]; !ok {
+					v.lines[processor] = location()
+				}
+			}
+		}
+		for _, aggregator := range c.Aggregators[aggregators:] {
+			v.lines[aggregator] = location()
+		}
+	}
+}
+
+// initPlugins runs the Init function on all plugins, in the order the agent
+// does.  Outputs are validated without opening their buffer log.
+func (v *validator) initPlugins() {
+	c := v.config
+
+	for _, input := range c.Inputs {
+		if err := input.Init(); err != nil {
+			v.initError(input, fmt.Errorf("could not initialize input %s: %v",
+				input.LogName(), err))
+		}
+	}
+	for _, processor := range c.Processors {
+		if err := processor.Init(); err != nil {
+			v.initError(processor, fmt.Errorf("could not initialize processor %s: %v",
+				processor.Config.Name, err))
+		}
+	}
+	for _, aggregator := range c.Aggregators {
+		if err := aggregator.Init(); err != nil {
+			v.initError(aggregator, fmt.Errorf("could not initialize aggregator %s: %v",
+				aggregator.Config.Name, err))
+		}
+	}
+	for _, processor := range c.AggProcessors {
+		// The processor copies for the aggregators have the same
+		// configuration, so their errors are already reported.
+		_ = processor.Init()
+	}
+	for _, output := range c.Outputs {
+		if err := output.Validate(); err != nil {
+			v.initError(output, fmt.Errorf("could not initialize output %s: %v",
+				output.Config.Name, err))
+		}
+	}
+}
+
+func (v *validator) initError(plugin interface{}, err error) {
+	location, ok := v.lines[plugin]
+	if !ok {
+		v.errs = append(v.errs, err)
+		return
+	}
+	v.errs = append(v.errs, &ValidationError{File: location.File, Line: location.Line, Err: err})
+}
+
+// splitTable splits the parsed config data into parts that can be loaded on
+// their own, in the order of the file: the agent settings, global tags and
+// secret stores, and each route and plugin.  The line of each part is the
+// line of its table.
+func splitTable(tbl *ast.Table) []*ast.Table {
+	globals := &ast.Table{Fields: make(map[string]interface{})}
+	var parts []*ast.Table
+	for name, val := range tbl.Fields {
+		switch name {
+		case "agent", "global_tags", "tags", "secretstores":
+			globals.Fields[name] = val
+			if line := fieldLine(val); globals.Line == 0 || line < globals.Line {
+				globals.Line = line
+			}
+		case "route":
+			subTables, ok := val.([]*ast.Table)
+			if !ok {
+				parts = append(parts, part(name, val, fieldLine(val)))
+				continue
+			}
+			for _, t := range subTables {
+				parts = append(parts, part(name, []*ast.Table{t}, t.Line))
+			}
+		case "inputs", "outputs", "processors", "aggregators", "plugins":
+			subTable, ok := val.(*ast.Table)
+			if !ok {
+				parts = append(parts, part(name, val, fieldLine(val)))
+				continue
+			}
+			for pluginName, pluginVal := range subTable.Fields {
+				subTables, ok := pluginVal.([]*ast.Table)
+				if !ok {
+					line := fieldLine(pluginVal)
+					plugin := part(pluginName, pluginVal, line)
+					parts = append(parts, part(name, plugin, line))
+					continue
+				}
+				for _, t := range subTables {
+					plugin := part(pluginName, []*ast.Table{t}, t.Line)
+					parts = append(parts, part(name, plugin, t.Line))
+				}
+			}
+		default:
+			parts = append(parts, part(name, val, fieldLine(val)))
+		}
+	}
+
+	sort.SliceStable(parts, func(i, j int) bool {
+		return parts[i].Line < parts[j].Line
+	})
+	if len(globals.Fields) > 0 {
+		parts = append([]*ast.Table{globals}, parts...)
+	}
+	return parts
+}
+
+// part returns a table with the single field.
+func part(name string, val interface{}, line int) *ast.Table {
+	return &ast.Table{
+		Line:   line,
+		Name:   name,
+		Fields: map[string]interface{}{name: val},
+	}
+}
+
+// fieldLine returns the line of a table field, or 0 if unknown.
+func fieldLine(val interface{}) int {
+	switch v := val.(type) {
+	case *ast.Table:
+		return v.Line
+	case []*ast.Table:
+		if len(v) > 0 {
+			return v[0].Line
+		}
+	case *ast.KeyValue:
+		return v.Line
+	}
+	return 0
+}
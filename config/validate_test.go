@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/stretchr/testify/require"
+)
+
+type validateInput struct{}
+
+func (i *validateInput) SampleConfig() string                  { return "" }
+func (i *validateInput) Description() string                   { return "" }
+func (i *validateInput) Gather(acc telegraf.Accumulator) error { return nil }
+func (i *validateInput) Init() error {
+	return errors.New("invalid setting")
+}
+
+func init() {
+	inputs.Add("validate_test", func() telegraf.Input { return &validateInput{} })
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := NewConfig()
+	errs := c.Validate([]string{"./testdata/invalid_plugins.toml"}, nil)
+	require.Len(t, errs, 4)
+
+	lines := make([]int, 0, len(errs))
+	for _, err := range errs {
+		var verr *ValidationError
+		require.True(t, errors.As(err, &verr), err.Error())
+		require.Equal(t, "./testdata/invalid_plugins.toml", verr.File)
+		lines = append(lines, verr.Line)
+	}
+	require.Equal(t, []int{1, 4, 12, 7}, lines)
+	require.Contains(t, errs[3].Error(), "could not initialize input inputs.validate_test: invalid setting")
+
+	// The plugins that could be built are loaded.
+	require.Len(t, c.Inputs, 3)
+	require.Equal(t, "http_listener_v2", c.Inputs[0].Config.Name)
+	require.Equal(t, "validate_test", c.Inputs[1].Config.Name)
+	require.Equal(t, "exec", c.Inputs[2].Config.Name)
+	require.Len(t, c.Outputs, 0)
+}
+
+func TestConfig_ValidateValid(t *testing.T) {
+	c := NewConfig()
+	errs := c.Validate([]string{"./testdata/single_plugin.toml"}, []string{"./testdata/subconfig"})
+	require.Len(t, errs, 0)
+	require.Len(t, c.Inputs, 4)
+}
+
+func TestConfig_ValidateMissingFile(t *testing.T) {
+	c := NewConfig()
+	errs := c.Validate([]string{"./testdata/missing.toml"}, nil)
+	require.Len(t, errs, 1)
+}
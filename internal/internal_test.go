@@ -7,7 +7,9 @@ import (
 	"io"
 	"io/ioutil"
 	"log"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
@@ -489,3 +491,22 @@ func TestProductToken(t *testing.T) {
 	re := regexp.MustCompile(`^Telegraf/[^\s]+ Go/\d+.\d+(.\d+)?$`)
 	require.True(t, re.MatchString(token), token)
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "state.json")
+
+	require.NoError(t, WriteFileAtomic(filename, []byte("first")))
+	require.NoError(t, WriteFileAtomic(filename, []byte("second")))
+	data, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(data))
+
+	// No temporary file is left behind, even on failure.
+	require.Error(t, WriteFileAtomic(filepath.Join(dir, "missing", "state.json"), nil))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -31,4 +31,8 @@ type ClientConfig struct {
 	EngineID     string `toml:"-"`
 	EngineBoots  uint32 `toml:"-"`
 	EngineTime   uint32 `toml:"-"`
+
+	// Paths of the MIB files used to translate OIDs; directories are
+	// searched recursively.
+	Path []string `toml:"path"`
 }
@@ -0,0 +1,229 @@
+package snmp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/telegraf"
+	"github.com/sleepinggenius2/gosmi"
+	"github.com/sleepinggenius2/gosmi/types"
+)
+
+// DefaultMibPath is the directory of the MIB files installed by net-snmp.
+var DefaultMibPath = []string{"/usr/share/snmp/mibs"}
+
+// The MIB modules are loaded once for all plugins, as the MIB parser keeps
+// them in global state.  It is not safe for concurrent use, so that all
+// access to it is made holding mibLock.
+var (
+	mibLock     sync.Mutex
+	mibDirs     []string
+	mibPaths    = map[string]bool{}
+	mibInitDone bool
+)
+
+// TableColumn is a column of a MIB table.
+type TableColumn struct {
+	Name string
+	// IsIndex tells if the column is part of the index of the table.
+	IsIndex bool
+}
+
+// LoadMibsFromPath loads the MIB modules of the files in the given paths and
+// their subdirectories.  Modules imported by them are looked up in all the
+// directories of the paths loaded so far.  Paths already loaded are skipped,
+// so that every plugin can load its paths on init.  Files which are not valid
+// MIB modules are logged and skipped.
+func LoadMibsFromPath(paths []string, log telegraf.Logger) error {
+	mibLock.Lock()
+	defer mibLock.Unlock()
+
+	if !mibInitDone {
+		gosmi.Init()
+		mibInitDone = true
+	}
+
+	var files []string
+	for _, path := range paths {
+		if mibPaths[path] {
+			continue
+		}
+
+		// The path is only recorded once it was read completely, so that
+		// a path which couldn't be read is read again by the next plugin.
+		var dirs, pathFiles []string
+		err := filepath.Walk(path, func(name string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				dirs = append(dirs, name)
+			} else if info.Mode().IsRegular() {
+				pathFiles = append(pathFiles, info.Name())
+			}
+			return nil
+		})
+		if os.IsNotExist(err) {
+			log.Warnf("MIB path %q does not exist", path)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading MIB path %q: %w", path, err)
+		}
+		mibPaths[path] = true
+		mibDirs = append(mibDirs, dirs...)
+		files = append(files, pathFiles...)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	// Setting the path replaces the default path of the parser, whose
+	// directories may not exist.
+	gosmi.SetPath(strings.Join(mibDirs, string(os.PathListSeparator)))
+	for _, file := range files {
+		// Files named after their module may have been loaded already as
+		// an import of another module.
+		if gosmi.IsLoaded(strings.SplitN(file, ".", 2)[0]) {
+			continue
+		}
+		if _, err := gosmi.LoadModule(file); err != nil {
+			log.Warnf("Loading MIB file %q failed: %v", file, err)
+		}
+	}
+	return nil
+}
+
+// TranslateOID resolves an OID, given either numerically or by name, with
+// the loaded MIB modules.  It returns the name of the MIB module of the
+// object, the numeric OID, the name of the object followed by the index in
+// the OID, and the conversion of the textual convention of the object.  A
+// numeric OID of no object of a MIB module is returned as is, without error.
+func TranslateOID(oid string) (mibName string, oidNum string, oidText string, conversion string, err error) {
+	mibLock.Lock()
+	defer mibLock.Unlock()
+
+	if isNumeric(oid) {
+		oidNum = oid
+		if !strings.HasPrefix(oidNum, ".") {
+			oidNum = "." + oidNum
+		}
+	} else {
+		oidNum, err = resolveName(oid)
+		if err != nil {
+			return "", "", "", "", err
+		}
+	}
+
+	node, suffix, ok := lookupNode(oidNum)
+	if !ok {
+		return "", oidNum, oidNum, "", nil
+	}
+	if node.Type != nil {
+		conversion = textualConvention(node.Type.Name)
+	}
+	return node.GetModule().Name, oidNum, node.Name + suffix, conversion, nil
+}
+
+// TranslateTable resolves the OID of a table like TranslateOID, and returns
+// the columns of the table which can be read.
+func TranslateTable(oid string) (mibName string, oidNum string, oidText string, columns []TableColumn, err error) {
+	mibName, oidNum, oidText, _, err = TranslateOID(oid)
+	if err != nil {
+		return "", "", "", nil, err
+	}
+
+	mibLock.Lock()
+	defer mibLock.Unlock()
+
+	node, suffix, ok := lookupNode(oidNum)
+	if !ok || suffix != "" || node.Kind != types.NodeTable {
+		return "", "", "", nil, fmt.Errorf("%s is not a table", oid)
+	}
+
+	table := node.AsTable()
+	index := map[string]bool{}
+	for _, column := range table.Index {
+		index[column.Name] = true
+	}
+	for _, name := range table.ColumnOrder {
+		// Columns not accessible are only part of the index and can't
+		// be walked.
+		if table.Columns[name].Access == types.AccessNotAccessible {
+			continue
+		}
+		columns = append(columns, TableColumn{Name: name, IsIndex: index[name]})
+	}
+	if len(columns) == 0 {
+		return "", "", "", nil, fmt.Errorf("could not find any columns in table %s", oid)
+	}
+	return mibName, oidNum, oidText, columns, nil
+}
+
+// resolveName resolves an OID given by name, such as "IF-MIB::ifDescr.1" or
+// ".iso.3.6", into a numeric OID.
+func resolveName(oid string) (string, error) {
+	var modules []gosmi.SmiModule
+	if i := strings.Index(oid, "::"); i != -1 {
+		module, err := gosmi.GetModule(oid[:i])
+		if err != nil {
+			return "", fmt.Errorf("could not find MIB module %q", oid[:i])
+		}
+		modules = append(modules, module)
+		oid = oid[i+2:]
+	}
+
+	var oidNum string
+	for _, part := range strings.Split(strings.TrimPrefix(oid, "."), ".") {
+		if _, err := strconv.ParseUint(part, 10, 32); err == nil {
+			oidNum += "." + part
+			continue
+		}
+		node, err := gosmi.GetNode(part, modules...)
+		if err != nil {
+			return "", fmt.Errorf("could not find object %q", part)
+		}
+		oidNum = "." + node.RenderNumeric()
+	}
+	return oidNum, nil
+}
+
+// lookupNode returns the object of a MIB module with the longest OID that
+// is a prefix of a numeric OID, and the rest of the OID.
+func lookupNode(oidNum string) (gosmi.SmiNode, string, bool) {
+	oid, err := types.OidFromString(oidNum)
+	if err != nil {
+		return gosmi.SmiNode{}, "", false
+	}
+	// The well-known objects, such as iso, are not of a MIB module.
+	node, err := gosmi.GetNodeByOID(oid)
+	if err != nil || node.GetModule().Name == "<well-known>" {
+		return gosmi.SmiNode{}, "", false
+	}
+
+	var suffix string
+	for _, id := range oid[len(node.Oid):] {
+		suffix += "." + strconv.FormatUint(uint64(id), 10)
+	}
+	return node, suffix, true
+}
+
+// textualConvention returns the conversion of the values of a textual
+// convention.
+func textualConvention(tc string) string {
+	switch tc {
+	case "MacAddress", "PhysAddress":
+		return "hwaddr"
+	case "InetAddressIPv4", "InetAddressIPv6", "InetAddress", "IPSIpAddress":
+		return "ipaddr"
+	}
+	return ""
+}
+
+func isNumeric(oid string) bool {
+	return oid != "" && strings.Trim(oid, ".0123456789") == ""
+}
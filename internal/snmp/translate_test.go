@@ -0,0 +1,154 @@
+package snmp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func loadTestMibs(t *testing.T) {
+	require.NoError(t, LoadMibsFromPath([]string{"testdata/mibs"}, testutil.Logger{}))
+}
+
+func TestResolveName(t *testing.T) {
+	loadTestMibs(t)
+
+	tests := []struct {
+		name     string
+		oid      string
+		expected string
+	}{
+		{name: "module and index", oid: "IF-MIB::ifDescr.1", expected: ".1.3.6.1.2.1.2.2.1.2.1"},
+		{name: "module", oid: "IF-MIB::ifTable", expected: ".1.3.6.1.2.1.2.2"},
+		{name: "without module", oid: "ifPhysAddress", expected: ".1.3.6.1.2.1.2.2.1.6"},
+		{name: "dotted names", oid: ".iso.3.6", expected: ".1.3.6"},
+		{name: "object and numbers", oid: "TEST::testOID.0.1", expected: ".1.0.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mibLock.Lock()
+			defer mibLock.Unlock()
+
+			oidNum, err := resolveName(tt.oid)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, oidNum)
+		})
+	}
+}
+
+func TestResolveNameErrors(t *testing.T) {
+	loadTestMibs(t)
+
+	for _, oid := range []string{
+		"UNKNOWN-MIB::ifDescr",
+		"IF-MIB::unknownObject",
+		"unknownObject.1",
+	} {
+		t.Run(oid, func(t *testing.T) {
+			mibLock.Lock()
+			defer mibLock.Unlock()
+
+			_, err := resolveName(oid)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLookupNode(t *testing.T) {
+	loadTestMibs(t)
+
+	tests := []struct {
+		name   string
+		oidNum string
+		node   string
+		suffix string
+		ok     bool
+	}{
+		{name: "object", oidNum: ".1.3.6.1.2.1.2.2.1.2", node: "ifDescr", ok: true},
+		{name: "index", oidNum: ".1.3.6.1.2.1.2.2.1.2.1", node: "ifDescr", suffix: ".1", ok: true},
+		{name: "multiple suffixes", oidNum: ".1.3.6.1.2.1.2.2.1.6.1.2", node: "ifPhysAddress", suffix: ".1.2", ok: true},
+		{name: "beyond the objects", oidNum: ".1.3.6.1.2.1.99", node: "mib-2", suffix: ".99", ok: true},
+		{name: "well-known", oidNum: ".1", ok: false},
+		{name: "invalid", oidNum: "not an oid", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mibLock.Lock()
+			defer mibLock.Unlock()
+
+			node, suffix, ok := lookupNode(tt.oidNum)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tt.node, node.Name)
+			require.Equal(t, tt.suffix, suffix)
+		})
+	}
+}
+
+func TestTranslateTable(t *testing.T) {
+	loadTestMibs(t)
+
+	mibName, oidNum, oidText, columns, err := TranslateTable("IF-MIB::ifTable")
+	require.NoError(t, err)
+	require.Equal(t, "IF-MIB", mibName)
+	require.Equal(t, ".1.3.6.1.2.1.2.2", oidNum)
+	require.Equal(t, "ifTable", oidText)
+	require.Equal(t, []TableColumn{
+		{Name: "ifIndex", IsIndex: true},
+		{Name: "ifDescr"},
+		{Name: "ifPhysAddress"},
+	}, columns)
+
+	_, _, _, columns, err = TranslateTable(".1.0.0.0")
+	require.NoError(t, err)
+	require.Equal(t, []TableColumn{
+		{Name: "server", IsIndex: true},
+		{Name: "connections"},
+		{Name: "latency"},
+		{Name: "description"},
+	}, columns)
+}
+
+func TestTranslateTableErrors(t *testing.T) {
+	loadTestMibs(t)
+
+	for _, oid := range []string{
+		"IF-MIB::ifDescr",
+		"IF-MIB::ifTable.1",
+		"UNKNOWN-MIB::ifTable",
+	} {
+		t.Run(oid, func(t *testing.T) {
+			_, _, _, _, err := TranslateTable(oid)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestLoadMibsFromPathMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mibs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// A path which doesn't exist yet is read again once it does.
+	path := filepath.Join(dir, "mibs")
+	require.NoError(t, LoadMibsFromPath([]string{path}, testutil.Logger{}))
+
+	require.NoError(t, os.Mkdir(path, 0755))
+	mib := []byte(`LATER-MIB DEFINITIONS ::= BEGIN
+laterOID OBJECT IDENTIFIER ::= { iso 5 }
+END
+`)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "LATER-MIB.txt"), mib, 0644))
+	require.NoError(t, LoadMibsFromPath([]string{path}, testutil.Logger{}))
+
+	_, oidNum, oidText, _, err := TranslateOID("LATER-MIB::laterOID")
+	require.NoError(t, err)
+	require.Equal(t, ".1.5", oidNum)
+	require.Equal(t, "laterOID", oidText)
+}
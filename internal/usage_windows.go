@@ -12,6 +12,9 @@ The commands & flags are:
 
   config              print out full sample configuration to stdout
   version             print the version to stdout
+  secrets set <id> <name>
+                      store a secret read from stdin in a secret store
+  secrets list <id>   list the secrets of a secret store
 
   --aggregator-filter <filter>   filter the aggregators to enable, separator is :
   --config <file>                configuration file to load
@@ -34,6 +37,7 @@ The commands & flags are:
   --test-wait                    wait up to this many seconds for service
                                  inputs to complete in test or once mode
   --usage <plugin>               print usage for a plugin, ie, 'telegraf --usage mysql'
+  --validate                     validate the configuration, print all errors found, and exit
   --version                      display the version and exit
 
   --console                      run as console application (windows only)
@@ -52,6 +56,12 @@ Examples:
   # run a single telegraf collection, outputting metrics to stdout
   telegraf --config telegraf.conf --test
 
+  # check a config file for errors
+  telegraf --config telegraf.conf --validate
+
+  # add a secret to the keyring secret store
+  telegraf --config telegraf.conf secrets set keyring influxdb_token < token.txt
+
   # run telegraf with all plugins defined in config file
   telegraf --config telegraf.conf
 
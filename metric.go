@@ -29,6 +29,44 @@ type Field struct {
 	Value interface{}
 }
 
+// Bucket is a bucket of a histogram.
+type Bucket struct {
+	// UpperBound is the inclusive upper bound of the bucket.
+	UpperBound float64
+
+	// Count is the cumulative count of the observations less than or equal
+	// to the upper bound.
+	Count uint64
+}
+
+// Quantile is a quantile of a summary and its value.
+type Quantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// Distribution is a histogram or summary of observations.  Histogram and
+// Summary metrics carry their distributions alongside the fields they are
+// flattened into, so outputs can write them without reassembling them from
+// the fields.
+type Distribution struct {
+	// Key identifies the distribution in the metric, it is usually the field
+	// key without the _bucket, _count or _sum suffix.  An empty key
+	// identifies the distribution of the metric as a whole.
+	Key string
+
+	// Count and Sum are the count and sum of the observations.
+	Count uint64
+	Sum   float64
+
+	// Buckets are the buckets of a histogram ordered by upper bound, the
+	// last of which has the upper bound +Inf.
+	Buckets []Bucket
+
+	// Quantiles are the quantiles of a summary ordered by quantile.
+	Quantiles []Quantile
+}
+
 // Metric is the type of data that is processed by Telegraf.  Input plugins,
 // and to a lesser degree, Processor and Aggregator plugins create new Metrics
 // and Output plugins write them.
@@ -103,6 +141,19 @@ type Metric interface {
 	// RemoveField removes the tag if it is set.
 	RemoveField(key string)
 
+	// DistributionList returns the histograms of a Histogram metric, or the
+	// summaries of a Summary metric, ordered by key.  The returned value
+	// should not be modified, use the AddDistribution or RemoveDistribution
+	// methods instead.
+	DistributionList() []*Distribution
+
+	// AddDistribution sets the distribution on the Metric.  If the Metric
+	// already has a distribution with the same key then it is replaced.
+	AddDistribution(d *Distribution)
+
+	// RemoveDistribution removes the distribution with the key if it is set.
+	RemoveDistribution(key string)
+
 	// SetTime sets the timestamp of the Metric.
 	SetTime(t time.Time)
 
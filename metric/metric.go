@@ -15,6 +15,8 @@ type metric struct {
 	fields []*telegraf.Field
 	tm     time.Time
 
+	distributions []*telegraf.Distribution
+
 	tp        telegraf.ValueType
 	aggregate bool
 }
@@ -83,6 +85,10 @@ func FromMetric(other telegraf.Metric) telegraf.Metric {
 	for i, field := range other.FieldList() {
 		m.fields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
 	}
+
+	for _, d := range other.DistributionList() {
+		m.distributions = append(m.distributions, copyDistribution(d))
+	}
 	return m
 }
 
@@ -227,6 +233,35 @@ func (m *metric) RemoveField(key string) {
 	}
 }
 
+func (m *metric) DistributionList() []*telegraf.Distribution {
+	return m.distributions
+}
+
+func (m *metric) AddDistribution(d *telegraf.Distribution) {
+	for i, distribution := range m.distributions {
+		if distribution.Key == d.Key {
+			m.distributions[i] = d
+			return
+		}
+	}
+
+	m.distributions = append(m.distributions, d)
+	sort.Slice(m.distributions, func(i, j int) bool {
+		return m.distributions[i].Key < m.distributions[j].Key
+	})
+}
+
+func (m *metric) RemoveDistribution(key string) {
+	for i, distribution := range m.distributions {
+		if distribution.Key == key {
+			copy(m.distributions[i:], m.distributions[i+1:])
+			m.distributions[len(m.distributions)-1] = nil
+			m.distributions = m.distributions[:len(m.distributions)-1]
+			return
+		}
+	}
+}
+
 func (m *metric) SetTime(t time.Time) {
 	m.tm = t
 }
@@ -248,6 +283,10 @@ func (m *metric) Copy() telegraf.Metric {
 	for i, field := range m.fields {
 		m2.fields[i] = &telegraf.Field{Key: field.Key, Value: field.Value}
 	}
+
+	for _, d := range m.distributions {
+		m2.distributions = append(m2.distributions, copyDistribution(d))
+	}
 	return m2
 }
 
@@ -281,6 +320,17 @@ func (m *metric) Reject() {
 func (m *metric) Drop() {
 }
 
+func copyDistribution(d *telegraf.Distribution) *telegraf.Distribution {
+	c := &telegraf.Distribution{Key: d.Key, Count: d.Count, Sum: d.Sum}
+	if d.Buckets != nil {
+		c.Buckets = append([]telegraf.Bucket(nil), d.Buckets...)
+	}
+	if d.Quantiles != nil {
+		c.Quantiles = append([]telegraf.Quantile(nil), d.Quantiles...)
+	}
+	return c
+}
+
 // Convert field to a supported type or nil if unconvertible
 func convertField(v interface{}) interface{} {
 	switch v := v.(type) {
@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -340,3 +341,37 @@ func TestCopyAggregate(t *testing.T) {
 	m2 := m1.Copy()
 	assert.True(t, m2.IsAggregate())
 }
+
+func TestAddDistribution(t *testing.T) {
+	m := baseMetric()
+
+	m.AddDistribution(&telegraf.Distribution{Key: "write", Count: 1})
+	m.AddDistribution(&telegraf.Distribution{Key: "read", Count: 2})
+	m.AddDistribution(&telegraf.Distribution{Key: "write", Count: 3})
+	require.Equal(t, []*telegraf.Distribution{
+		{Key: "read", Count: 2},
+		{Key: "write", Count: 3},
+	}, m.DistributionList())
+
+	m.RemoveDistribution("read")
+	m.RemoveDistribution("missing")
+	require.Equal(t, []*telegraf.Distribution{
+		{Key: "write", Count: 3},
+	}, m.DistributionList())
+}
+
+func TestCopyDistribution(t *testing.T) {
+	m1 := baseMetric()
+	m1.AddDistribution(&telegraf.Distribution{
+		Count:   2,
+		Sum:     1.5,
+		Buckets: []telegraf.Bucket{{UpperBound: 1, Count: 1}, {UpperBound: math.Inf(1), Count: 2}},
+	})
+
+	m2 := m1.Copy()
+	m2.DistributionList()[0].Buckets[0].Count = 2
+	require.Equal(t, uint64(1), m1.DistributionList()[0].Buckets[0].Count)
+
+	m3 := FromMetric(m1)
+	require.Equal(t, m1.DistributionList(), m3.DistributionList())
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/influxdata/telegraf"
@@ -24,6 +25,11 @@ type Buffer struct {
 	batchFirst int // index of the first metric in the batch
 	batchSize  int // number of metrics currently in the batch
 
+	wal       *wal     // optional write-ahead log
+	refs      []walRef // log references of the metrics in buf
+	batchRefs []walRef // log references of the metrics in the batch
+	log       telegraf.Logger
+
 	MetricsAdded   selfstat.Stat
 	MetricsWritten selfstat.Stat
 	MetricsDropped selfstat.Stat
@@ -105,10 +111,29 @@ func (b *Buffer) metricDropped(metric telegraf.Metric) {
 }
 
 func (b *Buffer) add(m telegraf.Metric) int {
+	var ref walRef
+	if b.wal != nil {
+		ref = b.wal.add(m)
+	}
+	return b.insert(m, ref)
+}
+
+func (b *Buffer) insert(m telegraf.Metric, ref walRef) int {
 	dropped := 0
+	// Make room in the write-ahead log by dropping the oldest metrics
+	if b.wal != nil {
+		for b.size > 0 && b.wal.overLimit() {
+			b.dropFirst()
+			dropped++
+		}
+	}
+
 	// Check if Buffer is full
 	if b.size == b.cap {
 		b.metricDropped(b.buf[b.last])
+		if b.wal != nil {
+			b.wal.remove(b.refs[b.last])
+		}
 		dropped++
 
 		if b.batchSize > 0 {
@@ -120,6 +145,9 @@ func (b *Buffer) add(m telegraf.Metric) int {
 	b.metricAdded()
 
 	b.buf[b.last] = m
+	if b.wal != nil {
+		b.refs[b.last] = ref
+	}
 	b.last = b.next(b.last)
 
 	if b.size == b.cap {
@@ -130,6 +158,17 @@ func (b *Buffer) add(m telegraf.Metric) int {
 	return dropped
 }
 
+// dropFirst drops the oldest metric in the buffer.
+func (b *Buffer) dropFirst() {
+	b.metricDropped(b.buf[b.first])
+	if b.wal != nil {
+		b.wal.remove(b.refs[b.first])
+	}
+	b.buf[b.first] = nil
+	b.first = b.next(b.first)
+	b.size--
+}
+
 // Add adds metrics to the buffer and returns number of dropped metrics.
 func (b *Buffer) Add(metrics ...telegraf.Metric) int {
 	b.Lock()
@@ -142,6 +181,7 @@ func (b *Buffer) Add(metrics ...telegraf.Metric) int {
 		}
 	}
 
+	b.commitWAL(false)
 	b.BufferSize.Set(int64(b.length()))
 	return dropped
 }
@@ -161,11 +201,17 @@ func (b *Buffer) Batch(batchSize int) []telegraf.Metric {
 
 	b.batchFirst = b.first
 	b.batchSize = outLen
+	if b.wal != nil {
+		b.batchRefs = make([]walRef, outLen)
+	}
 
 	batchIndex := b.batchFirst
 	for i := range out {
 		out[i] = b.buf[batchIndex]
 		b.buf[batchIndex] = nil
+		if b.wal != nil {
+			b.batchRefs[i] = b.refs[batchIndex]
+		}
 		batchIndex = b.next(batchIndex)
 	}
 
@@ -182,8 +228,12 @@ func (b *Buffer) Accept(batch []telegraf.Metric) {
 	for _, m := range batch {
 		b.metricWritten(m)
 	}
+	if b.wal != nil {
+		b.wal.remove(b.batchRefs...)
+	}
 
 	b.resetBatch()
+	b.commitWAL(true)
 	b.BufferSize.Set(int64(b.length()))
 }
 
@@ -210,14 +260,97 @@ func (b *Buffer) Reject(batch []telegraf.Metric) {
 	for i := range batch {
 		if i < skip {
 			b.metricDropped(batch[i])
+			if b.wal != nil {
+				b.wal.remove(b.batchRefs[i])
+			}
 		} else {
 			b.buf[re] = batch[i]
+			if b.wal != nil {
+				b.refs[re] = b.batchRefs[i]
+			}
 			re = b.next(re)
 		}
 	}
 
 	b.resetBatch()
+	b.commitWAL(true)
+	b.BufferSize.Set(int64(b.length()))
+}
+
+// OpenWAL backs the buffer with a write-ahead log stored at path so that
+// unwritten metrics survive a restart.  Metrics left in the log by a previous
+// run are restored into the buffer and the number restored is returned.
+//
+// The metrics kept in the log are limited to maxBytes, when exceeded the
+// oldest metrics are dropped.  A maxBytes of 0 disables the limit.
+func (b *Buffer) OpenWAL(path string, maxBytes int64, fsync string, log telegraf.Logger) (int, error) {
+	w, entries, err := openWAL(path, maxBytes, fsync, log)
+	if err != nil {
+		return 0, err
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.wal = w
+	b.refs = make([]walRef, b.cap)
+	b.log = log
+	for _, e := range entries {
+		w.live += e.ref.size
+		b.insert(e.metric, e.ref)
+	}
+
+	if err := b.compactWAL(); err != nil {
+		b.wal = nil
+		b.refs = nil
+		return 0, fmt.Errorf("writing buffer log %q: %w", path, err)
+	}
+
 	b.BufferSize.Set(int64(b.length()))
+	return b.size, nil
+}
+
+// Close closes the write-ahead log, if any.  Metrics remaining in the buffer
+// are kept in the log.
+func (b *Buffer) Close() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.wal == nil {
+		return nil
+	}
+	return b.wal.close()
+}
+
+// commitWAL writes pending records to the write-ahead log and compacts the log
+// if it has grown too large.  The log is only compacted when no batch is
+// outstanding, since the batch is not held by the buffer.
+func (b *Buffer) commitWAL(flush bool) {
+	if b.wal == nil {
+		return
+	}
+
+	if err := b.wal.commit(flush); err != nil {
+		b.log.Errorf("Writing buffer log: %v", err)
+		return
+	}
+
+	if b.batchRefs == nil && b.wal.needsCompaction() {
+		if err := b.compactWAL(); err != nil {
+			b.log.Errorf("Compacting buffer log: %v", err)
+		}
+	}
+}
+
+// compactWAL rewrites the write-ahead log with the metrics in the buffer.
+func (b *Buffer) compactWAL() error {
+	refs := make([]walRef, 0, b.size)
+	metrics := make([]telegraf.Metric, 0, b.size)
+	for i, index := 0, b.first; i < b.size; i, index = i+1, b.next(index) {
+		refs = append(refs, b.refs[index])
+		metrics = append(metrics, b.buf[index])
+	}
+	return b.wal.compact(refs, metrics)
 }
 
 // dist returns the distance between two indexes.  Because this data structure
@@ -270,6 +403,7 @@ func (b *Buffer) prevby(index, count int) int {
 func (b *Buffer) resetBatch() {
 	b.batchFirst = 0
 	b.batchSize = 0
+	b.batchRefs = nil
 }
 
 func min(a, b int) int {
@@ -0,0 +1,537 @@
+package models
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// Fsync policies for the buffer write-ahead log.
+const (
+	// FsyncAlways syncs the log to disk after every change to the buffer.
+	FsyncAlways = "always"
+	// FsyncFlush syncs the log to disk after each write to the output.
+	FsyncFlush = "flush"
+	// FsyncNever leaves syncing the log to the operating system.
+	FsyncNever = "never"
+)
+
+// Log record kinds.
+const (
+	walRecordAdd    byte = 1
+	walRecordRemove byte = 2
+)
+
+// Field value kinds used when encoding metrics.
+const (
+	walFieldFloat byte = iota + 1
+	walFieldInt
+	walFieldUint
+	walFieldString
+	walFieldBool
+)
+
+// Each record is framed by a kind byte and payload length before the payload
+// and a checksum of the kind and payload after it.
+const walRecordOverhead = 1 + 4 + 4
+
+var errWALCorrupt = errors.New("corrupt record")
+
+// walRef identifies a metric in the write-ahead log.
+type walRef struct {
+	id   uint64
+	size int64 // size of the add record in bytes
+}
+
+// walEntry is a metric restored from the write-ahead log.
+type walEntry struct {
+	ref    walRef
+	metric telegraf.Metric
+}
+
+// wal is an append only log of the metrics added to and removed from a
+// Buffer.  Replaying the log yields the metrics that were in the buffer and
+// not yet written when the log was last appended to.
+type wal struct {
+	path     string
+	file     *os.File
+	maxBytes int64
+	fsync    string
+
+	nextID  uint64
+	size    int64 // bytes in the log file
+	live    int64 // bytes of add records that have not been removed
+	pending []byte
+}
+
+// ValidFsyncPolicy returns an error if the policy is not a known fsync policy.
+func ValidFsyncPolicy(policy string) error {
+	switch policy {
+	case FsyncAlways, FsyncFlush, FsyncNever:
+		return nil
+	default:
+		return fmt.Errorf("unknown fsync policy %q, must be one of %q, %q or %q",
+			policy, FsyncAlways, FsyncFlush, FsyncNever)
+	}
+}
+
+// openWAL reads the log at path and returns the metrics it still holds,
+// ordered from oldest to newest.  The log file is not opened for writing
+// until it is compacted.
+func openWAL(path string, maxBytes int64, fsync string, log telegraf.Logger) (*wal, []walEntry, error) {
+	if err := ValidFsyncPolicy(fsync); err != nil {
+		return nil, nil, err
+	}
+
+	w := &wal{
+		path:     path,
+		maxBytes: maxBytes,
+		fsync:    fsync,
+		nextID:   1,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	live := make(map[uint64]walEntry)
+	offset := 0
+	for offset < len(data) {
+		kind, payload, n, err := readWALRecord(data[offset:])
+		if err != nil {
+			log.Warnf("Discarding %d bytes at offset %d of buffer log %q: %v",
+				len(data)-offset, offset, path, err)
+			break
+		}
+
+		switch kind {
+		case walRecordAdd:
+			if len(payload) < 8 {
+				err = errWALCorrupt
+				break
+			}
+			id := binary.LittleEndian.Uint64(payload)
+			var m telegraf.Metric
+			m, err = decodeWALMetric(payload[8:])
+			if err != nil {
+				break
+			}
+			live[id] = walEntry{ref: walRef{id: id, size: int64(n)}, metric: m}
+			if id >= w.nextID {
+				w.nextID = id + 1
+			}
+		case walRecordRemove:
+			if len(payload)%8 != 0 {
+				err = errWALCorrupt
+				break
+			}
+			for i := 0; i < len(payload); i += 8 {
+				delete(live, binary.LittleEndian.Uint64(payload[i:]))
+			}
+		default:
+			err = errWALCorrupt
+		}
+		if err != nil {
+			log.Warnf("Discarding %d bytes at offset %d of buffer log %q: %v",
+				len(data)-offset, offset, path, err)
+			break
+		}
+		offset += n
+	}
+
+	entries := make([]walEntry, 0, len(live))
+	for _, e := range live {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ref.id < entries[j].ref.id
+	})
+	return w, entries, nil
+}
+
+// add appends an add record for the metric and returns its reference.
+func (w *wal) add(m telegraf.Metric) walRef {
+	ref := walRef{id: w.nextID}
+	w.nextID++
+
+	start := len(w.pending)
+	w.pending = appendWALAdd(w.pending, ref.id, m)
+	ref.size = int64(len(w.pending) - start)
+	w.live += ref.size
+	return ref
+}
+
+// remove appends a remove record for the referenced metrics.
+func (w *wal) remove(refs ...walRef) {
+	if len(refs) == 0 {
+		return
+	}
+
+	payload := make([]byte, 0, len(refs)*8)
+	for _, ref := range refs {
+		payload = appendUint64(payload, ref.id)
+		w.live -= ref.size
+	}
+	w.pending = appendWALRecord(w.pending, walRecordRemove, payload)
+}
+
+// overLimit returns true if the metrics in the log exceed the size limit.
+func (w *wal) overLimit() bool {
+	return w.maxBytes > 0 && w.live > w.maxBytes
+}
+
+// needsCompaction returns true once removed records take up more space in
+// the log file than the metrics it holds, or the file has grown to twice the
+// size limit.
+func (w *wal) needsCompaction() bool {
+	if w.maxBytes > 0 && w.size > 2*w.maxBytes {
+		return true
+	}
+	return w.size > 2*w.live && w.size-w.live > 1024*1024
+}
+
+// commit writes the pending records to the log file.  The file is synced
+// according to the fsync policy, flush should be true when called after a
+// write to the output.
+func (w *wal) commit(flush bool) error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	if w.file == nil {
+		return errors.New("buffer log is closed")
+	}
+
+	n, err := w.file.Write(w.pending)
+	w.size += int64(n)
+	w.pending = w.pending[:0]
+	if err != nil {
+		return err
+	}
+
+	if w.fsync == FsyncAlways || (flush && w.fsync == FsyncFlush) {
+		return w.file.Sync()
+	}
+	return nil
+}
+
+// compact replaces the log file with one containing only the given metrics.
+// Any pending records are discarded as they are superseded by the new file.
+func (w *wal) compact(refs []walRef, metrics []telegraf.Metric) error {
+	w.pending = w.pending[:0]
+
+	var buf []byte
+	for i, m := range metrics {
+		buf = appendWALAdd(buf, refs[i].id, m)
+	}
+
+	tmp := w.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return err
+	}
+
+	w.file, err = os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	w.size = int64(len(buf))
+	w.live = w.size
+	return nil
+}
+
+// close writes any pending records, syncs and closes the log file.
+func (w *wal) close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.commit(true)
+	if err == nil && w.fsync != FsyncNever {
+		err = w.file.Sync()
+	}
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+	w.file = nil
+	return err
+}
+
+func appendWALAdd(buf []byte, id uint64, m telegraf.Metric) []byte {
+	payload := appendUint64(nil, id)
+	payload = appendWALMetric(payload, m)
+	return appendWALRecord(buf, walRecordAdd, payload)
+}
+
+func appendWALRecord(buf []byte, kind byte, payload []byte) []byte {
+	start := len(buf)
+	buf = append(buf, kind)
+	buf = appendUint32(buf, uint32(len(payload)))
+	buf = append(buf, payload...)
+
+	crc := crc32.NewIEEE()
+	crc.Write(buf[start : start+1])
+	crc.Write(payload)
+	return appendUint32(buf, crc.Sum32())
+}
+
+// readWALRecord reads the record at the start of data and returns its kind,
+// payload and total size.
+func readWALRecord(data []byte) (byte, []byte, int, error) {
+	if len(data) < walRecordOverhead {
+		return 0, nil, 0, errors.New("truncated record")
+	}
+
+	kind := data[0]
+	length := int(binary.LittleEndian.Uint32(data[1:5]))
+	if len(data) < walRecordOverhead+length {
+		return 0, nil, 0, errors.New("truncated record")
+	}
+	payload := data[5 : 5+length]
+
+	crc := crc32.NewIEEE()
+	crc.Write(data[0:1])
+	crc.Write(payload)
+	if crc.Sum32() != binary.LittleEndian.Uint32(data[5+length:]) {
+		return 0, nil, 0, errors.New("checksum mismatch")
+	}
+	return kind, payload, walRecordOverhead + length, nil
+}
+
+func appendWALMetric(buf []byte, m telegraf.Metric) []byte {
+	buf = appendString(buf, m.Name())
+	buf = append(buf, byte(m.Type()))
+	buf = appendUint64(buf, uint64(m.Time().UnixNano()))
+
+	tags := m.TagList()
+	buf = appendUvarint(buf, uint64(len(tags)))
+	for _, tag := range tags {
+		buf = appendString(buf, tag.Key)
+		buf = appendString(buf, tag.Value)
+	}
+
+	fields := m.FieldList()
+	buf = appendUvarint(buf, uint64(len(fields)))
+	for _, field := range fields {
+		buf = appendString(buf, field.Key)
+		switch v := field.Value.(type) {
+		case float64:
+			buf = append(buf, walFieldFloat)
+			buf = appendUint64(buf, math.Float64bits(v))
+		case int64:
+			buf = append(buf, walFieldInt)
+			buf = appendUint64(buf, uint64(v))
+		case uint64:
+			buf = append(buf, walFieldUint)
+			buf = appendUint64(buf, v)
+		case string:
+			buf = append(buf, walFieldString)
+			buf = appendString(buf, v)
+		case bool:
+			buf = append(buf, walFieldBool)
+			if v {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+		}
+	}
+
+	// Distributions follow the fields so that metrics logged without them
+	// can still be decoded.
+	distributions := m.DistributionList()
+	if len(distributions) == 0 {
+		return buf
+	}
+	buf = appendUvarint(buf, uint64(len(distributions)))
+	for _, d := range distributions {
+		buf = appendString(buf, d.Key)
+		buf = appendUint64(buf, d.Count)
+		buf = appendUint64(buf, math.Float64bits(d.Sum))
+		buf = appendUvarint(buf, uint64(len(d.Buckets)))
+		for _, b := range d.Buckets {
+			buf = appendUint64(buf, math.Float64bits(b.UpperBound))
+			buf = appendUint64(buf, b.Count)
+		}
+		buf = appendUvarint(buf, uint64(len(d.Quantiles)))
+		for _, q := range d.Quantiles {
+			buf = appendUint64(buf, math.Float64bits(q.Quantile))
+			buf = appendUint64(buf, math.Float64bits(q.Value))
+		}
+	}
+	return buf
+}
+
+func decodeWALMetric(data []byte) (telegraf.Metric, error) {
+	d := &walDecoder{data: data}
+
+	name := d.string()
+	tp := telegraf.ValueType(d.byte())
+	tm := time.Unix(0, int64(d.uint64()))
+
+	n := d.uvarint()
+	tags := make(map[string]string, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		k := d.string()
+		tags[k] = d.string()
+	}
+
+	n = d.uvarint()
+	fields := make(map[string]interface{}, n)
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		k := d.string()
+		switch d.byte() {
+		case walFieldFloat:
+			fields[k] = math.Float64frombits(d.uint64())
+		case walFieldInt:
+			fields[k] = int64(d.uint64())
+		case walFieldUint:
+			fields[k] = d.uint64()
+		case walFieldString:
+			fields[k] = d.string()
+		case walFieldBool:
+			fields[k] = d.byte() == 1
+		default:
+			d.err = errWALCorrupt
+		}
+	}
+
+	var distributions []*telegraf.Distribution
+	if len(d.data) > 0 {
+		n = d.uvarint()
+		for i := uint64(0); i < n && d.err == nil; i++ {
+			distribution := &telegraf.Distribution{
+				Key:   d.string(),
+				Count: d.uint64(),
+				Sum:   math.Float64frombits(d.uint64()),
+			}
+			nb := d.uvarint()
+			for j := uint64(0); j < nb && d.err == nil; j++ {
+				distribution.Buckets = append(distribution.Buckets, telegraf.Bucket{
+					UpperBound: math.Float64frombits(d.uint64()),
+					Count:      d.uint64(),
+				})
+			}
+			nq := d.uvarint()
+			for j := uint64(0); j < nq && d.err == nil; j++ {
+				distribution.Quantiles = append(distribution.Quantiles, telegraf.Quantile{
+					Quantile: math.Float64frombits(d.uint64()),
+					Value:    math.Float64frombits(d.uint64()),
+				})
+			}
+			distributions = append(distributions, distribution)
+		}
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	m, err := metric.New(name, tags, fields, tm, tp)
+	if err != nil {
+		return nil, err
+	}
+	for _, distribution := range distributions {
+		m.AddDistribution(distribution)
+	}
+	return m, nil
+}
+
+type walDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *walDecoder) byte() byte {
+	if d.err != nil || len(d.data) < 1 {
+		d.err = errWALCorrupt
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *walDecoder) uint64() uint64 {
+	if d.err != nil || len(d.data) < 8 {
+		d.err = errWALCorrupt
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return v
+}
+
+func (d *walDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errWALCorrupt
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *walDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil || uint64(len(d.data)) < n {
+		d.err = errWALCorrupt
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = appendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
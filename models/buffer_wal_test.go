@@ -0,0 +1,246 @@
+package models
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newWALBuffer(t *testing.T, path string, capacity int, maxBytes int64) (*Buffer, int) {
+	b := setup(NewBuffer("test", "", capacity))
+	n, err := b.OpenWAL(path, maxBytes, FsyncAlways, testutil.Logger{})
+	require.NoError(t, err)
+	return b, n
+}
+
+func walPath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "buffer-wal")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "test.wal")
+}
+
+func TestBufferWAL_RestoreUnwritten(t *testing.T) {
+	path := walPath(t)
+
+	b, n := newWALBuffer(t, path, 5, 0)
+	require.Equal(t, 0, n)
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3))
+	require.NoError(t, b.Close())
+
+	b, n = newWALBuffer(t, path, 5, 0)
+	require.Equal(t, 3, n)
+	require.Equal(t, 3, b.Len())
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(1),
+			MetricTime(2),
+			MetricTime(3),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_AcceptedNotRestored(t *testing.T) {
+	path := walPath(t)
+
+	b, _ := newWALBuffer(t, path, 5, 0)
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3))
+	batch := b.Batch(2)
+	b.Accept(batch)
+	require.NoError(t, b.Close())
+
+	b, n := newWALBuffer(t, path, 5, 0)
+	require.Equal(t, 1, n)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(3),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_RejectedRestored(t *testing.T) {
+	path := walPath(t)
+
+	b, _ := newWALBuffer(t, path, 5, 0)
+	b.Add(MetricTime(1), MetricTime(2))
+	batch := b.Batch(2)
+	b.Add(MetricTime(3))
+	b.Reject(batch)
+	require.NoError(t, b.Close())
+
+	b, n := newWALBuffer(t, path, 5, 0)
+	require.Equal(t, 3, n)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(1),
+			MetricTime(2),
+			MetricTime(3),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_OverflowNotRestored(t *testing.T) {
+	path := walPath(t)
+
+	b, _ := newWALBuffer(t, path, 3, 0)
+	b.Add(MetricTime(1), MetricTime(2), MetricTime(3), MetricTime(4))
+	require.NoError(t, b.Close())
+
+	b, n := newWALBuffer(t, path, 3, 0)
+	require.Equal(t, 3, n)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(2),
+			MetricTime(3),
+			MetricTime(4),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_MaxBytesDropsOldest(t *testing.T) {
+	path := walPath(t)
+
+	size := int64(len(appendWALAdd(nil, 1, MetricTime(1))))
+	b, _ := newWALBuffer(t, path, 10, 2*size)
+	dropped := b.Add(MetricTime(1), MetricTime(2), MetricTime(3))
+	require.Equal(t, 1, dropped)
+	require.Equal(t, int64(1), b.MetricsDropped.Get())
+	require.NoError(t, b.Close())
+
+	b, n := newWALBuffer(t, path, 10, 2*size)
+	require.Equal(t, 2, n)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(2),
+			MetricTime(3),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_TruncatedRecordDiscarded(t *testing.T) {
+	path := walPath(t)
+
+	b, _ := newWALBuffer(t, path, 5, 0)
+	b.Add(MetricTime(1), MetricTime(2))
+	require.NoError(t, b.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-3))
+
+	b, n := newWALBuffer(t, path, 5, 0)
+	require.Equal(t, 1, n)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(1),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_Compaction(t *testing.T) {
+	path := walPath(t)
+
+	size := int64(len(appendWALAdd(nil, 1, MetricTime(1))))
+	b, _ := newWALBuffer(t, path, 10, 2*size)
+	for i := int64(0); i < 100; i++ {
+		b.Add(MetricTime(i))
+		b.Accept(b.Batch(1))
+	}
+	b.Add(MetricTime(100))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.True(t, info.Size() <= 4*size+2*walRecordOverhead+16)
+	require.NoError(t, b.Close())
+
+	b, n := newWALBuffer(t, path, 10, 2*size)
+	require.Equal(t, 1, n)
+	testutil.RequireMetricsEqual(t,
+		[]telegraf.Metric{
+			MetricTime(100),
+		}, b.Batch(5))
+}
+
+func TestBufferWAL_MetricEncoding(t *testing.T) {
+	m, err := metric.New(
+		"cpu",
+		map[string]string{
+			"host": "localhost",
+			"cpu":  "cpu0",
+		},
+		map[string]interface{}{
+			"float":  42.5,
+			"int":    int64(-42),
+			"uint":   uint64(42),
+			"string": "forty two",
+			"bool":   true,
+		},
+		time.Unix(0, 1600000000123456789),
+		telegraf.Counter,
+	)
+	require.NoError(t, err)
+
+	actual, err := decodeWALMetric(appendWALMetric(nil, m))
+	require.NoError(t, err)
+	testutil.RequireMetricEqual(t, m, actual)
+	require.Equal(t, telegraf.Counter, actual.Type())
+}
+
+func TestBufferWAL_DistributionEncoding(t *testing.T) {
+	m, err := metric.New(
+		"prometheus",
+		map[string]string{},
+		map[string]interface{}{
+			"latency_count": 3.0,
+			"latency_sum":   1.5,
+		},
+		time.Unix(0, 1600000000123456789),
+		telegraf.Histogram,
+	)
+	require.NoError(t, err)
+	m.AddDistribution(&telegraf.Distribution{
+		Key:   "latency",
+		Count: 3,
+		Sum:   1.5,
+		Buckets: []telegraf.Bucket{
+			{UpperBound: 0.5, Count: 2},
+			{UpperBound: math.Inf(1), Count: 3},
+		},
+	})
+
+	actual, err := decodeWALMetric(appendWALMetric(nil, m))
+	require.NoError(t, err)
+	testutil.RequireMetricEqual(t, m, actual)
+}
+
+func TestRunningOutput_ValidateLeavesBufferLog(t *testing.T) {
+	path := walPath(t)
+	b, _ := newWALBuffer(t, path, 5, 0)
+	b.Add(MetricTime(1))
+	require.NoError(t, b.Close())
+	before, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	ro := NewRunningOutput("test", &mockOutput{}, &OutputConfig{
+		Name:            "test",
+		BufferDirectory: filepath.Dir(path),
+	}, 1000, 10000)
+	require.NoError(t, ro.Validate())
+	after, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+
+	missing := filepath.Join(filepath.Dir(path), "missing")
+	ro = NewRunningOutput("test", &mockOutput{}, &OutputConfig{
+		Name:            "test",
+		BufferDirectory: missing,
+	}, 1000, 10000)
+	require.NoError(t, ro.Validate())
+	_, err = os.Stat(missing)
+	require.True(t, os.IsNotExist(err))
+
+	ro.Config.BufferFsync = "sometimes"
+	require.Error(t, ro.Validate())
+}
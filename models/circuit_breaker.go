@@ -0,0 +1,88 @@
+package models
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Defaults for the retry backoff and circuit breaker of an output.
+const (
+	DefaultRetryMaxInterval      = 5 * time.Minute
+	DefaultCircuitBreakerTimeout = time.Minute
+)
+
+// circuitBreaker tracks the consecutive write failures of an output and when
+// the next write may be attempted.
+//
+// After a failure writes are delayed by an exponential backoff, starting at
+// the initial interval and doubling up to the max interval, with up to half
+// of the delay randomized.  After threshold consecutive failures the circuit
+// opens and writes are paused for the timeout, after which a single write is
+// attempted; the circuit closes if it succeeds and opens again if it fails.
+type circuitBreaker struct {
+	initial   time.Duration
+	max       time.Duration
+	threshold int
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	retryAt  time.Time
+}
+
+// allow returns true if a write may be attempted.
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !now.Before(b.retryAt)
+}
+
+// failure records a failed write and returns true if it opened the circuit.
+func (b *circuitBreaker) failure(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.threshold > 0 && b.failures >= b.threshold {
+		opened := !b.open
+		b.open = true
+		b.retryAt = now.Add(b.timeout)
+		return opened
+	}
+
+	if b.initial > 0 {
+		delay := b.initial
+		for i := 1; i < b.failures && delay < b.max; i++ {
+			delay *= 2
+		}
+		if delay > b.max {
+			delay = b.max
+		}
+		delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		b.retryAt = now.Add(delay)
+	}
+	return false
+}
+
+// success records a successful write and returns true if it closed the
+// circuit.
+func (b *circuitBreaker) success() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	closed := b.open
+	b.failures = 0
+	b.open = false
+	b.retryAt = time.Time{}
+	return closed
+}
+
+// state returns the number of consecutive failures and if the circuit is
+// open.
+func (b *circuitBreaker) state() (int, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures, b.open
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerBackoff(t *testing.T) {
+	b := &circuitBreaker{initial: time.Second, max: 4 * time.Second, timeout: time.Minute}
+	now := time.Unix(0, 0)
+	require.True(t, b.allow(now))
+
+	// The delay doubles after each failure up to the maximum, with up to
+	// half of it randomized.
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		require.False(t, b.failure(now))
+		require.False(t, b.allow(now.Add(delay/2-time.Nanosecond)))
+		require.True(t, b.allow(now.Add(delay)))
+	}
+	failures, open := b.state()
+	require.Equal(t, 4, failures)
+	require.False(t, open)
+
+	require.False(t, b.success())
+	require.True(t, b.allow(now))
+	failures, _ = b.state()
+	require.Equal(t, 0, failures)
+}
+
+func TestCircuitBreakerNoBackoff(t *testing.T) {
+	b := &circuitBreaker{max: time.Minute, timeout: time.Minute}
+	now := time.Unix(0, 0)
+	require.False(t, b.failure(now))
+	require.True(t, b.allow(now))
+}
+
+func TestCircuitBreakerOpen(t *testing.T) {
+	b := &circuitBreaker{threshold: 3, max: time.Minute, timeout: time.Minute}
+	now := time.Unix(0, 0)
+
+	require.False(t, b.failure(now))
+	require.False(t, b.failure(now))
+	require.True(t, b.failure(now))
+	_, open := b.state()
+	require.True(t, open)
+	require.False(t, b.allow(now.Add(59*time.Second)))
+
+	// A failed trial write keeps the circuit open for another timeout.
+	now = now.Add(time.Minute)
+	require.True(t, b.allow(now))
+	require.False(t, b.failure(now))
+	require.False(t, b.allow(now.Add(59*time.Second)))
+
+	now = now.Add(time.Minute)
+	require.True(t, b.allow(now))
+	require.True(t, b.success())
+	_, open = b.state()
+	require.False(t, open)
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"log"
+	"sync"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/filter"
@@ -34,6 +36,9 @@ type Filter struct {
 	TagInclude []string
 	tagInclude filter.Filter
 
+	MetricPass string
+	metricPass expression
+
 	isActive bool
 }
 
@@ -46,7 +51,8 @@ func (f *Filter) Compile() error {
 		len(f.TagInclude) == 0 &&
 		len(f.TagExclude) == 0 &&
 		len(f.TagPass) == 0 &&
-		len(f.TagDrop) == 0 {
+		len(f.TagDrop) == 0 &&
+		f.MetricPass == "" {
 		return nil
 	}
 
@@ -91,11 +97,19 @@ func (f *Filter) Compile() error {
 			return fmt.Errorf("Error compiling 'tagpass', %s", err)
 		}
 	}
+
+	if f.MetricPass != "" {
+		f.metricPass, err = compileExpression(f.MetricPass)
+		if err != nil {
+			return fmt.Errorf("Error compiling 'metricpass', %s", err)
+		}
+	}
 	return nil
 }
 
 // Select returns true if the metric matches according to the
-// namepass/namedrop and tagpass/tagdrop filters.  The metric is not modified.
+// namepass/namedrop, tagpass/tagdrop and metricpass filters.  The metric is
+// not modified.
 func (f *Filter) Select(metric telegraf.Metric) bool {
 	if !f.isActive {
 		return true
@@ -109,6 +123,10 @@ func (f *Filter) Select(metric telegraf.Metric) bool {
 		return false
 	}
 
+	if !f.shouldMetricPass(metric) {
+		return false
+	}
+
 	return true
 }
 
@@ -219,6 +237,30 @@ func (f *Filter) shouldTagsPass(tags []*telegraf.Tag) bool {
 	return true
 }
 
+// metricPassErrors holds the metricpass expressions whose evaluation error
+// has been logged.
+var metricPassErrors sync.Map
+
+// shouldMetricPass returns true if the metricpass expression is true for the
+// metric.  A metric for which the expression cannot be evaluated, or is not a
+// bool, is dropped.  The first evaluation error of each expression is logged,
+// so that a broken expression can be told apart from one dropping all metrics.
+func (f *Filter) shouldMetricPass(metric telegraf.Metric) bool {
+	if f.metricPass == nil {
+		return true
+	}
+	v, err := f.metricPass(metric)
+	if err != nil {
+		if _, logged := metricPassErrors.LoadOrStore(f.MetricPass, true); !logged {
+			log.Printf("W! [models] Evaluating metricpass %q failed, dropping the metric; further errors are not logged: %v",
+				f.MetricPass, err)
+		}
+		return false
+	}
+	pass, _ := v.(bool)
+	return pass
+}
+
 // filterFields removes fields according to fieldpass/fielddrop.
 func (f *Filter) filterFields(metric telegraf.Metric) {
 	filterKeys := []string{}
@@ -0,0 +1,393 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+)
+
+// expression is a compiled metricpass expression, returning the value of the
+// expression for a metric.  Values are nil, bool, int64, float64 or string;
+// nil is the value of a missing tag or field.
+type expression func(metric telegraf.Metric) (interface{}, error)
+
+var errDivideByZero = errors.New("integer divide by zero")
+
+// compileExpression compiles a boolean expression over the name, tags, fields
+// and time of a metric, using the syntax of Go expressions:
+//
+//	fields.usage_idle < 10 && tags.cpu != "cpu-total"
+//
+// The identifiers are:
+//
+//	name                       the metric name
+//	time                       the metric time in nanoseconds since the epoch
+//	tags.key, tags["key"]      the value of a tag
+//	fields.key, fields["key"]  the value of a field
+//	true, false, nil
+func compileExpression(s string) (expression, error) {
+	node, err := parser.ParseExpr(s)
+	if err != nil {
+		return nil, err
+	}
+	return compileNode(node)
+}
+
+func compileNode(node ast.Expr) (expression, error) {
+	switch n := node.(type) {
+	case *ast.ParenExpr:
+		return compileNode(n.X)
+	case *ast.BasicLit:
+		v, err := literal(n)
+		if err != nil {
+			return nil, err
+		}
+		return func(telegraf.Metric) (interface{}, error) { return v, nil }, nil
+	case *ast.Ident:
+		return compileIdent(n)
+	case *ast.SelectorExpr:
+		return compileLookup(n.X, n.Sel.Name)
+	case *ast.IndexExpr:
+		lit, ok := n.Index.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, fmt.Errorf("column %d: index must be a string", n.Index.Pos())
+		}
+		key, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		return compileLookup(n.X, key)
+	case *ast.UnaryExpr:
+		return compileUnary(n)
+	case *ast.BinaryExpr:
+		return compileBinary(n)
+	}
+	return nil, fmt.Errorf("column %d: unsupported expression", node.Pos())
+}
+
+func literal(lit *ast.BasicLit) (interface{}, error) {
+	switch lit.Kind {
+	case token.INT:
+		if v, err := strconv.ParseInt(lit.Value, 0, 64); err == nil {
+			return v, nil
+		}
+		return strconv.ParseFloat(lit.Value, 64)
+	case token.FLOAT:
+		return strconv.ParseFloat(lit.Value, 64)
+	case token.STRING:
+		return strconv.Unquote(lit.Value)
+	}
+	return nil, fmt.Errorf("column %d: unsupported literal %s", lit.Pos(), lit.Value)
+}
+
+func compileIdent(ident *ast.Ident) (expression, error) {
+	switch ident.Name {
+	case "name":
+		return func(m telegraf.Metric) (interface{}, error) { return m.Name(), nil }, nil
+	case "time":
+		return func(m telegraf.Metric) (interface{}, error) { return m.Time().UnixNano(), nil }, nil
+	case "true":
+		return func(telegraf.Metric) (interface{}, error) { return true, nil }, nil
+	case "false":
+		return func(telegraf.Metric) (interface{}, error) { return false, nil }, nil
+	case "nil":
+		return func(telegraf.Metric) (interface{}, error) { return nil, nil }, nil
+	}
+	return nil, fmt.Errorf("column %d: unknown identifier %q", ident.Pos(), ident.Name)
+}
+
+func compileLookup(x ast.Expr, key string) (expression, error) {
+	ident, ok := x.(*ast.Ident)
+	if ok {
+		switch ident.Name {
+		case "tags":
+			return func(m telegraf.Metric) (interface{}, error) {
+				if v, ok := m.GetTag(key); ok {
+					return v, nil
+				}
+				return nil, nil
+			}, nil
+		case "fields":
+			return func(m telegraf.Metric) (interface{}, error) {
+				if v, ok := m.GetField(key); ok {
+					return fieldValue(v), nil
+				}
+				return nil, nil
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("column %d: only tags and fields can be indexed", x.Pos())
+}
+
+// fieldValue converts a field value to an expression value.
+func fieldValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case uint64:
+		if v > math.MaxInt64 {
+			return float64(v)
+		}
+		return int64(v)
+	}
+	return v
+}
+
+func compileUnary(n *ast.UnaryExpr) (expression, error) {
+	x, err := compileNode(n.X)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case token.NOT:
+		return func(m telegraf.Metric) (interface{}, error) {
+			v, err := x(m)
+			if err != nil {
+				return nil, err
+			}
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("column %d: operand of ! is not a bool", n.Pos())
+			}
+			return !b, nil
+		}, nil
+	case token.SUB:
+		return func(m telegraf.Metric) (interface{}, error) {
+			v, err := x(m)
+			if err != nil {
+				return nil, err
+			}
+			switch v := v.(type) {
+			case nil:
+				return nil, nil
+			case int64:
+				return -v, nil
+			case float64:
+				return -v, nil
+			}
+			return nil, fmt.Errorf("column %d: operand of - is not a number", n.Pos())
+		}, nil
+	}
+	return nil, fmt.Errorf("column %d: unsupported operator %s", n.OpPos, n.Op)
+}
+
+func compileBinary(n *ast.BinaryExpr) (expression, error) {
+	x, err := compileNode(n.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := compileNode(n.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case token.LAND, token.LOR:
+		return compileLogical(n, x, y), nil
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ,
+		token.ADD, token.SUB, token.MUL, token.QUO, token.REM:
+	default:
+		return nil, fmt.Errorf("column %d: unsupported operator %s", n.OpPos, n.Op)
+	}
+
+	return func(m telegraf.Metric) (interface{}, error) {
+		a, err := x(m)
+		if err != nil {
+			return nil, err
+		}
+		b, err := y(m)
+		if err != nil {
+			return nil, err
+		}
+
+		switch n.Op {
+		case token.EQL:
+			return equal(a, b), nil
+		case token.NEQ:
+			return !equal(a, b), nil
+		case token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return compare(n, a, b)
+		}
+		return arithmetic(n, a, b)
+	}, nil
+}
+
+// compileLogical compiles && and ||, evaluating the right operand only when
+// needed.
+func compileLogical(n *ast.BinaryExpr, x, y expression) expression {
+	operand := func(e expression, m telegraf.Metric) (bool, error) {
+		v, err := e(m)
+		if err != nil {
+			return false, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return false, fmt.Errorf("column %d: operand of %s is not a bool", n.OpPos, n.Op)
+		}
+		return b, nil
+	}
+
+	return func(m telegraf.Metric) (interface{}, error) {
+		a, err := operand(x, m)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op == token.LAND && !a || n.Op == token.LOR && a {
+			return a, nil
+		}
+		return operand(y, m)
+	}
+}
+
+// equal compares values of any type; numbers are equal if they have the same
+// value regardless of their type.
+func equal(a, b interface{}) bool {
+	if fa, fb, ok := numbers(a, b); ok {
+		if ia, ok := a.(int64); ok {
+			if ib, ok := b.(int64); ok {
+				return ia == ib
+			}
+		}
+		return fa == fb
+	}
+	return a == b
+}
+
+// compare orders numbers or strings.  Comparing a missing value is false.
+func compare(n *ast.BinaryExpr, a, b interface{}) (interface{}, error) {
+	if a == nil || b == nil {
+		return false, nil
+	}
+
+	var cmp int
+	if ia, ok := a.(int64); ok {
+		if ib, ok := b.(int64); ok {
+			cmp = compareInts(ia, ib)
+			return ordered(n.Op, cmp), nil
+		}
+	}
+	if fa, fb, ok := numbers(a, b); ok {
+		switch {
+		case fa < fb:
+			cmp = -1
+		case fa > fb:
+			cmp = 1
+		case fa != fb:
+			return false, nil // NaN
+		}
+		return ordered(n.Op, cmp), nil
+	}
+	if sa, ok := a.(string); ok {
+		if sb, ok := b.(string); ok {
+			switch {
+			case sa < sb:
+				cmp = -1
+			case sa > sb:
+				cmp = 1
+			}
+			return ordered(n.Op, cmp), nil
+		}
+	}
+	return nil, fmt.Errorf("column %d: cannot compare %T and %T", n.OpPos, a, b)
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func ordered(op token.Token, cmp int) bool {
+	switch op {
+	case token.LSS:
+		return cmp < 0
+	case token.LEQ:
+		return cmp <= 0
+	case token.GTR:
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+// arithmetic applies an arithmetic operator to numbers, or + to strings.  The
+// result of an operation on a missing value is missing.
+func arithmetic(n *ast.BinaryExpr, a, b interface{}) (interface{}, error) {
+	if a == nil || b == nil {
+		return nil, nil
+	}
+
+	if ia, ok := a.(int64); ok {
+		if ib, ok := b.(int64); ok {
+			switch n.Op {
+			case token.ADD:
+				return ia + ib, nil
+			case token.SUB:
+				return ia - ib, nil
+			case token.MUL:
+				return ia * ib, nil
+			case token.QUO:
+				if ib == 0 {
+					return nil, errDivideByZero
+				}
+				return ia / ib, nil
+			case token.REM:
+				if ib == 0 {
+					return nil, errDivideByZero
+				}
+				return ia % ib, nil
+			}
+		}
+	}
+	if fa, fb, ok := numbers(a, b); ok {
+		switch n.Op {
+		case token.ADD:
+			return fa + fb, nil
+		case token.SUB:
+			return fa - fb, nil
+		case token.MUL:
+			return fa * fb, nil
+		case token.QUO:
+			return fa / fb, nil
+		case token.REM:
+			return math.Mod(fa, fb), nil
+		}
+	}
+	if sa, ok := a.(string); ok && n.Op == token.ADD {
+		if sb, ok := b.(string); ok {
+			return sa + sb, nil
+		}
+	}
+	return nil, fmt.Errorf("column %d: invalid operation %T %s %T", n.OpPos, a, n.Op, b)
+}
+
+// numbers returns both values as floats if they are both numbers.
+func numbers(a, b interface{}) (float64, float64, bool) {
+	fa, ok := number(a)
+	if !ok {
+		return 0, 0, false
+	}
+	fb, ok := number(b)
+	if !ok {
+		return 0, 0, false
+	}
+	return fa, fb, true
+}
+
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -523,3 +527,83 @@ func BenchmarkFilter(b *testing.B) {
 		})
 	}
 }
+
+func TestFilter_MetricPass(t *testing.T) {
+	m, err := metric.New("cpu",
+		map[string]string{"cpu": "cpu0", "host": "a-b"},
+		map[string]interface{}{
+			"usage_idle": 5.5,
+			"count":      int64(3),
+			"total":      uint64(10),
+			"ok":         true,
+			"state":      "up",
+		},
+		time.Unix(0, 100))
+	require.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`fields.usage_idle < 10 && tags.cpu != "cpu-total"`, true},
+		{`fields.usage_idle > 10 || tags.cpu == "cpu-total"`, false},
+		{`name == "cpu"`, true},
+		{`time == 100`, true},
+		{`tags["host"] == "a-b"`, true},
+		{`fields.count * 2 == 6 && fields.total / fields.count == 3`, true},
+		{`fields.count == 3.0`, true},
+		{`fields.total % 4 == 2`, true},
+		{`-fields.count < 0`, true},
+		{`fields.ok && !(fields.state == "down")`, true},
+		{`fields.state >= "up"`, true},
+		{`tags.missing == nil && fields.missing != 1`, true},
+		{`fields.missing > 0`, false},
+		{`fields.missing <= 0`, false},
+		{`fields.state > 1`, false},
+		{`fields.count / 0 == 1`, false},
+		{`fields.count`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			f := Filter{MetricPass: tt.expression}
+			require.NoError(t, f.Compile())
+			require.True(t, f.IsActive())
+			require.Equal(t, tt.expected, f.Select(m))
+		})
+	}
+}
+
+func TestFilter_MetricPassErrorLoggedOnce(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	m, err := metric.New("cpu",
+		map[string]string{},
+		map[string]interface{}{"state": "up"},
+		time.Unix(0, 0))
+	require.NoError(t, err)
+
+	f := Filter{MetricPass: `fields.state > 2`}
+	require.NoError(t, f.Compile())
+	require.False(t, f.Select(m))
+	require.False(t, f.Select(m))
+	require.Equal(t, 1, strings.Count(buf.String(), "W! "))
+	require.Contains(t, buf.String(), "fields.state > 2")
+}
+
+func TestFilter_MetricPassInvalid(t *testing.T) {
+	for _, expression := range []string{
+		`fields.usage_idle <`,
+		`value > 1`,
+		`host.name == "a"`,
+		`fields[1] == 1`,
+		`len(name) > 1`,
+		`fields.a & 1`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			f := Filter{MetricPass: expression}
+			require.Error(t, f.Compile())
+		})
+	}
+}
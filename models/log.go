@@ -1,8 +1,11 @@
 package models
 
 import (
+	"fmt"
 	"log"
 	"reflect"
+	"sync"
+	"time"
 
 	"github.com/influxdata/telegraf"
 )
@@ -11,6 +14,10 @@ import (
 type Logger struct {
 	OnErrs []func()
 	Name   string // Name is the plugin name, will be printed in the `[]`.
+
+	mu          sync.Mutex
+	lastErr     string
+	lastErrTime time.Time
 }
 
 // NewLogger creates a new logger instance
@@ -30,6 +37,7 @@ func (l *Logger) Errorf(format string, args ...interface{}) {
 	for _, f := range l.OnErrs {
 		f()
 	}
+	l.setLastError(fmt.Sprintf(format, args...))
 	log.Printf("E! ["+l.Name+"] "+format, args...)
 }
 
@@ -38,9 +46,24 @@ func (l *Logger) Error(args ...interface{}) {
 	for _, f := range l.OnErrs {
 		f()
 	}
+	l.setLastError(fmt.Sprint(args...))
 	log.Print(append([]interface{}{"E! [" + l.Name + "] "}, args...)...)
 }
 
+func (l *Logger) setLastError(msg string) {
+	l.mu.Lock()
+	l.lastErr = msg
+	l.lastErrTime = time.Now()
+	l.mu.Unlock()
+}
+
+// LastError returns the last error message logged and when it was logged.
+func (l *Logger) LastError() (string, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastErr, l.lastErrTime
+}
+
 // Debugf logs a debug message, patterned after log.Printf.
 func (l *Logger) Debugf(format string, args ...interface{}) {
 	log.Printf("D! ["+l.Name+"] "+format, args...)
@@ -22,3 +22,17 @@ func TestErrorCounting(t *testing.T) {
 
 	require.Equal(t, int64(2), reg.Get())
 }
+
+func TestLastError(t *testing.T) {
+	iLog := Logger{Name: "inputs.test"}
+	msg, at := iLog.LastError()
+	require.Equal(t, "", msg)
+	require.True(t, at.IsZero())
+
+	iLog.Errorf("first %d", 1)
+	iLog.Error("second")
+
+	msg, at = iLog.LastError()
+	require.Equal(t, "second", msg)
+	require.False(t, at.IsZero())
+}
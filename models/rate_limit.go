@@ -0,0 +1,91 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+)
+
+// rateLimiter is a token bucket refilled at rate tokens per second, holding
+// up to one second of tokens.
+type rateLimiter struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate, tokens: rate}
+}
+
+// reserve takes n tokens and returns how long to wait before using them.  The
+// bucket may go into debt of up to its size: a request larger than the bucket
+// takes all of it instead of never being allowed.
+func (l *rateLimiter) reserve(now time.Time, n float64) time.Duration {
+	if n > l.rate {
+		n = l.rate
+	}
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.rate {
+			l.tokens = l.rate
+		}
+	}
+	l.last = now
+
+	l.tokens -= n
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// estimateSize returns about the size of a metric in line protocol, without
+// serializing it: escaping is not counted, and the timestamp is counted with
+// nanosecond precision.
+func estimateSize(m telegraf.Metric) int {
+	var buf [32]byte
+	size := len(m.Name())
+	for _, tag := range m.TagList() {
+		size += len(tag.Key) + len(tag.Value) + 2
+	}
+	for _, field := range m.FieldList() {
+		size += len(field.Key) + 2
+		switch v := field.Value.(type) {
+		case string:
+			size += len(v) + 2
+		case int64:
+			size += len(strconv.AppendInt(buf[:0], v, 10)) + 1
+		case uint64:
+			size += len(strconv.AppendUint(buf[:0], v, 10)) + 1
+		case float64:
+			size += len(strconv.AppendFloat(buf[:0], v, 'f', -1, 64))
+		case bool:
+			size += len(strconv.AppendBool(buf[:0], v))
+		}
+	}
+	// The timestamp, separated by a space and followed by a newline.
+	return size + 21
+}
+
+// tooLargeRe matches the errors of outputs rejecting a request as too large,
+// such as an HTTP 413 status.
+var tooLargeRe = regexp.MustCompile(`(?i)\b413\b|too large`)
+
+// isBatchTooLarge returns true if a write error suggests a smaller batch may
+// succeed: the request was too large, or timed out.
+func isBatchTooLarge(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return tooLargeRe.MatchString(err.Error())
+}
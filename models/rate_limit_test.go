@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/plugins/serializers/influx"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newRateLimiter(100)
+
+	// The bucket starts full.
+	require.Equal(t, time.Duration(0), l.reserve(now, 100))
+
+	// An empty bucket delays the request until it refills.
+	require.Equal(t, 500*time.Millisecond, l.reserve(now, 50))
+
+	// The debt is paid back before new tokens are available.
+	now = now.Add(time.Second)
+	require.Equal(t, time.Duration(0), l.reserve(now, 50))
+
+	// The bucket holds at most one second of tokens.
+	now = now.Add(time.Minute)
+	require.Equal(t, time.Duration(0), l.reserve(now, 100))
+	require.Equal(t, 500*time.Millisecond, l.reserve(now, 50))
+
+	// A request larger than the bucket takes all of it, so the debt is at
+	// most one second of tokens.
+	now = now.Add(time.Minute)
+	require.Equal(t, time.Duration(0), l.reserve(now, 1000))
+	require.Equal(t, time.Second, l.reserve(now, 1000))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestIsBatchTooLarge(t *testing.T) {
+	require.True(t, isBatchTooLarge(errors.New("when writing to [http://localhost] received status code: 413")))
+	require.True(t, isBatchTooLarge(errors.New("413 Request Entity Too Large")))
+	require.True(t, isBatchTooLarge(fmt.Errorf("writing: %w", context.DeadlineExceeded)))
+	require.True(t, isBatchTooLarge(fmt.Errorf("writing: %w", timeoutError{})))
+	require.False(t, isBatchTooLarge(errors.New("received status code: 500")))
+	require.False(t, isBatchTooLarge(errors.New("connection refused")))
+}
+
+func TestEstimateSize(t *testing.T) {
+	m := testutil.MustMetric("cpu",
+		map[string]string{"host": "localhost", "cpu": "cpu0"},
+		map[string]interface{}{
+			"usage_idle": 99.5,
+			"count":      int64(-42),
+			"total":      uint64(42),
+			"state":      "running",
+			"up":         true,
+		},
+		time.Unix(1600000000, 0),
+	)
+
+	// The size is exact for metrics without characters to escape.
+	s := influx.NewSerializer()
+	s.SetFieldTypeSupport(influx.UintSupport)
+	octets, err := s.Serialize(m)
+	require.NoError(t, err)
+	require.Equal(t, len(octets), estimateSize(m))
+}
@@ -13,6 +13,7 @@ type RunningAggregator struct {
 	sync.Mutex
 	Aggregator  telegraf.Aggregator
 	Config      *AggregatorConfig
+	ID          string // identifies the plugin by its configuration
 	periodStart time.Time
 	periodEnd   time.Time
 	log         telegraf.Logger
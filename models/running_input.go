@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -13,10 +14,15 @@ var (
 )
 
 type RunningInput struct {
+	// Must be 64-bit aligned
+	lastGatherStart    int64
+	lastGatherDuration int64
+
 	Input  telegraf.Input
 	Config *InputConfig
+	ID     string // identifies the plugin by its configuration
 
-	log         telegraf.Logger
+	log         *Logger
 	defaultTags map[string]string
 
 	MetricsGathered selfstat.Stat
@@ -117,9 +123,27 @@ func (r *RunningInput) Gather(acc telegraf.Accumulator) error {
 	err := r.Input.Gather(acc)
 	elapsed := time.Since(start)
 	r.GatherTime.Incr(elapsed.Nanoseconds())
+	atomic.StoreInt64(&r.lastGatherStart, start.UnixNano())
+	atomic.StoreInt64(&r.lastGatherDuration, int64(elapsed))
 	return err
 }
 
+// LastGather returns when the last completed Gather started and how long it
+// took.  The time is zero if Gather has not completed.
+func (r *RunningInput) LastGather() (time.Time, time.Duration) {
+	start := atomic.LoadInt64(&r.lastGatherStart)
+	if start == 0 {
+		return time.Time{}, 0
+	}
+	return time.Unix(0, start), time.Duration(atomic.LoadInt64(&r.lastGatherDuration))
+}
+
+// LastError returns the last error logged by the input and when it was
+// logged.
+func (r *RunningInput) LastError() (string, time.Time) {
+	return r.log.LastError()
+}
+
 func (r *RunningInput) SetDefaultTags(tags map[string]string) {
 	r.defaultTags = tags
 }
@@ -1,6 +1,11 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +20,9 @@ const (
 
 	// Default number of metrics kept. It should be a multiple of batch size.
 	DEFAULT_METRIC_BUFFER_LIMIT = 10000
+
+	// Default maximum size of the metrics kept in a buffer log.
+	DEFAULT_BUFFER_MAX_BYTES = 64 * 1024 * 1024
 )
 
 // OutputConfig containing name and filter
@@ -28,6 +36,32 @@ type OutputConfig struct {
 	MetricBufferLimit int
 	MetricBatchSize   int
 
+	// BufferDirectory enables a write-ahead log for the buffer, stored in
+	// this directory, when set.
+	BufferDirectory string
+	BufferMaxBytes  int64
+	BufferFsync     string
+
+	// MaxMetricsPerSecond and MaxBytesPerSecond limit the rate metrics are
+	// written at when non-zero.  Bytes are estimated in line protocol.
+	MaxMetricsPerSecond int
+	MaxBytesPerSecond   int64
+
+	// AdaptiveBatchSize shrinks the batch when a write fails because the
+	// batch is too large or times out, and grows it back on success.
+	AdaptiveBatchSize bool
+
+	// RetryInitialInterval and RetryMaxInterval bound the backoff of writes
+	// after a failure; writes are retried on the next flush when zero.
+	RetryInitialInterval time.Duration
+	RetryMaxInterval     time.Duration
+
+	// CircuitBreakerThreshold is the number of consecutive failures after
+	// which writes are paused for the CircuitBreakerTimeout; zero disables
+	// the circuit breaker.
+	CircuitBreakerThreshold int
+	CircuitBreakerTimeout   time.Duration
+
 	NameOverride string
 	NamePrefix   string
 	NameSuffix   string
@@ -36,21 +70,41 @@ type OutputConfig struct {
 // RunningOutput contains the output configuration
 type RunningOutput struct {
 	// Must be 64-bit aligned
-	newMetricsCount int64
-	droppedMetrics  int64
+	newMetricsCount   int64
+	droppedMetrics    int64
+	lastWriteStart    int64
+	lastWriteDuration int64
+	batchSize         int64
 
 	Output            telegraf.Output
 	Config            *OutputConfig
+	ID                string // identifies the plugin by its configuration
 	MetricBufferLimit int
 	MetricBatchSize   int
 
 	MetricsFiltered selfstat.Stat
 	WriteTime       selfstat.Stat
+	BatchSize       selfstat.Stat
+
+	ConsecutiveFailures selfstat.Stat
+	CircuitOpen         selfstat.Stat
 
 	BatchReady chan time.Time
 
 	buffer *Buffer
-	log    telegraf.Logger
+	log    *Logger
+
+	metricLimiter *rateLimiter
+	byteLimiter   *rateLimiter
+	sleep         func(time.Duration) bool
+	stop          chan struct{}
+	stopOnce      sync.Once
+
+	breaker *circuitBreaker
+
+	errMutex         sync.Mutex
+	lastWriteErr     string
+	lastWriteErrTime time.Time
 
 	aggMutex sync.Mutex
 }
@@ -104,7 +158,43 @@ func NewRunningOutput(
 			"write_time_ns",
 			tags,
 		),
-		log: logger,
+		ConsecutiveFailures: selfstat.Register(
+			"write",
+			"consecutive_failures",
+			tags,
+		),
+		CircuitOpen: selfstat.Register(
+			"write",
+			"circuit_open",
+			tags,
+		),
+		log:       logger,
+		batchSize: int64(batchSize),
+		stop:      make(chan struct{}),
+		breaker: &circuitBreaker{
+			initial:   config.RetryInitialInterval,
+			max:       config.RetryMaxInterval,
+			threshold: config.CircuitBreakerThreshold,
+			timeout:   config.CircuitBreakerTimeout,
+		},
+	}
+	if ro.breaker.max == 0 {
+		ro.breaker.max = DefaultRetryMaxInterval
+	}
+	if ro.breaker.timeout == 0 {
+		ro.breaker.timeout = DefaultCircuitBreakerTimeout
+	}
+
+	ro.sleep = ro.sleepUnlessStopped
+	if config.MaxMetricsPerSecond > 0 {
+		ro.metricLimiter = newRateLimiter(float64(config.MaxMetricsPerSecond))
+	}
+	if config.MaxBytesPerSecond > 0 {
+		ro.byteLimiter = newRateLimiter(float64(config.MaxBytesPerSecond))
+	}
+	if config.AdaptiveBatchSize {
+		ro.BatchSize = selfstat.Register("write", "batch_size", tags)
+		ro.BatchSize.Set(int64(batchSize))
 	}
 
 	return ro
@@ -127,9 +217,85 @@ func (r *RunningOutput) Init() error {
 		}
 
 	}
+
+	if r.Config.BufferDirectory != "" {
+		err := r.openBufferLog()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate initializes the output plugin and checks the buffer settings,
+// like Init does, but without opening the buffer log, which may be in use by
+// a running agent.
+func (r *RunningOutput) Validate() error {
+	if p, ok := r.Output.(telegraf.Initializer); ok {
+		err := p.Init()
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Config.BufferDirectory == "" {
+		return nil
+	}
+	fsync := r.Config.BufferFsync
+	if fsync == "" {
+		fsync = FsyncFlush
+	}
+	if err := ValidFsyncPolicy(fsync); err != nil {
+		return err
+	}
+	info, err := os.Stat(r.Config.BufferDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("checking buffer directory: %w", err)
+	}
+	if err == nil && !info.IsDir() {
+		return fmt.Errorf("buffer directory %q is not a directory", r.Config.BufferDirectory)
+	}
+	return nil
+}
+
+// openBufferLog backs the buffer with a write-ahead log in the configured
+// buffer directory, restoring any metrics left unwritten by a previous run.
+func (r *RunningOutput) openBufferLog() error {
+	err := os.MkdirAll(r.Config.BufferDirectory, 0750)
+	if err != nil {
+		return fmt.Errorf("creating buffer directory: %w", err)
+	}
+
+	fsync := r.Config.BufferFsync
+	if fsync == "" {
+		fsync = FsyncFlush
+	}
+
+	path := filepath.Join(r.Config.BufferDirectory, BufferLogName(r.Config.Name, r.Config.Alias))
+	n, err := r.buffer.OpenWAL(path, r.Config.BufferMaxBytes, fsync, r.log)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		r.log.Infof("Restored %d unwritten metrics from %s", n, path)
+	}
 	return nil
 }
 
+// BufferLogName returns the file name of the write-ahead log for an output.
+func BufferLogName(name, alias string) string {
+	if alias == "" {
+		return name + ".wal"
+	}
+	alias = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, alias)
+	return name + "-" + alias + ".wal"
+}
+
 // AddMetric adds a metric to the output.
 //
 // Takes ownership of metric
@@ -193,9 +359,9 @@ func (ro *RunningOutput) Write() error {
 	// Only process the metrics in the buffer now.  Metrics added while we are
 	// writing will be sent on the next call.
 	nBuffer := ro.buffer.Len()
-	nBatches := nBuffer/ro.MetricBatchSize + 1
+	nBatches := nBuffer/ro.currentBatchSize() + 1
 	for i := 0; i < nBatches; i++ {
-		batch := ro.buffer.Batch(ro.MetricBatchSize)
+		batch := ro.buffer.Batch(ro.currentBatchSize())
 		if len(batch) == 0 {
 			break
 		}
@@ -212,7 +378,7 @@ func (ro *RunningOutput) Write() error {
 
 // WriteBatch writes a single batch of metrics to the output.
 func (ro *RunningOutput) WriteBatch() error {
-	batch := ro.buffer.Batch(ro.MetricBatchSize)
+	batch := ro.buffer.Batch(ro.currentBatchSize())
 	if len(batch) == 0 {
 		return nil
 	}
@@ -227,12 +393,38 @@ func (ro *RunningOutput) WriteBatch() error {
 	return nil
 }
 
+// WriteMetrics writes the metrics to the output directly, bypassing the
+// buffer and the filters.  It is used by output groups to write the batches
+// of the group's buffer to their child outputs.
+func (ro *RunningOutput) WriteMetrics(metrics []telegraf.Metric) error {
+	return ro.write(metrics)
+}
+
+// Stop interrupts the waits for the rate limits, so that a write does not
+// delay stopping the agent or removing the output.  The metrics not written
+// are returned to the buffer.
+func (r *RunningOutput) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+
+	// Outputs writing to child outputs, such as output groups, stop them.
+	if s, ok := r.Output.(interface{ Stop() }); ok {
+		s.Stop()
+	}
+}
+
 // Close closes the output
 func (r *RunningOutput) Close() {
 	err := r.Output.Close()
 	if err != nil {
 		r.log.Errorf("Error closing output: %v", err)
 	}
+
+	err = r.buffer.Close()
+	if err != nil {
+		r.log.Errorf("Error closing buffer log: %v", err)
+	}
 }
 
 func (r *RunningOutput) write(metrics []telegraf.Metric) error {
@@ -242,17 +434,202 @@ func (r *RunningOutput) write(metrics []telegraf.Metric) error {
 		atomic.StoreInt64(&r.droppedMetrics, 0)
 	}
 
+	// Batches larger than the rate limits allow at once are written in
+	// chunks.
+	var err error
+	for rest := metrics; len(rest) > 0 && err == nil; {
+		var n int
+		n, err = r.waitRateLimit(rest)
+		if err != nil {
+			return err
+		}
+		err = r.writeChunk(rest[:n])
+		rest = rest[n:]
+	}
+	r.adaptBatchSize(len(metrics), err)
+	return err
+}
+
+func (r *RunningOutput) writeChunk(metrics []telegraf.Metric) error {
 	start := time.Now()
 	err := r.Output.Write(metrics)
 	elapsed := time.Since(start)
 	r.WriteTime.Incr(elapsed.Nanoseconds())
+	atomic.StoreInt64(&r.lastWriteStart, start.UnixNano())
+	atomic.StoreInt64(&r.lastWriteDuration, int64(elapsed))
 
 	if err == nil {
 		r.log.Debugf("Wrote batch of %d metrics in %s", len(metrics), elapsed)
+	} else {
+		r.errMutex.Lock()
+		r.lastWriteErr = err.Error()
+		r.lastWriteErrTime = start
+		r.errMutex.Unlock()
 	}
+	r.updateHealth(start, err)
 	return err
 }
 
+// updateHealth records the result of a write in the circuit breaker.
+func (r *RunningOutput) updateHealth(start time.Time, err error) {
+	if err == nil {
+		if r.breaker.success() {
+			r.log.Infof("Write succeeded, closing circuit breaker")
+		}
+	} else if r.breaker.failure(start) {
+		r.log.Warnf("Circuit breaker opened after %d consecutive write failures, pausing writes for %s",
+			r.breaker.threshold, r.breaker.timeout)
+	}
+
+	failures, open := r.breaker.state()
+	r.ConsecutiveFailures.Set(int64(failures))
+	if open {
+		r.CircuitOpen.Set(1)
+	} else {
+		r.CircuitOpen.Set(0)
+	}
+}
+
+// WriteAllowed returns false while writes are delayed by the retry backoff
+// or paused by an open circuit breaker.
+func (r *RunningOutput) WriteAllowed() bool {
+	return r.breaker.allow(time.Now())
+}
+
+// Health returns the number of consecutive write failures and if the circuit
+// breaker is open.
+func (r *RunningOutput) Health() (int, bool) {
+	return r.breaker.state()
+}
+
+// waitRateLimit waits until the first metrics can be written without
+// exceeding the configured rates, and returns how many.  They are limited to
+// one second of the rates, so that a large batch is split instead of waiting
+// for long.  An error is returned if the output is stopped while waiting.
+func (r *RunningOutput) waitRateLimit(metrics []telegraf.Metric) (int, error) {
+	n := len(metrics)
+	if r.metricLimiter == nil && r.byteLimiter == nil {
+		return n, nil
+	}
+
+	if r.metricLimiter != nil && float64(n) > r.metricLimiter.rate {
+		n = int(r.metricLimiter.rate)
+	}
+	var size int
+	if r.byteLimiter != nil {
+		for i, m := range metrics[:n] {
+			s := estimateSize(m)
+			if i > 0 && float64(size+s) > r.byteLimiter.rate {
+				n = i
+				break
+			}
+			size += s
+		}
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	if r.metricLimiter != nil {
+		wait = r.metricLimiter.reserve(now, float64(n))
+	}
+	if r.byteLimiter != nil {
+		if d := r.byteLimiter.reserve(now, float64(size)); d > wait {
+			wait = d
+		}
+	}
+
+	if wait > 0 {
+		r.log.Debugf("Rate limit reached, waiting %s to write %d metrics", wait, n)
+		if !r.sleep(wait) {
+			return 0, errors.New("stopped while waiting for the rate limit")
+		}
+	}
+	return n, nil
+}
+
+// sleepUnlessStopped sleeps for d, and returns false if the output is stopped
+// before.
+func (r *RunningOutput) sleepUnlessStopped(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.stop:
+		return false
+	}
+}
+
+// currentBatchSize returns the number of metrics to write in a batch, which
+// is less than the metric_batch_size when adapting to write errors.
+func (r *RunningOutput) currentBatchSize() int {
+	return int(atomic.LoadInt64(&r.batchSize))
+}
+
+// adaptBatchSize halves the batch size when a write of n metrics fails with
+// an error a smaller batch may avoid, and grows it by a tenth of the
+// metric_batch_size after each successful write of a full batch.
+func (r *RunningOutput) adaptBatchSize(n int, err error) {
+	if !r.Config.AdaptiveBatchSize {
+		return
+	}
+
+	size := r.currentBatchSize()
+	switch {
+	case err == nil:
+		if n < size || size >= r.MetricBatchSize {
+			return
+		}
+		grow := r.MetricBatchSize / 10
+		if grow < 1 {
+			grow = 1
+		}
+		size += grow
+		if size > r.MetricBatchSize {
+			size = r.MetricBatchSize
+		}
+		r.log.Debugf("Increasing batch size to %d metrics", size)
+	case isBatchTooLarge(err):
+		if n < size {
+			size = n
+		}
+		if size <= 1 {
+			return
+		}
+		size /= 2
+		r.log.Warnf("Write of %d metrics failed, reducing batch size to %d metrics", n, size)
+	default:
+		return
+	}
+
+	atomic.StoreInt64(&r.batchSize, int64(size))
+	r.BatchSize.Set(int64(size))
+}
+
+// LastWrite returns when the last completed write started and how long it
+// took.  The time is zero if no write has completed.
+func (r *RunningOutput) LastWrite() (time.Time, time.Duration) {
+	start := atomic.LoadInt64(&r.lastWriteStart)
+	if start == 0 {
+		return time.Time{}, 0
+	}
+	return time.Unix(0, start), time.Duration(atomic.LoadInt64(&r.lastWriteDuration))
+}
+
+// LastWriteError returns the error of the last failed write and when the
+// write started.
+func (r *RunningOutput) LastWriteError() (string, time.Time) {
+	r.errMutex.Lock()
+	defer r.errMutex.Unlock()
+	return r.lastWriteErr, r.lastWriteErrTime
+}
+
+// LastError returns the last error logged by the output and when it was
+// logged.
+func (r *RunningOutput) LastError() (string, time.Time) {
+	return r.log.LastError()
+}
+
 func (r *RunningOutput) LogBufferStatus() {
 	nBuffer := r.buffer.Len()
 	r.log.Debugf("Buffer fullness: %d / %d metrics", nBuffer, r.MetricBufferLimit)
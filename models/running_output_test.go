@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -488,14 +489,16 @@ func TestInternalMetrics(t *testing.T) {
 				"alias":  "test_alias",
 			},
 			map[string]interface{}{
-				"buffer_limit":     10,
-				"buffer_size":      0,
-				"errors":           0,
-				"metrics_added":    0,
-				"metrics_dropped":  0,
-				"metrics_filtered": 0,
-				"metrics_written":  0,
-				"write_time_ns":    0,
+				"buffer_limit":         10,
+				"buffer_size":          0,
+				"circuit_open":         0,
+				"consecutive_failures": 0,
+				"errors":               0,
+				"metrics_added":        0,
+				"metrics_dropped":      0,
+				"metrics_filtered":     0,
+				"metrics_written":      0,
+				"write_time_ns":        0,
 			},
 			time.Unix(0, 0),
 		),
@@ -512,6 +515,206 @@ func TestInternalMetrics(t *testing.T) {
 	testutil.RequireMetricsEqual(t, expected, actual, testutil.IgnoreTime())
 }
 
+func TestRunningOutputRateLimit(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:              Filter{},
+		MaxMetricsPerSecond: 5,
+	}
+
+	m := &mockOutput{}
+	ro := NewRunningOutput("test", m, conf, 5, 100)
+	var waits []time.Duration
+	ro.sleep = func(d time.Duration) bool {
+		waits = append(waits, d)
+		return true
+	}
+
+	for _, metric := range append(first5, next5...) {
+		ro.AddMetric(metric)
+	}
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 10)
+
+	// The first batch fits in the limit, the second waits about a second.
+	require.Len(t, waits, 1)
+	require.InDelta(t, time.Second, waits[0], float64(100*time.Millisecond))
+}
+
+func TestRunningOutputByteRateLimit(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:            Filter{},
+		MaxBytesPerSecond: 10,
+	}
+
+	m := &mockOutput{}
+	ro := NewRunningOutput("test", m, conf, 5, 100)
+	var waits []time.Duration
+	ro.sleep = func(d time.Duration) bool {
+		waits = append(waits, d)
+		return true
+	}
+
+	// Each metric is larger than the limit, so they are written one at a
+	// time, each taking the whole second of the limit.
+	ro.AddMetric(testutil.TestMetric(101, "metric1"))
+	ro.AddMetric(testutil.TestMetric(101, "metric2"))
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 2)
+	require.Len(t, waits, 1)
+	require.InDelta(t, time.Second, waits[0], float64(100*time.Millisecond))
+}
+
+func TestRunningOutputRateLimitSplitsBatch(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:              Filter{},
+		MaxMetricsPerSecond: 2,
+	}
+
+	m := &mockOutput{}
+	ro := NewRunningOutput("test", m, conf, 5, 100)
+	var written []int
+	ro.sleep = func(d time.Duration) bool {
+		written = append(written, len(m.Metrics()))
+		return true
+	}
+
+	for _, metric := range first5 {
+		ro.AddMetric(metric)
+	}
+	require.NoError(t, ro.Write())
+	require.Len(t, m.Metrics(), 5)
+
+	// The batch is written in chunks of 2, 2 and 1 metrics, waiting for the
+	// limit before the last two.
+	require.Equal(t, []int{2, 4}, written)
+}
+
+func TestRunningOutputRateLimitStop(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:              Filter{},
+		MaxMetricsPerSecond: 1,
+	}
+
+	m := &mockOutput{}
+	ro := NewRunningOutput("test", m, conf, 5, 100)
+	ro.AddMetric(testutil.TestMetric(101, "metric1"))
+	ro.AddMetric(testutil.TestMetric(101, "metric2"))
+
+	done := make(chan error)
+	go func() {
+		done <- ro.Write()
+	}()
+	ro.Stop()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "write not interrupted")
+	}
+	require.Equal(t, 2, ro.BufferLength())
+}
+
+func TestRunningOutputAdaptiveBatchSize(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:            Filter{},
+		AdaptiveBatchSize: true,
+	}
+
+	m := &sizeLimitedOutput{limit: 4}
+	ro := NewRunningOutput("test", m, conf, 10, 100)
+
+	for i := 0; i < 10; i++ {
+		ro.AddMetric(testutil.TestMetric(101, "metric"))
+	}
+
+	// Halve the batch on each failure until it fits.
+	require.Error(t, ro.Write())
+	require.Equal(t, 5, ro.currentBatchSize())
+	require.Error(t, ro.Write())
+	require.Equal(t, 2, ro.currentBatchSize())
+
+	// Grow back by a tenth of the maximum after each full batch, writing
+	// batches of 2, 3, 4 and 1 metrics.
+	require.NoError(t, ro.Write())
+	require.Equal(t, 0, ro.BufferLength())
+	require.Equal(t, 10, m.written)
+	require.Equal(t, 5, ro.currentBatchSize())
+
+	// Other errors do not change the batch size.
+	m.err = errors.New("connection refused")
+	for i := 0; i < 10; i++ {
+		ro.AddMetric(testutil.TestMetric(101, "metric"))
+	}
+	require.Error(t, ro.Write())
+	require.Equal(t, 5, ro.currentBatchSize())
+}
+
+func TestRunningOutputCircuitBreaker(t *testing.T) {
+	conf := &OutputConfig{
+		Filter:                  Filter{},
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerTimeout:   time.Hour,
+	}
+
+	m := &mockOutput{failWrite: true}
+	ro := NewRunningOutput("test", m, conf, 5, 100)
+	ro.AddMetric(testutil.TestMetric(101, "metric1"))
+
+	require.Error(t, ro.Write())
+	require.True(t, ro.WriteAllowed())
+	require.Equal(t, int64(1), ro.ConsecutiveFailures.Get())
+
+	require.Error(t, ro.Write())
+	require.False(t, ro.WriteAllowed())
+	require.Equal(t, int64(2), ro.ConsecutiveFailures.Get())
+	require.Equal(t, int64(1), ro.CircuitOpen.Get())
+
+	// A requested write is attempted while the circuit is open, and closes
+	// it on success.
+	m.failWrite = false
+	require.NoError(t, ro.Write())
+	require.True(t, ro.WriteAllowed())
+	failures, open := ro.Health()
+	require.Equal(t, 0, failures)
+	require.False(t, open)
+	require.Equal(t, int64(0), ro.CircuitOpen.Get())
+	require.Len(t, m.Metrics(), 1)
+}
+
+type sizeLimitedOutput struct {
+	limit   int
+	written int
+	err     error
+}
+
+func (m *sizeLimitedOutput) Connect() error {
+	return nil
+}
+
+func (m *sizeLimitedOutput) Close() error {
+	return nil
+}
+
+func (m *sizeLimitedOutput) Description() string {
+	return ""
+}
+
+func (m *sizeLimitedOutput) SampleConfig() string {
+	return ""
+}
+
+func (m *sizeLimitedOutput) Write(metrics []telegraf.Metric) error {
+	if m.err != nil {
+		return m.err
+	}
+	if len(metrics) > m.limit {
+		return errors.New("received status code: 413")
+	}
+	m.written += len(metrics)
+	return nil
+}
+
 type mockOutput struct {
 	sync.Mutex
 
@@ -12,6 +12,7 @@ type RunningProcessor struct {
 	log       telegraf.Logger
 	Processor telegraf.StreamingProcessor
 	Config    *ProcessorConfig
+	ID        string // identifies the plugin by its configuration
 }
 
 type RunningProcessors []*RunningProcessor
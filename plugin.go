@@ -9,6 +9,22 @@ type Initializer interface {
 	Init() error
 }
 
+// StatefulPlugin is an interface that plugins can optionally implement to
+// keep their state across restarts of Telegraf.  When a state file is
+// configured, the agent saves the state of the plugin to it periodically and
+// when stopping, and restores it after Init and before the plugin is started.
+type StatefulPlugin interface {
+	// GetState returns the state of the plugin, which must be serializable
+	// to JSON, or nil if it has no state to save.  It may be called while the
+	// plugin is running.
+	GetState() interface{}
+
+	// SetState restores the state saved from a previous run.  The state has
+	// the type of the value returned by GetState, with numbers in values of
+	// interface types as json.Number.
+	SetState(state interface{}) error
+}
+
 // PluginDescriber contains the functions all plugins must implement to describe
 // themselves to Telegraf. Note that all plugins may define a logger that is
 // not part of the interface, but will receive an injected logger if it's set.
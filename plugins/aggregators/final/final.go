@@ -1,11 +1,15 @@
 package final
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/plugins/aggregators"
+	"github.com/influxdata/telegraf/plugins/parsers/influx"
+	serializer "github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
 var sampleConfig = `
@@ -22,7 +26,9 @@ var sampleConfig = `
 type Final struct {
 	SeriesTimeout internal.Duration `toml:"series_timeout"`
 
-	// The last metric for all series which are active
+	// The last metric for all series which are active, guarded by mu as it
+	// is read by GetState while running.
+	mu          sync.Mutex
 	metricCache map[uint64]telegraf.Metric
 }
 
@@ -42,11 +48,17 @@ func (m *Final) Description() string {
 }
 
 func (m *Final) Add(in telegraf.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	id := in.HashID()
 	m.metricCache[id] = in
 }
 
 func (m *Final) Push(acc telegraf.Accumulator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// Preserve timestamp of original metric
 	acc.SetPrecision(time.Nanosecond)
 
@@ -65,6 +77,46 @@ func (m *Final) Push(acc telegraf.Accumulator) {
 func (m *Final) Reset() {
 }
 
+// GetState returns the last metric of the active series in line protocol.
+func (m *Final) GetState() interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := serializer.NewSerializer()
+	s.SetFieldTypeSupport(serializer.UintSupport)
+	state := make([]string, 0, len(m.metricCache))
+	for _, metric := range m.metricCache {
+		buf, err := s.Serialize(metric)
+		if err != nil {
+			continue
+		}
+		state = append(state, string(buf))
+	}
+	return state
+}
+
+// SetState restores the last metric of the active series from line
+// protocol, so that series ending while Telegraf is stopped are reported.
+func (m *Final) SetState(state interface{}) error {
+	lines, ok := state.([]string)
+	if !ok {
+		return fmt.Errorf("invalid state of type %T", state)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parser := influx.NewParser(influx.NewMetricHandler())
+	for _, line := range lines {
+		metric, err := parser.ParseLine(line)
+		if err != nil {
+			return fmt.Errorf("invalid metric %q: %v", line, err)
+		}
+		m.metricCache[metric.HashID()] = metric
+	}
+	return nil
+}
+
 func init() {
 	aggregators.Add("final", func() telegraf.Aggregator {
 		return NewFinal()
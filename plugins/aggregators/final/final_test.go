@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSimple(t *testing.T) {
@@ -142,3 +143,41 @@ func TestLongDifference(t *testing.T) {
 	}
 	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(), testutil.SortMetrics())
 }
+
+func TestState(t *testing.T) {
+	acc := testutil.Accumulator{}
+	final := NewFinal()
+
+	tags := map[string]string{"foo": "bar"}
+	m1, _ := metric.New("m1",
+		tags,
+		map[string]interface{}{
+			"a": int64(1),
+			"b": 2.0,
+			"c": uint64(3),
+			"d": "four",
+		},
+		time.Unix(1530939936, 0))
+	final.Add(m1)
+	state := final.GetState()
+
+	// The series is reported by a new instance restored from the state.
+	restored := NewFinal()
+	require.NoError(t, restored.SetState(state))
+	restored.Push(&acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"m1",
+			tags,
+			map[string]interface{}{
+				"a_final": int64(1),
+				"b_final": 2.0,
+				"c_final": uint64(3),
+				"d_final": "four",
+			},
+			time.Unix(1530939936, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
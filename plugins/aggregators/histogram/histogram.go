@@ -1,10 +1,13 @@
 package histogram
 
 import (
+	"math"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/plugins/aggregators"
 )
 
@@ -25,6 +28,7 @@ type HistogramAggregator struct {
 	Configs      []config `toml:"config"`
 	ResetBuckets bool     `toml:"reset"`
 	Cumulative   bool     `toml:"cumulative"`
+	Native       bool     `toml:"native_histogram"`
 
 	buckets bucketsByMetrics
 	cache   map[uint64]metricHistogramCollection
@@ -49,6 +53,7 @@ type buckets []float64
 // metricHistogramCollection aggregates the histogram data
 type metricHistogramCollection struct {
 	histogramCollection map[string]counts
+	sums                map[string]float64
 	name                string
 	tags                map[string]string
 }
@@ -90,6 +95,12 @@ var sampleConfig = `
   ## Defaults to true.
   cumulative = true
 
+  ## If true, and cumulative is true, the buckets are emitted as histogram
+  ## metrics, along with a metric holding the count, sum and complete
+  ## histogram of each field, for outputs supporting histograms such as
+  ## prometheus_client.
+  # native_histogram = false
+
   ## Example config that aggregates all fields of the metric.
   # [[aggregators.histogram.config]]
   #   ## Right borders of buckets (with +Inf implicitly added).
@@ -138,6 +149,7 @@ func (h *HistogramAggregator) Add(in telegraf.Metric) {
 			name:                in.Name(),
 			tags:                in.Tags(),
 			histogramCollection: make(map[string]counts),
+			sums:                make(map[string]float64),
 		}
 	}
 
@@ -150,6 +162,7 @@ func (h *HistogramAggregator) Add(in telegraf.Metric) {
 			if value, ok := convert(value); ok {
 				index := sort.SearchFloat64s(buckets, value)
 				agr.histogramCollection[field][index]++
+				agr.sums[field] += value
 			}
 		}
 	}
@@ -167,9 +180,54 @@ func (h *HistogramAggregator) Push(acc telegraf.Accumulator) {
 		}
 	}
 
+	if !h.Cumulative || !h.Native {
+		for _, metric := range metricsWithGroupedFields {
+			acc.AddFields(metric.name, makeFieldsWithCount(metric.fieldsWithCount), metric.tags)
+		}
+		return
+	}
+
+	// Cumulative buckets form a histogram, which is also added as a whole
+	// so that outputs don't have to assemble it from the bucket metrics.
+	// All metrics of the push have the same time.
+	now := time.Now()
 	for _, metric := range metricsWithGroupedFields {
-		acc.AddFields(metric.name, makeFieldsWithCount(metric.fieldsWithCount), metric.tags)
+		acc.AddHistogram(metric.name, makeFieldsWithCount(metric.fieldsWithCount), metric.tags, now)
+	}
+	for _, aggregate := range h.cache {
+		acc.AddMetric(h.makeHistogramMetric(aggregate, now))
+	}
+}
+
+// makeHistogramMetric returns the metric with the count and sum of the
+// fields of an aggregate, carrying their histograms.
+func (h *HistogramAggregator) makeHistogramMetric(aggregate metricHistogramCollection, tm time.Time) telegraf.Metric {
+	fields := make(map[string]interface{})
+	distributions := make([]*telegraf.Distribution, 0, len(aggregate.histogramCollection))
+	for field, counts := range aggregate.histogramCollection {
+		buckets := h.getBuckets(aggregate.name, field)
+		d := &telegraf.Distribution{
+			Key: field,
+			Sum: aggregate.sums[field],
+		}
+		for index, count := range counts {
+			d.Count += uint64(count)
+			bound := math.Inf(1)
+			if index < len(buckets) {
+				bound = buckets[index]
+			}
+			d.Buckets = append(d.Buckets, telegraf.Bucket{UpperBound: bound, Count: d.Count})
+		}
+		fields[field+"_count"] = int64(d.Count)
+		fields[field+"_sum"] = d.Sum
+		distributions = append(distributions, d)
+	}
+
+	m, _ := metric.New(aggregate.name, copyTags(aggregate.tags), fields, tm, telegraf.Histogram)
+	for _, d := range distributions {
+		m.AddDistribution(d)
 	}
+	return m
 }
 
 // groupFieldsByBuckets groups fields by metric buckets which are represented as tags
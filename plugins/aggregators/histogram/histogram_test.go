@@ -2,6 +2,7 @@ package histogram
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/influxdata/telegraf/metric"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type fields map[string]interface{}
@@ -238,6 +240,73 @@ func TestHistogramWithTwoPeriodsAndAllFields(t *testing.T) {
 	assertContainsTaggedField(t, acc, "first_metric_name", fields{"a_bucket": int64(2), "b_bucket": int64(1), "c_bucket": int64(1)}, tags{bucketRightTag: bucketPosInf})
 }
 
+// TestHistogramDistribution tests the histogram carried by the metric with the count and sum of a field
+func TestHistogramDistribution(t *testing.T) {
+	var cfg []config
+	cfg = append(cfg, config{Metric: "first_metric_name", Fields: []string{"a"}, Buckets: []float64{0.0, 10.0, 20.0}})
+	histogram := NewHistogramAggregator()
+	histogram.Configs = cfg
+	histogram.Cumulative = true
+	histogram.Native = true
+
+	acc := &testutil.Accumulator{}
+
+	histogram.Add(firstMetric1)
+	histogram.Add(firstMetric2)
+	histogram.Push(acc)
+
+	a, c := 15.3, 15.9
+	sum := a + c
+	expected := testutil.MustMetric(
+		"first_metric_name",
+		tags{},
+		fields{"a_count": int64(2), "a_sum": sum},
+		time.Unix(0, 0),
+		telegraf.Histogram,
+	)
+	expected.AddDistribution(&telegraf.Distribution{
+		Key:   "a",
+		Count: 2,
+		Sum:   sum,
+		Buckets: []telegraf.Bucket{
+			{UpperBound: 0, Count: 0},
+			{UpperBound: 10, Count: 0},
+			{UpperBound: 20, Count: 2},
+			{UpperBound: math.Inf(1), Count: 2},
+		},
+	})
+
+	var actual []telegraf.Metric
+	metrics := acc.GetTelegrafMetrics()
+	for _, m := range metrics {
+		if len(m.DistributionList()) > 0 {
+			actual = append(actual, m)
+		} else {
+			assert.Equal(t, telegraf.Histogram, m.Type())
+		}
+		assert.Equal(t, metrics[0].Time(), m.Time())
+	}
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{expected}, actual, testutil.IgnoreTime())
+}
+
+// TestHistogramNotNative tests that the buckets are untyped without histogram unless native_histogram is set
+func TestHistogramNotNative(t *testing.T) {
+	var cfg []config
+	cfg = append(cfg, config{Metric: "first_metric_name", Fields: []string{"a"}, Buckets: []float64{0.0, 10.0, 20.0}})
+	histogram := NewTestHistogram(cfg, false, true)
+
+	acc := &testutil.Accumulator{}
+
+	histogram.Add(firstMetric1)
+	histogram.Push(acc)
+
+	require.Len(t, acc.Metrics, 4)
+	for _, m := range acc.GetTelegrafMetrics() {
+		assert.Equal(t, telegraf.Untyped, m.Type())
+		assert.Empty(t, m.DistributionList())
+	}
+}
+
 // TestWrongBucketsOrder tests the calling panic with incorrect order of buckets
 func TestWrongBucketsOrder(t *testing.T) {
 	defer func() {
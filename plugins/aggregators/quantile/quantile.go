@@ -0,0 +1,236 @@
+package quantile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caio/go-tdigest"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+)
+
+const (
+	metricTypeFields  = "fields"
+	metricTypeSummary = "summary"
+
+	defaultCompression = 100
+)
+
+var defaultQuantiles = []float64{0.5, 0.95, 0.99}
+
+var sampleConfig = `
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## Quantiles to compute, in the range [0, 1].
+  # quantiles = [0.5, 0.95, 0.99]
+
+  ## Compression of the t-digest sketch.  Higher values are more accurate,
+  ## especially for the extreme quantiles, but use more memory.
+  # compression = 100.0
+
+  ## How to emit the quantiles:
+  ##   "fields"  - add a field per quantile named <field>_p<percent>, such as
+  ##               latency_p99, to a metric with the name of the original
+  ##   "summary" - emit a summary metric named <measurement>_<field> per field,
+  ##               with count, sum and a field per quantile
+  # metric_type = "fields"
+`
+
+// Quantile estimates the quantiles of each field of each series over the
+// aggregation period, using a t-digest sketch.
+type Quantile struct {
+	Quantiles   []float64 `toml:"quantiles"`
+	Compression float64   `toml:"compression"`
+	MetricType  string    `toml:"metric_type"`
+
+	cache map[uint64]aggregate
+
+	// suffixes are the field name suffixes, or the summary field names, of
+	// the quantiles.
+	suffixes []string
+}
+
+type aggregate struct {
+	name   string
+	tags   map[string]string
+	fields map[string]*sketch
+}
+
+type sketch struct {
+	digest *tdigest.TDigest
+	sum    float64
+}
+
+func (q *Quantile) SampleConfig() string {
+	return sampleConfig
+}
+
+func (q *Quantile) Description() string {
+	return "Keep the quantiles of each metric passing through."
+}
+
+func (q *Quantile) Init() error {
+	if q.Quantiles == nil {
+		q.Quantiles = defaultQuantiles
+	}
+	if q.Compression == 0 {
+		q.Compression = defaultCompression
+	}
+	if q.Compression < 1 {
+		return fmt.Errorf("compression must be at least 1, got %v", q.Compression)
+	}
+
+	switch q.MetricType {
+	case "":
+		q.MetricType = metricTypeFields
+	case metricTypeFields, metricTypeSummary:
+	default:
+		return fmt.Errorf("unknown metric_type %q", q.MetricType)
+	}
+
+	q.suffixes = make([]string, 0, len(q.Quantiles))
+	seen := make(map[string]bool)
+	for _, quantile := range q.Quantiles {
+		if quantile < 0 || quantile > 1 {
+			return fmt.Errorf("quantile %v is not in the range [0, 1]", quantile)
+		}
+
+		var suffix string
+		if q.MetricType == metricTypeSummary {
+			suffix = strconv.FormatFloat(quantile, 'g', -1, 64)
+		} else {
+			percent := strconv.FormatFloat(quantile*100, 'f', -1, 64)
+			suffix = "_p" + strings.Replace(percent, ".", "_", 1)
+		}
+		if seen[suffix] {
+			return fmt.Errorf("duplicate quantile %v", quantile)
+		}
+		seen[suffix] = true
+		q.suffixes = append(q.suffixes, suffix)
+	}
+
+	q.Reset()
+	return nil
+}
+
+func (q *Quantile) Add(in telegraf.Metric) {
+	id := in.HashID()
+	a, ok := q.cache[id]
+	if !ok {
+		a = aggregate{
+			name:   in.Name(),
+			tags:   in.Tags(),
+			fields: make(map[string]*sketch),
+		}
+		q.cache[id] = a
+	}
+
+	for _, field := range in.FieldList() {
+		value, ok := convert(field.Value)
+		if !ok {
+			continue
+		}
+
+		s, ok := a.fields[field.Key]
+		if !ok {
+			digest, err := tdigest.New(tdigest.Compression(uint32(q.Compression)))
+			if err != nil {
+				continue
+			}
+			s = &sketch{digest: digest}
+			a.fields[field.Key] = s
+		}
+		if err := s.digest.Add(value); err != nil {
+			continue
+		}
+		s.sum += value
+	}
+}
+
+func (q *Quantile) Push(acc telegraf.Accumulator) {
+	for _, a := range q.cache {
+		if q.MetricType == metricTypeSummary {
+			q.pushSummaries(acc, a)
+			continue
+		}
+
+		fields := make(map[string]interface{}, len(a.fields)*len(q.Quantiles))
+		for name, s := range a.fields {
+			for i, quantile := range q.Quantiles {
+				fields[name+q.suffixes[i]] = s.digest.Quantile(quantile)
+			}
+		}
+		if len(fields) > 0 {
+			acc.AddFields(a.name, fields, a.tags)
+		}
+	}
+}
+
+// pushSummaries adds a summary metric for each field of the series, in the
+// form of the prometheus input: a count and sum field, and a field named
+// after each quantile.  The metric carries the summary as a whole, so that
+// outputs don't have to assemble it from the fields.
+func (q *Quantile) pushSummaries(acc telegraf.Accumulator, a aggregate) {
+	now := time.Now()
+	for name, s := range a.fields {
+		d := &telegraf.Distribution{
+			Count:     s.digest.Count(),
+			Sum:       s.sum,
+			Quantiles: make([]telegraf.Quantile, 0, len(q.Quantiles)),
+		}
+		fields := make(map[string]interface{}, len(q.Quantiles)+2)
+		fields["count"] = d.Count
+		fields["sum"] = d.Sum
+		for i, quantile := range q.Quantiles {
+			value := s.digest.Quantile(quantile)
+			fields[q.suffixes[i]] = value
+			d.Quantiles = append(d.Quantiles, telegraf.Quantile{Quantile: quantile, Value: value})
+		}
+		sort.Slice(d.Quantiles, func(i, j int) bool {
+			return d.Quantiles[i].Quantile < d.Quantiles[j].Quantile
+		})
+
+		tags := make(map[string]string, len(a.tags))
+		for k, v := range a.tags {
+			tags[k] = v
+		}
+		m, err := metric.New(a.name+"_"+name, tags, fields, now, telegraf.Summary)
+		if err != nil {
+			continue
+		}
+		m.AddDistribution(d)
+		acc.AddMetric(m)
+	}
+}
+
+func (q *Quantile) Reset() {
+	q.cache = make(map[uint64]aggregate)
+}
+
+func convert(in interface{}) (float64, bool) {
+	switch v := in.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	aggregators.Add("quantile", func() telegraf.Aggregator {
+		return &Quantile{}
+	})
+}
@@ -0,0 +1,103 @@
+package quantile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func addLatencies(q *Quantile, n int) {
+	for i := 1; i <= n; i++ {
+		q.Add(testutil.MustMetric("http",
+			map[string]string{"host": "a"},
+			map[string]interface{}{
+				"latency": float64(i),
+				"status":  "ok",
+			},
+			time.Unix(int64(i), 0),
+		))
+		q.Add(testutil.MustMetric("http",
+			map[string]string{"host": "b"},
+			map[string]interface{}{
+				"latency": int64(2 * i),
+			},
+			time.Unix(int64(i), 0),
+		))
+	}
+}
+
+func TestQuantileFields(t *testing.T) {
+	q := &Quantile{Quantiles: []float64{0.5, 0.99, 0.999}}
+	require.NoError(t, q.Init())
+	addLatencies(q, 1000)
+
+	acc := testutil.Accumulator{}
+	q.Push(&acc)
+	require.Len(t, acc.Metrics, 2)
+
+	for host, scale := range map[string]float64{"a": 1, "b": 2} {
+		var fields map[string]interface{}
+		for _, m := range acc.Metrics {
+			if m.Tags["host"] == host {
+				fields = m.Fields
+				require.Equal(t, "http", m.Measurement)
+				require.Equal(t, telegraf.Untyped, m.Type)
+			}
+		}
+		require.Len(t, fields, 3)
+		require.InDelta(t, 500*scale, fields["latency_p50"], 5*scale)
+		require.InDelta(t, 990*scale, fields["latency_p99"], 2*scale)
+		require.InDelta(t, 999*scale, fields["latency_p99_9"], 1*scale)
+	}
+
+	// Reset starts a new period.
+	q.Reset()
+	acc.ClearMetrics()
+	q.Push(&acc)
+	require.Empty(t, acc.Metrics)
+}
+
+func TestQuantileSummary(t *testing.T) {
+	q := &Quantile{MetricType: "summary"}
+	require.NoError(t, q.Init())
+	addLatencies(q, 100)
+
+	acc := testutil.Accumulator{}
+	q.Push(&acc)
+	require.Len(t, acc.Metrics, 2)
+
+	for _, m := range acc.Metrics {
+		require.Equal(t, "http_latency", m.Measurement)
+		require.Equal(t, telegraf.Summary, m.Type)
+		if m.Tags["host"] != "a" {
+			continue
+		}
+		require.Equal(t, uint64(100), m.Fields["count"])
+		require.Equal(t, float64(5050), m.Fields["sum"])
+		require.InDelta(t, 50, m.Fields["0.5"], 1)
+		require.InDelta(t, 95, m.Fields["0.95"], 1)
+		require.InDelta(t, 99, m.Fields["0.99"], 1)
+
+		// The metric carries the summary with the same values.
+		require.Len(t, m.Distributions, 1)
+		d := m.Distributions[0]
+		require.Equal(t, "", d.Key)
+		require.Equal(t, uint64(100), d.Count)
+		require.Equal(t, float64(5050), d.Sum)
+		require.Equal(t, []telegraf.Quantile{
+			{Quantile: 0.5, Value: m.Fields["0.5"].(float64)},
+			{Quantile: 0.95, Value: m.Fields["0.95"].(float64)},
+			{Quantile: 0.99, Value: m.Fields["0.99"].(float64)},
+		}, d.Quantiles)
+	}
+}
+
+func TestQuantileInvalid(t *testing.T) {
+	require.Error(t, (&Quantile{Quantiles: []float64{1.5}}).Init())
+	require.Error(t, (&Quantile{Quantiles: []float64{0.5, 0.5}}).Init())
+	require.Error(t, (&Quantile{Compression: 0.5}).Init())
+	require.Error(t, (&Quantile{MetricType: "histogram"}).Init())
+}
@@ -0,0 +1,172 @@
+package starlark
+
+import (
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/aggregators"
+	common "github.com/influxdata/telegraf/plugins/common/starlark"
+	"go.starlark.net/starlark"
+)
+
+const (
+	description  = "Aggregate metrics using a Starlark script"
+	sampleConfig = `
+  ## The period on which to flush & clear the aggregator.
+  period = "30s"
+
+  ## If true, the original metric will be dropped by the
+  ## aggregator and will not get sent to the output plugins.
+  drop_original = false
+
+  ## The Starlark source can be set as a string in this configuration file, or
+  ## by referencing a file containing the script.  Only one source or script
+  ## should be set at once.
+  ##
+  ## The script must define the functions add, called with each metric, push,
+  ## returning the metrics to emit at the end of each period, and reset,
+  ## called after push.  The predeclared dict state, and any other global of
+  ## the script, is kept between calls.
+  ##
+  ## Source of the Starlark script.
+  source = '''
+def add(metric):
+  state["last"] = metric
+
+def push():
+  return state.get("last")
+
+def reset():
+  state.clear()
+'''
+
+  ## File containing a Starlark script.
+  # script = "/usr/local/bin/myscript.star"
+`
+)
+
+// Starlark aggregates metrics with the add, push and reset functions of a
+// script.  Unlike in the processor, the globals of the script are not frozen,
+// so the functions can keep state across calls.
+type Starlark struct {
+	Source string `toml:"source"`
+	Script string `toml:"script"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	thread    *starlark.Thread
+	addFunc   *starlark.Function
+	pushFunc  *starlark.Function
+	resetFunc *starlark.Function
+}
+
+func (s *Starlark) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *Starlark) Description() string {
+	return description
+}
+
+func (s *Starlark) Init() error {
+	s.thread = common.NewThread(s.Log)
+
+	// The state is predeclared so that it can be used without being defined
+	// by the script.
+	builtins := common.Builtins()
+	builtins["state"] = starlark.NewDict(0)
+
+	globals, err := common.LoadScript(s.thread, "aggregator.starlark", s.Source, s.Script, builtins)
+	if err != nil {
+		return err
+	}
+
+	if s.addFunc, err = common.Function(globals, "add", 1); err != nil {
+		return err
+	}
+	if s.pushFunc, err = common.Function(globals, "push", 0); err != nil {
+		return err
+	}
+	if s.resetFunc, err = common.Function(globals, "reset", 0); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Starlark) Add(metric telegraf.Metric) {
+	// Each metric gets its own wrapper, so the script can keep references to
+	// the metrics it is passed.  The metric is a copy owned by the
+	// aggregator.
+	args := starlark.Tuple{&common.Metric{}}
+	args[0].(*common.Metric).Wrap(metric)
+
+	if _, err := s.call(s.addFunc, args); err != nil {
+		s.Log.Errorf("Error calling add: %v", err)
+	}
+}
+
+func (s *Starlark) Push(acc telegraf.Accumulator) {
+	rv, err := s.call(s.pushFunc, nil)
+	if err != nil {
+		s.Log.Errorf("Error calling push: %v", err)
+		return
+	}
+
+	metrics, err := asMetrics(rv)
+	if err != nil {
+		s.Log.Errorf("Error calling push: %v", err)
+		return
+	}
+
+	// The metrics are copied, as the script may return metrics it keeps in
+	// its state.
+	for _, m := range metrics {
+		acc.AddMetric(m.Copy())
+	}
+}
+
+func (s *Starlark) Reset() {
+	if _, err := s.call(s.resetFunc, nil); err != nil {
+		s.Log.Errorf("Error calling reset: %v", err)
+	}
+}
+
+func (s *Starlark) call(fn *starlark.Function, args starlark.Tuple) (starlark.Value, error) {
+	rv, err := starlark.Call(s.thread, fn, args, nil)
+	if err != nil {
+		common.LogError(s.Log, err)
+	}
+	return rv, err
+}
+
+// asMetrics returns the metrics returned by push: a metric, a list of
+// metrics, or None.
+func asMetrics(rv starlark.Value) ([]telegraf.Metric, error) {
+	switch rv := rv.(type) {
+	case *common.Metric:
+		return []telegraf.Metric{rv.Unwrap()}, nil
+	case *starlark.List:
+		metrics := make([]telegraf.Metric, 0, rv.Len())
+		iter := rv.Iterate()
+		defer iter.Done()
+		var v starlark.Value
+		for iter.Next(&v) {
+			m, ok := v.(*common.Metric)
+			if !ok {
+				return nil, fmt.Errorf("invalid type returned in list: %s", v.Type())
+			}
+			metrics = append(metrics, m.Unwrap())
+		}
+		return metrics, nil
+	case starlark.NoneType:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid type returned: %s", rv.Type())
+	}
+}
+
+func init() {
+	aggregators.Add("starlark", func() telegraf.Aggregator {
+		return &Starlark{}
+	})
+}
@@ -0,0 +1,407 @@
+package starlark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for runtime errors in the aggregators Init function.
+func TestInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin *Starlark
+	}{
+		{
+			name: "source must define add",
+			plugin: &Starlark{
+				Source: `
+def push():
+	pass
+
+def reset():
+	pass
+`,
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "source must define push",
+			plugin: &Starlark{
+				Source: `
+def add(metric):
+	pass
+
+def reset():
+	pass
+`,
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "reset must be a function",
+			plugin: &Starlark{
+				Source: `
+def add(metric):
+	pass
+
+def push():
+	pass
+
+reset = 42
+`,
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "add function must take one arg",
+			plugin: &Starlark{
+				Source: `
+def add():
+	pass
+
+def push():
+	pass
+
+def reset():
+	pass
+`,
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "push function must take no args",
+			plugin: &Starlark{
+				Source: `
+def add(metric):
+	pass
+
+def push(metric):
+	pass
+
+def reset():
+	pass
+`,
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "no source no script",
+			plugin: &Starlark{
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "source and script",
+			plugin: &Starlark{
+				Source: `
+def add(metric):
+	pass
+`,
+				Script: "testdata/max.star",
+				Log:    testutil.Logger{},
+			},
+		},
+		{
+			name: "script file not found",
+			plugin: &Starlark{
+				Script: "testdata/file_not_found.star",
+				Log:    testutil.Logger{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plugin.Init()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	var tests = []struct {
+		name     string
+		source   string
+		script   string
+		input    []telegraf.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name: "push nothing",
+			source: `
+def add(metric):
+	pass
+
+def push():
+	return None
+
+def reset():
+	pass
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"value": 42},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{},
+		},
+		{
+			name: "count metrics with predeclared state",
+			source: `
+def add(metric):
+	state["count"] = state.get("count", 0) + 1
+
+def push():
+	m = Metric("count")
+	m.fields["value"] = state["count"]
+	m.time = 0
+	return m
+
+def reset():
+	state.clear()
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{},
+					map[string]interface{}{"value": 1},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("mem",
+					map[string]string{},
+					map[string]interface{}{"value": 2},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("count",
+					map[string]string{},
+					map[string]interface{}{"value": 2},
+					time.Unix(0, 0),
+				),
+			},
+		},
+		{
+			name: "state defined by the script",
+			source: `
+last = {}
+
+def add(metric):
+	last[metric.tags["host"]] = metric
+
+def push():
+	return [last[k] for k in sorted(last.keys())]
+
+def reset():
+	last.clear()
+`,
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"value": 1},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"value": 2},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"value": 3},
+					time.Unix(1, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"value": 2},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"value": 3},
+					time.Unix(1, 0),
+				),
+			},
+		},
+		{
+			name:   "weighted average across series",
+			script: "testdata/weighted_average.star",
+			input: []telegraf.Metric{
+				testutil.MustMetric("latency",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"value": 10.0, "count": 1},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("latency",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"value": 20.0, "count": 3},
+					time.Unix(1, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("latency",
+					map[string]string{},
+					map[string]interface{}{"value": 17.5},
+					time.Unix(1, 0),
+				),
+			},
+		},
+		{
+			name:   "keep metric",
+			script: "testdata/max.star",
+			input: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "a"},
+					map[string]interface{}{"value": 10},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"value": 30},
+					time.Unix(0, 0),
+				),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "c"},
+					map[string]interface{}{"value": 20},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "b"},
+					map[string]interface{}{"value": 30},
+					time.Unix(0, 0),
+				),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &Starlark{
+				Source: tt.source,
+				Script: tt.script,
+				Log:    testutil.Logger{},
+			}
+			require.NoError(t, plugin.Init())
+
+			for _, m := range tt.input {
+				plugin.Add(m)
+			}
+
+			var acc testutil.Accumulator
+			plugin.Push(&acc)
+			testutil.RequireMetricsEqual(t, tt.expected, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	plugin := &Starlark{
+		Script: "testdata/weighted_average.star",
+		Log:    testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Add(testutil.MustMetric("latency",
+		map[string]string{},
+		map[string]interface{}{"value": 10.0, "count": 1},
+		time.Unix(0, 0),
+	))
+	plugin.Reset()
+	plugin.Add(testutil.MustMetric("latency",
+		map[string]string{},
+		map[string]interface{}{"value": 20.0, "count": 1},
+		time.Unix(1, 0),
+	))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("latency",
+			map[string]string{},
+			map[string]interface{}{"value": 20.0},
+			time.Unix(1, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+// Metrics kept in the state and pushed are copied, so that changes made by the
+// script later do not affect the metrics already pushed.
+func TestPushCopiesMetrics(t *testing.T) {
+	plugin := &Starlark{
+		Source: `
+def add(metric):
+	state["last"] = metric
+
+def push():
+	m = state["last"]
+	m.fields["value"] += 1
+	return m
+
+def reset():
+	pass
+`,
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0),
+	))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+	plugin.Push(&acc)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 2},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"value": 3},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestRuntimeError(t *testing.T) {
+	plugin := &Starlark{
+		Source: `
+def add(metric):
+	fail("oops")
+
+def push():
+	return 42
+
+def reset():
+	pass
+`,
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0),
+	))
+
+	var acc testutil.Accumulator
+	plugin.Push(&acc)
+	require.Empty(t, acc.GetTelegrafMetrics())
+}
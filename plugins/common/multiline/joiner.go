@@ -0,0 +1,128 @@
+package multiline
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// Joiner joins the lines of multiline events for the inputs receiving lines
+// one by one, such as the messages of a connection, rather than reading them
+// in a loop.  Every line is added with a value, such as the metadata of its
+// message, and events are emitted with the value of their first line.  An
+// event is emitted once the line following it is added, or after the timeout
+// of the configuration when no line follows.
+//
+// The emit function is called holding the lock of the joiner, possibly from
+// the goroutine of the timeout; it must not call the joiner.
+type Joiner struct {
+	multiline *Multiline
+	emit      func(text string, value interface{})
+	onTimeout func()
+
+	mu     sync.Mutex
+	buffer bytes.Buffer
+	first  interface{}
+	timer  *time.Timer
+}
+
+// NewJoiner returns a joiner emitting the events joined to emit.  When
+// multiline is disabled, every line is emitted as it is added.
+func (m *Multiline) NewJoiner(emit func(text string, value interface{})) *Joiner {
+	return &Joiner{
+		multiline: m,
+		emit:      emit,
+	}
+}
+
+// Add adds a line of an event.
+func (j *Joiner) Add(line string, value interface{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.multiline.IsEnabled() {
+		j.emit(line, value)
+		return
+	}
+
+	matched := j.multiline.matchString(line)
+	empty := j.buffer.Len() == 0
+	first := j.first
+	text := j.multiline.ProcessLine(line, &j.buffer)
+	switch {
+	case matched:
+		// The line is buffered, starting an event if none was.
+		if empty {
+			j.first = value
+		}
+	case j.multiline.config.MatchWhichLine == Previous:
+		// The line starts a new event, ending the one buffered.
+		j.first = value
+		if text != "" {
+			j.emit(text, first)
+		}
+	default:
+		// The line ends the event buffered, or is an event on its own.
+		j.first = nil
+		if empty {
+			first = value
+		}
+		j.emit(text, first)
+	}
+
+	if j.buffer.Len() == 0 {
+		if j.timer != nil {
+			j.timer.Stop()
+		}
+		return
+	}
+	if j.timer == nil {
+		j.timer = time.AfterFunc(j.multiline.config.Timeout.Duration, j.timeout)
+	} else {
+		j.timer.Reset(j.multiline.config.Timeout.Duration)
+	}
+}
+
+// OnTimeout sets a function called after an event is emitted on timeout,
+// without holding the lock of the joiner, so that the joiners of sources
+// which stopped sending can be removed.  It must be set before adding lines.
+func (j *Joiner) OnTimeout(f func()) {
+	j.onTimeout = f
+}
+
+// Idle tells if no event is buffered.
+func (j *Joiner) Idle() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.buffer.Len() == 0
+}
+
+func (j *Joiner) timeout() {
+	j.Flush()
+	if j.onTimeout != nil {
+		j.onTimeout()
+	}
+}
+
+// Flush emits the event buffered, if any.
+func (j *Joiner) Flush() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if text := j.multiline.Flush(&j.buffer); text != "" {
+		j.emit(text, j.first)
+	}
+	j.first = nil
+}
+
+// Stop emits the event buffered, and stops the timeout.  Lines must not be
+// added after the joiner is stopped.
+func (j *Joiner) Stop() {
+	j.Flush()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.timer != nil {
+		j.timer.Stop()
+	}
+}
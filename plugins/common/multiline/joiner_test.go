@@ -0,0 +1,137 @@
+package multiline
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/internal"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	text  string
+	value interface{}
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	events []event
+}
+
+func (r *recorder) emit(text string, value interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event{text, value})
+}
+
+func (r *recorder) get() []event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]event(nil), r.events...)
+}
+
+func TestJoinerPrevious(t *testing.T) {
+	c := &Config{
+		Pattern:        `^\s`,
+		MatchWhichLine: Previous,
+	}
+	m, err := c.NewMultiline()
+	require.NoError(t, err)
+
+	var r recorder
+	j := m.NewJoiner(r.emit)
+	j.Add("Exception in thread main", 1)
+	j.Add("\tat Foo.bar", 2)
+	j.Add("\tat Foo.main", 3)
+	j.Add("done", 4)
+	require.Equal(t, []event{{"Exception in thread main\tat Foo.bar\tat Foo.main", 1}}, r.get())
+
+	j.Stop()
+	require.Equal(t, []event{
+		{"Exception in thread main\tat Foo.bar\tat Foo.main", 1},
+		{"done", 4},
+	}, r.get())
+}
+
+func TestJoinerNext(t *testing.T) {
+	c := &Config{
+		Pattern:        `\\$`,
+		MatchWhichLine: Next,
+	}
+	m, err := c.NewMultiline()
+	require.NoError(t, err)
+
+	var r recorder
+	j := m.NewJoiner(r.emit)
+	j.Add("one", 1)
+	j.Add(`two \`, 2)
+	j.Add("three", 3)
+	j.Stop()
+	require.Equal(t, []event{
+		{"one", 1},
+		{`two \three`, 2},
+	}, r.get())
+}
+
+func TestJoinerTimeout(t *testing.T) {
+	c := &Config{
+		Pattern:        `^\s`,
+		MatchWhichLine: Previous,
+		Timeout:        &internal.Duration{Duration: 10 * time.Millisecond},
+	}
+	m, err := c.NewMultiline()
+	require.NoError(t, err)
+
+	var r recorder
+	j := m.NewJoiner(r.emit)
+	defer j.Stop()
+	j.Add("first", 1)
+	j.Add(" second", 2)
+
+	require.Eventually(t, func() bool {
+		return len(r.get()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []event{{"first second", 1}}, r.get())
+}
+
+func TestJoinerOnTimeout(t *testing.T) {
+	c := &Config{
+		Pattern:        `^\s`,
+		MatchWhichLine: Previous,
+		Timeout:        &internal.Duration{Duration: 10 * time.Millisecond},
+	}
+	m, err := c.NewMultiline()
+	require.NoError(t, err)
+
+	var r recorder
+	j := m.NewJoiner(r.emit)
+	defer j.Stop()
+	timedOut := make(chan bool, 1)
+	j.OnTimeout(func() {
+		timedOut <- j.Idle()
+	})
+	require.True(t, j.Idle())
+	j.Add("first", 1)
+	require.False(t, j.Idle())
+
+	select {
+	case idle := <-timedOut:
+		require.True(t, idle)
+	case <-time.After(time.Second):
+		require.FailNow(t, "timeout not called")
+	}
+	require.Equal(t, []event{{"first", 1}}, r.get())
+}
+
+func TestJoinerDisabled(t *testing.T) {
+	c := &Config{}
+	m, err := c.NewMultiline()
+	require.NoError(t, err)
+
+	var r recorder
+	j := m.NewJoiner(r.emit)
+	j.Add("first", 1)
+	j.Add(" second", 2)
+	require.Equal(t, []event{{"first", 1}, {" second", 2}}, r.get())
+}
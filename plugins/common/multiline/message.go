@@ -0,0 +1,92 @@
+package multiline
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/parsers"
+)
+
+// sampleConfig is the sample configuration of the options of the multiline
+// table of the inputs joining lines with a Joiner.
+const sampleConfig = `
+  #   ## Regular expression of the lines belonging to another line.
+  #   pattern = "^\\s"
+  #
+  #   ## Whether the matching lines belong to the "previous" or the "next"
+  #   ## line.
+  #   # match_which_line = "previous"
+  #
+  #   ## Whether the lines not matching the pattern belong to another line.
+  #   # invert_match = false
+  #
+  #   ## Time after which a multiline event is emitted if no other line
+  #   ## follows.
+  #   # timeout = "5s"
+`
+
+// SampleConfig returns the commented sample configuration of the multiline
+// table of a plugin, such as "inputs.syslog".
+func SampleConfig(plugin string) string {
+	return "  # [" + plugin + ".multiline]" + sampleConfig
+}
+
+// MessageParser parses log messages, such as the multiline events joined, in
+// a data format, and merges the fields and tags parsed into the ones of the
+// metric of the message.
+type MessageParser struct {
+	parser parsers.Parser
+	log    telegraf.Logger
+
+	// Parsers are not safe for concurrent use.
+	mu sync.Mutex
+}
+
+// NewMessageParser returns a parser of the messages in a format, one of
+// "json" or "logfmt", or nil if the format is empty.
+func NewMessageParser(format string, log telegraf.Logger) (*MessageParser, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "json", "logfmt":
+	default:
+		return nil, fmt.Errorf("unsupported message format %q", format)
+	}
+
+	parser, err := parsers.NewParser(&parsers.Config{
+		DataFormat:       format,
+		MetricName:       "message",
+		JSONStrict:       true,
+		JSONStringFields: []string{"*"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating message parser: %w", err)
+	}
+	return &MessageParser{parser: parser, log: log}, nil
+}
+
+// Parse adds the fields and tags parsed from a message, which don't replace
+// the ones already set.  Messages which can't be parsed are left as is.
+func (p *MessageParser) Parse(message string, fields map[string]interface{}, tags map[string]string) {
+	p.mu.Lock()
+	metrics, err := p.parser.Parse([]byte(message))
+	p.mu.Unlock()
+	if err != nil {
+		p.log.Debugf("Unable to parse message %q: %v", message, err)
+		return
+	}
+
+	for _, m := range metrics {
+		for _, tag := range m.TagList() {
+			if _, ok := tags[tag.Key]; !ok {
+				tags[tag.Key] = tag.Value
+			}
+		}
+		for _, field := range m.FieldList() {
+			if _, ok := fields[field.Key]; !ok {
+				fields[field.Key] = field.Value
+			}
+		}
+	}
+}
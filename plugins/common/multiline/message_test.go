@@ -0,0 +1,49 @@
+package multiline
+
+import (
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageParserNoFormat(t *testing.T) {
+	p, err := NewMessageParser("", testutil.Logger{})
+	require.NoError(t, err)
+	require.Nil(t, p)
+}
+
+func TestNewMessageParserUnsupportedFormat(t *testing.T) {
+	_, err := NewMessageParser("influx", testutil.Logger{})
+	require.Error(t, err)
+}
+
+func TestMessageParserParse(t *testing.T) {
+	p, err := NewMessageParser("logfmt", testutil.Logger{})
+	require.NoError(t, err)
+
+	message := `level=error msg="disk full" host=other`
+	fields := map[string]interface{}{"message": message, "host": "server"}
+	tags := map[string]string{"source": "app"}
+	p.Parse(message, fields, tags)
+
+	require.Equal(t, map[string]interface{}{
+		"message": message,
+		"host":    "server",
+		"level":   "error",
+		"msg":     "disk full",
+	}, fields)
+	require.Equal(t, map[string]string{"source": "app"}, tags)
+}
+
+func TestMessageParserInvalid(t *testing.T) {
+	p, err := NewMessageParser("json", testutil.Logger{})
+	require.NoError(t, err)
+
+	fields := map[string]interface{}{"message": "not json"}
+	tags := map[string]string{}
+	p.Parse("not json", fields, tags)
+
+	require.Equal(t, map[string]interface{}{"message": "not json"}, fields)
+	require.Empty(t, tags)
+}
@@ -1,4 +1,4 @@
-package tail
+package multiline
 
 import (
 	"bytes"
@@ -11,29 +11,29 @@ import (
 )
 
 // Indicates relation to the multiline event: previous or next
-type MultilineMatchWhichLine int
+type MatchWhichLine int
 
 type Multiline struct {
-	config        *MultilineConfig
+	config        *Config
 	enabled       bool
 	patternRegexp *regexp.Regexp
 }
 
-type MultilineConfig struct {
+type Config struct {
 	Pattern        string
-	MatchWhichLine MultilineMatchWhichLine `toml:"match_which_line"`
+	MatchWhichLine MatchWhichLine `toml:"match_which_line"`
 	InvertMatch    bool
 	Timeout        *internal.Duration
 }
 
 const (
 	// Previous => Append current line to previous line
-	Previous MultilineMatchWhichLine = iota
+	Previous MatchWhichLine = iota
 	// Next => Next line will be appended to current line
 	Next
 )
 
-func (m *MultilineConfig) NewMultiline() (*Multiline, error) {
+func (m *Config) NewMultiline() (*Multiline, error) {
 	enabled := false
 	var r *regexp.Regexp
 	var err error
@@ -94,7 +94,7 @@ func (m *Multiline) matchString(text string) bool {
 	return m.patternRegexp.MatchString(text) != m.config.InvertMatch
 }
 
-func (w MultilineMatchWhichLine) String() string {
+func (w MatchWhichLine) String() string {
 	switch w {
 	case Previous:
 		return "previous"
@@ -104,13 +104,13 @@ func (w MultilineMatchWhichLine) String() string {
 	return ""
 }
 
-// UnmarshalTOML implements ability to unmarshal MultilineMatchWhichLine from TOML files.
-func (w *MultilineMatchWhichLine) UnmarshalTOML(data []byte) (err error) {
+// UnmarshalTOML implements ability to unmarshal MatchWhichLine from TOML files.
+func (w *MatchWhichLine) UnmarshalTOML(data []byte) (err error) {
 	return w.UnmarshalText(data)
 }
 
 // UnmarshalText implements encoding.TextUnmarshaler
-func (w *MultilineMatchWhichLine) UnmarshalText(data []byte) (err error) {
+func (w *MatchWhichLine) UnmarshalText(data []byte) (err error) {
 	s := string(data)
 	switch strings.ToUpper(s) {
 	case `PREVIOUS`, `"PREVIOUS"`, `'PREVIOUS'`:
@@ -122,14 +122,14 @@ func (w *MultilineMatchWhichLine) UnmarshalText(data []byte) (err error) {
 		return
 	}
 	*w = -1
-	return fmt.Errorf("E! [inputs.tail] unknown multiline MatchWhichLine")
+	return fmt.Errorf("unknown multiline MatchWhichLine")
 }
 
 // MarshalText implements encoding.TextMarshaler
-func (w MultilineMatchWhichLine) MarshalText() ([]byte, error) {
+func (w MatchWhichLine) MarshalText() ([]byte, error) {
 	s := w.String()
 	if s != "" {
 		return []byte(s), nil
 	}
-	return nil, fmt.Errorf("E! [inputs.tail] unknown multiline MatchWhichLine")
+	return nil, fmt.Errorf("unknown multiline MatchWhichLine")
 }
@@ -1,4 +1,4 @@
-package tail
+package multiline
 
 import (
 	"bytes"
@@ -9,8 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestMultilineConfigOK(t *testing.T) {
-	c := &MultilineConfig{
+func TestConfigOK(t *testing.T) {
+	c := &Config{
 		Pattern:        ".*",
 		MatchWhichLine: Previous,
 	}
@@ -20,8 +20,8 @@ func TestMultilineConfigOK(t *testing.T) {
 	assert.NoError(t, err, "Configuration was OK.")
 }
 
-func TestMultilineConfigError(t *testing.T) {
-	c := &MultilineConfig{
+func TestConfigError(t *testing.T) {
+	c := &Config{
 		Pattern:        "\xA0",
 		MatchWhichLine: Previous,
 	}
@@ -31,9 +31,9 @@ func TestMultilineConfigError(t *testing.T) {
 	assert.Error(t, err, "The pattern was invalid")
 }
 
-func TestMultilineConfigTimeoutSpecified(t *testing.T) {
+func TestConfigTimeoutSpecified(t *testing.T) {
 	duration, _ := time.ParseDuration("10s")
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        ".*",
 		MatchWhichLine: Previous,
 		Timeout:        &internal.Duration{Duration: duration},
@@ -44,9 +44,9 @@ func TestMultilineConfigTimeoutSpecified(t *testing.T) {
 	assert.Equal(t, duration, m.config.Timeout.Duration)
 }
 
-func TestMultilineConfigDefaultTimeout(t *testing.T) {
+func TestConfigDefaultTimeout(t *testing.T) {
 	duration, _ := time.ParseDuration("5s")
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        ".*",
 		MatchWhichLine: Previous,
 	}
@@ -57,7 +57,7 @@ func TestMultilineConfigDefaultTimeout(t *testing.T) {
 }
 
 func TestMultilineIsEnabled(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        ".*",
 		MatchWhichLine: Previous,
 	}
@@ -70,7 +70,7 @@ func TestMultilineIsEnabled(t *testing.T) {
 }
 
 func TestMultilineIsDisabled(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		MatchWhichLine: Previous,
 	}
 	m, err := c.NewMultiline()
@@ -82,7 +82,7 @@ func TestMultilineIsDisabled(t *testing.T) {
 }
 
 func TestMultilineFlushEmpty(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        "^=>",
 		MatchWhichLine: Previous,
 	}
@@ -96,7 +96,7 @@ func TestMultilineFlushEmpty(t *testing.T) {
 }
 
 func TestMultilineFlush(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        "^=>",
 		MatchWhichLine: Previous,
 	}
@@ -112,7 +112,7 @@ func TestMultilineFlush(t *testing.T) {
 }
 
 func TestMultiLineProcessLinePrevious(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        "^=>",
 		MatchWhichLine: Previous,
 	}
@@ -142,7 +142,7 @@ func TestMultiLineProcessLinePrevious(t *testing.T) {
 }
 
 func TestMultiLineProcessLineNext(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        "=>$",
 		MatchWhichLine: Next,
 	}
@@ -172,7 +172,7 @@ func TestMultiLineProcessLineNext(t *testing.T) {
 }
 
 func TestMultiLineMatchStringWithInvertMatchFalse(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        "=>$",
 		MatchWhichLine: Next,
 		InvertMatch:    false,
@@ -188,7 +188,7 @@ func TestMultiLineMatchStringWithInvertMatchFalse(t *testing.T) {
 }
 
 func TestMultiLineMatchStringWithInvertTrue(t *testing.T) {
-	c := &MultilineConfig{
+	c := &Config{
 		Pattern:        "=>$",
 		MatchWhichLine: Next,
 		InvertMatch:    true,
@@ -204,32 +204,32 @@ func TestMultiLineMatchStringWithInvertTrue(t *testing.T) {
 }
 
 func TestMultilineWhat(t *testing.T) {
-	var w1 MultilineMatchWhichLine
+	var w1 MatchWhichLine
 	w1.UnmarshalTOML([]byte(`"previous"`))
 	assert.Equal(t, Previous, w1)
 
-	var w2 MultilineMatchWhichLine
+	var w2 MatchWhichLine
 	w2.UnmarshalTOML([]byte(`previous`))
 	assert.Equal(t, Previous, w2)
 
-	var w3 MultilineMatchWhichLine
+	var w3 MatchWhichLine
 	w3.UnmarshalTOML([]byte(`'previous'`))
 	assert.Equal(t, Previous, w3)
 
-	var w4 MultilineMatchWhichLine
+	var w4 MatchWhichLine
 	w4.UnmarshalTOML([]byte(`"next"`))
 	assert.Equal(t, Next, w4)
 
-	var w5 MultilineMatchWhichLine
+	var w5 MatchWhichLine
 	w5.UnmarshalTOML([]byte(`next`))
 	assert.Equal(t, Next, w5)
 
-	var w6 MultilineMatchWhichLine
+	var w6 MatchWhichLine
 	w6.UnmarshalTOML([]byte(`'next'`))
 	assert.Equal(t, Next, w6)
 
-	var w7 MultilineMatchWhichLine
+	var w7 MatchWhichLine
 	err := w7.UnmarshalTOML([]byte(`nope`))
-	assert.Equal(t, MultilineMatchWhichLine(-1), w7)
+	assert.Equal(t, MatchWhichLine(-1), w7)
 	assert.Error(t, err)
 }
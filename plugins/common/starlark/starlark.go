@@ -0,0 +1,121 @@
+// Package starlark contains the parts of the Starlark plugins shared by the
+// processor and the aggregator: the metric wrappers, builtins and loading of
+// the script.
+package starlark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkjson"
+)
+
+// Builtins returns the builtin values available to the scripts, in addition
+// to the Starlark universe.
+func Builtins() starlark.StringDict {
+	return starlark.StringDict{
+		"Metric":   starlark.NewBuiltin("Metric", newMetric),
+		"deepcopy": starlark.NewBuiltin("deepcopy", deepcopy),
+		"catch":    starlark.NewBuiltin("catch", catch),
+	}
+}
+
+// NewThread returns a thread for running a script that prints to the debug
+// log and can load the json.star and logging.star modules.
+func NewThread(logger telegraf.Logger) *starlark.Thread {
+	return &starlark.Thread{
+		Print: func(_ *starlark.Thread, msg string) { logger.Debug(msg) },
+		Load: func(thread *starlark.Thread, module string) (starlark.StringDict, error) {
+			return loadFunc(thread, module, logger)
+		},
+	}
+}
+
+// LoadScript executes the script set in source, or read from the file
+// script, with the builtins predeclared, and returns its globals.  Exactly one
+// of source and script must be set; name is used for the source in errors.
+func LoadScript(thread *starlark.Thread, name, source, script string, builtins starlark.StringDict) (starlark.StringDict, error) {
+	if source == "" && script == "" {
+		return nil, errors.New("one of source or script must be set")
+	}
+	if source != "" && script != "" {
+		return nil, errors.New("both source or script cannot be set")
+	}
+
+	var program *starlark.Program
+	var err error
+	if source != "" {
+		_, program, err = starlark.SourceProgram(name, source, builtins.Has)
+	} else {
+		_, program, err = starlark.SourceProgram(script, nil, builtins.Has)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return program.Init(thread, builtins)
+}
+
+// Function returns the function defined by the script as name, which must
+// take nparams parameters.
+func Function(globals starlark.StringDict, name string, nparams int) (*starlark.Function, error) {
+	value := globals[name]
+	if value == nil {
+		return nil, fmt.Errorf("%s is not defined", name)
+	}
+
+	fn, ok := value.(*starlark.Function)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a function", name)
+	}
+
+	if fn.NumParams() != nparams {
+		switch nparams {
+		case 0:
+			return nil, fmt.Errorf("%s function must take no parameters", name)
+		case 1:
+			return nil, fmt.Errorf("%s function must take one parameter", name)
+		default:
+			return nil, fmt.Errorf("%s function must take %d parameters", name, nparams)
+		}
+	}
+	return fn, nil
+}
+
+// LogError logs the backtrace of an error raised by a script.
+func LogError(logger telegraf.Logger, err error) {
+	if err, ok := err.(*starlark.EvalError); ok {
+		for _, line := range strings.Split(err.Backtrace(), "\n") {
+			logger.Error(line)
+		}
+	}
+}
+
+func init() {
+	// https://github.com/bazelbuild/starlark/issues/20
+	resolve.AllowNestedDef = true
+	resolve.AllowLambda = true
+	resolve.AllowFloat = true
+	resolve.AllowSet = true
+	resolve.AllowGlobalReassign = true
+	resolve.AllowRecursion = true
+}
+
+func loadFunc(thread *starlark.Thread, module string, logger telegraf.Logger) (starlark.StringDict, error) {
+	switch module {
+	case "json.star":
+		return starlark.StringDict{
+			"json": starlarkjson.Module,
+		}, nil
+	case "logging.star":
+		return starlark.StringDict{
+			"log": LogModule(logger),
+		}, nil
+	default:
+		return nil, errors.New("module " + module + " is not available")
+	}
+}
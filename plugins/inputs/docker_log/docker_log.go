@@ -19,6 +19,7 @@ import (
 	"github.com/influxdata/telegraf/filter"
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/docker"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
@@ -60,6 +61,14 @@ var sampleConfig = `
   # tls_key = "/etc/telegraf/key.pem"
   ## Use TLS but skip chain & host verification
   # insecure_skip_verify = false
+
+  ## Data format of the log lines, parsed into fields and tags added to the
+  ## metrics; one of "json" or "logfmt".  Lines which can't be parsed are kept
+  ## as is.  By default the lines are not parsed.
+  # message_format = ""
+
+  ## Join the lines of multiline events, such as stack traces, of each stream
+  ## of a container.  The pattern is used like in the tail input.
 `
 
 const (
@@ -88,9 +97,14 @@ type DockerLogs struct {
 	ContainerStateInclude []string          `toml:"container_state_include"`
 	ContainerStateExclude []string          `toml:"container_state_exclude"`
 	IncludeSourceTag      bool              `toml:"source_tag"`
+	MessageFormat         string            `toml:"message_format"`
+
+	Multiline multiline.Config `toml:"multiline"`
 
 	tlsint.ClientConfig
 
+	Log telegraf.Logger `toml:"-"`
+
 	newEnvClient func() (Client, error)
 	newClient    func(string, *tls.Config) (Client, error)
 
@@ -102,6 +116,9 @@ type DockerLogs struct {
 	wg              sync.WaitGroup
 	mu              sync.Mutex
 	containerList   map[string]context.CancelFunc
+
+	multiline *multiline.Multiline
+	parser    *multiline.MessageParser
 }
 
 func (d *DockerLogs) Description() string {
@@ -109,7 +126,7 @@ func (d *DockerLogs) Description() string {
 }
 
 func (d *DockerLogs) SampleConfig() string {
-	return sampleConfig
+	return sampleConfig + multiline.SampleConfig("inputs.docker_log")
 }
 
 func (d *DockerLogs) Init() error {
@@ -130,6 +147,16 @@ func (d *DockerLogs) Init() error {
 		}
 	}
 
+	d.multiline, err = d.Multiline.NewMultiline()
+	if err != nil {
+		return fmt.Errorf("compiling multiline pattern: %w", err)
+	}
+
+	d.parser, err = multiline.NewMessageParser(d.MessageFormat, d.Log)
+	if err != nil {
+		return err
+	}
+
 	// Create filters
 	err = d.createLabelFilters()
 	if err != nil {
@@ -307,9 +334,9 @@ func (d *DockerLogs) tailContainerLogs(
 	// If the container is *not* using a TTY, streams for stdout and stderr are
 	// multiplexed.
 	if hasTTY {
-		return tailStream(acc, tags, container.ID, logReader, "tty")
+		return d.tailStream(acc, tags, container.ID, logReader, "tty")
 	} else {
-		return tailMultiplexed(acc, tags, container.ID, logReader)
+		return d.tailMultiplexed(acc, tags, container.ID, logReader)
 	}
 }
 
@@ -337,7 +364,7 @@ func parseLine(line []byte) (time.Time, string, error) {
 	return ts, string(message), nil
 }
 
-func tailStream(
+func (d *DockerLogs) tailStream(
 	acc telegraf.Accumulator,
 	baseTags map[string]string,
 	containerID string,
@@ -352,6 +379,25 @@ func tailStream(
 	}
 	tags["stream"] = stream
 
+	// The lines of multiline events are added with the timestamp of their
+	// first line.
+	joiner := d.multiline.NewJoiner(func(message string, ts interface{}) {
+		fields := map[string]interface{}{
+			"container_id": containerID,
+			"message":      message,
+		}
+		mtags := tags
+		if d.parser != nil {
+			mtags = make(map[string]string, len(tags))
+			for k, v := range tags {
+				mtags[k] = v
+			}
+			d.parser.Parse(message, fields, mtags)
+		}
+		acc.AddFields("docker_log", fields, mtags, ts.(time.Time))
+	})
+	defer joiner.Stop()
+
 	r := bufio.NewReaderSize(reader, 64*1024)
 
 	for {
@@ -362,10 +408,7 @@ func tailStream(
 			if err != nil {
 				acc.AddError(err)
 			} else {
-				acc.AddFields("docker_log", map[string]interface{}{
-					"container_id": containerID,
-					"message":      message,
-				}, tags, ts)
+				joiner.Add(message, ts)
 			}
 		}
 
@@ -378,7 +421,7 @@ func tailStream(
 	}
 }
 
-func tailMultiplexed(
+func (d *DockerLogs) tailMultiplexed(
 	acc telegraf.Accumulator,
 	tags map[string]string,
 	containerID string,
@@ -391,7 +434,7 @@ func tailMultiplexed(
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := tailStream(acc, tags, containerID, outReader, "stdout")
+		err := d.tailStream(acc, tags, containerID, outReader, "stdout")
 		if err != nil {
 			acc.AddError(err)
 		}
@@ -400,7 +443,7 @@ func tailMultiplexed(
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		err := tailStream(acc, tags, containerID, errReader, "stderr")
+		err := d.tailStream(acc, tags, containerID, errReader, "stderr")
 		if err != nil {
 			acc.AddError(err)
 		}
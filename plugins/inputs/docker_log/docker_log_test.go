@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -63,9 +64,11 @@ func MustParse(layout, value string) time.Time {
 
 func Test(t *testing.T) {
 	tests := []struct {
-		name     string
-		client   *MockClient
-		expected []telegraf.Metric
+		name          string
+		client        *MockClient
+		multiline     multiline.Config
+		messageFormat string
+		expected      []telegraf.Metric
 	}{
 		{
 			name: "no containers",
@@ -160,6 +163,74 @@ func Test(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "multiline message format",
+			client: &MockClient{
+				ContainerListF: func(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+					return []types.Container{
+						{
+							ID:    "deadbeef",
+							Names: []string{"/telegraf"},
+							Image: "influxdata/telegraf:1.11.0",
+						},
+					}, nil
+				},
+				ContainerInspectF: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+					return types.ContainerJSON{
+						Config: &container.Config{
+							Tty: true,
+						},
+					}, nil
+				},
+				ContainerLogsF: func(ctx context.Context, containerID string, options types.ContainerLogsOptions) (io.ReadCloser, error) {
+					return &Response{Reader: bytes.NewBuffer([]byte(
+						"2020-04-28T18:43:16.432691200Z level=error msg=failed\n" +
+							"2020-04-28T18:43:16.532691200Z \tat Foo.main\n" +
+							"2020-04-28T18:43:17.432691200Z level=info msg=done\n"))}, nil
+				},
+			},
+			multiline: multiline.Config{
+				Pattern:        `^\s`,
+				MatchWhichLine: multiline.Previous,
+			},
+			messageFormat: "logfmt",
+			expected: []telegraf.Metric{
+				testutil.MustMetric(
+					"docker_log",
+					map[string]string{
+						"container_name":    "telegraf",
+						"container_image":   "influxdata/telegraf",
+						"container_version": "1.11.0",
+						"stream":            "tty",
+						"source":            "deadbeef",
+					},
+					map[string]interface{}{
+						"container_id": "deadbeef",
+						"message":      "level=error msg=failed\tat Foo.main",
+						"level":        "error",
+						"msg":          "failed",
+					},
+					MustParse(time.RFC3339Nano, "2020-04-28T18:43:16.432691200Z"),
+				),
+				testutil.MustMetric(
+					"docker_log",
+					map[string]string{
+						"container_name":    "telegraf",
+						"container_image":   "influxdata/telegraf",
+						"container_version": "1.11.0",
+						"stream":            "tty",
+						"source":            "deadbeef",
+					},
+					map[string]interface{}{
+						"container_id": "deadbeef",
+						"message":      "level=info msg=done",
+						"level":        "info",
+						"msg":          "done",
+					},
+					MustParse(time.RFC3339Nano, "2020-04-28T18:43:17.432691200Z"),
+				),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -169,6 +240,9 @@ func Test(t *testing.T) {
 				newClient:        func(string, *tls.Config) (Client, error) { return tt.client, nil },
 				containerList:    make(map[string]context.CancelFunc),
 				IncludeSourceTag: true,
+				Multiline:        tt.multiline,
+				MessageFormat:    tt.messageFormat,
+				Log:              testutil.Logger{},
 			}
 
 			err := plugin.Init()
@@ -0,0 +1,419 @@
+package netflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+const (
+	measurement = "netflow"
+
+	v5HeaderLength     = 24
+	v5RecordLength     = 48
+	v9HeaderLength     = 20
+	ipfixHeaderLength  = 16
+	setHeaderLength    = 4
+	variableLength     = 65535
+	minDataSetID       = 256
+	v9TemplateSetID    = 0
+	v9OptionsSetID     = 1
+	ipfixTemplateSetID = 2
+	ipfixOptionsSetID  = 3
+)
+
+// templateKey identifies a template of an exporter.  Templates are only
+// unique within the source id of v9, or the observation domain of IPFIX, of
+// an exporter.
+type templateKey struct {
+	exporter string
+	domain   uint32
+	id       uint16
+}
+
+type templateField struct {
+	pen    uint32
+	id     uint16
+	length uint16
+}
+
+// template describes the records of the data sets referring to it.
+type template struct {
+	fields []templateField
+	// options is set for the templates of options records, which describe
+	// the exporter rather than flows, and are not turned into metrics.
+	options bool
+	// received is the time the template was last received.
+	received time.Time
+}
+
+// Decoder decodes the packets of NetFlow v5, v9 and IPFIX into metrics.  It
+// keeps the templates received from every exporter, and is not safe for
+// concurrent use.
+type Decoder struct {
+	// TemplateTimeout is the time after which a template that was not
+	// received again is removed; templates never expire if it is zero.
+	TemplateTimeout time.Duration
+	Log             telegraf.Logger
+
+	templates  map[templateKey]*template
+	lastExpire time.Time
+	now        func() time.Time
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{
+		templates: make(map[templateKey]*template),
+		now:       time.Now,
+	}
+}
+
+// Decode decodes a packet sent by an exporter into the metrics of its flow
+// records.
+func (d *Decoder) Decode(exporter net.IP, buf []byte) ([]telegraf.Metric, error) {
+	if len(buf) < 2 {
+		return nil, fmt.Errorf("packet too short: %d bytes", len(buf))
+	}
+
+	// Templates are expired at most ten times per timeout, so that they
+	// don't have to be checked for every packet.
+	if now := d.now(); d.TemplateTimeout > 0 && now.Sub(d.lastExpire) >= d.TemplateTimeout/10 {
+		d.expire(now)
+		d.lastExpire = now
+	}
+
+	switch version := binary.BigEndian.Uint16(buf); version {
+	case 5:
+		return d.decodeV5(exporter, buf)
+	case 9:
+		return d.decodeV9(exporter, buf)
+	case 10:
+		return d.decodeIPFIX(exporter, buf)
+	default:
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+}
+
+func (d *Decoder) decodeV5(exporter net.IP, buf []byte) ([]telegraf.Metric, error) {
+	if len(buf) < v5HeaderLength {
+		return nil, fmt.Errorf("v5 header too short: %d bytes", len(buf))
+	}
+	count := int(binary.BigEndian.Uint16(buf[2:]))
+	if len(buf) < v5HeaderLength+count*v5RecordLength {
+		return nil, fmt.Errorf("v5 packet too short for %d records: %d bytes", count, len(buf))
+	}
+	// The upper two bits are the sampling mode.
+	samplingInterval := uint64(binary.BigEndian.Uint16(buf[22:]) & 0x3fff)
+
+	now := time.Now()
+	metrics := make([]telegraf.Metric, 0, count)
+	for i := 0; i < count; i++ {
+		r := buf[v5HeaderLength+i*v5RecordLength:]
+		tags := map[string]string{
+			"agent_address":  exporter.String(),
+			"version":        "v5",
+			"src_ip":         net.IP(r[0:4]).String(),
+			"dst_ip":         net.IP(r[4:8]).String(),
+			"next_hop":       net.IP(r[8:12]).String(),
+			"input_ifindex":  strconv.FormatUint(uint64(binary.BigEndian.Uint16(r[12:])), 10),
+			"output_ifindex": strconv.FormatUint(uint64(binary.BigEndian.Uint16(r[14:])), 10),
+			"src_port":       strconv.FormatUint(uint64(binary.BigEndian.Uint16(r[32:])), 10),
+			"dst_port":       strconv.FormatUint(uint64(binary.BigEndian.Uint16(r[34:])), 10),
+			"ip_protocol":    strconv.FormatUint(uint64(r[38]), 10),
+			"src_as":         strconv.FormatUint(uint64(binary.BigEndian.Uint16(r[40:])), 10),
+			"dst_as":         strconv.FormatUint(uint64(binary.BigEndian.Uint16(r[42:])), 10),
+			"src_mask_len":   strconv.FormatUint(uint64(r[44]), 10),
+			"dst_mask_len":   strconv.FormatUint(uint64(r[45]), 10),
+		}
+		fields := map[string]interface{}{
+			"packets":           uint64(binary.BigEndian.Uint32(r[16:])),
+			"bytes":             uint64(binary.BigEndian.Uint32(r[20:])),
+			"first_switched":    uint64(binary.BigEndian.Uint32(r[24:])),
+			"last_switched":     uint64(binary.BigEndian.Uint32(r[28:])),
+			"tcp_flags":         uint64(r[37]),
+			"sampling_interval": samplingInterval,
+		}
+		setTOS(fields, ipTOS(r[39]))
+
+		m, err := metric.New(measurement, tags, fields, now)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+func (d *Decoder) decodeV9(exporter net.IP, buf []byte) ([]telegraf.Metric, error) {
+	if len(buf) < v9HeaderLength {
+		return nil, fmt.Errorf("v9 header too short: %d bytes", len(buf))
+	}
+	sourceID := binary.BigEndian.Uint32(buf[16:])
+	return d.decodeSets(exporter, "v9", sourceID, buf[v9HeaderLength:])
+}
+
+func (d *Decoder) decodeIPFIX(exporter net.IP, buf []byte) ([]telegraf.Metric, error) {
+	if len(buf) < ipfixHeaderLength {
+		return nil, fmt.Errorf("IPFIX header too short: %d bytes", len(buf))
+	}
+	length := int(binary.BigEndian.Uint16(buf[2:]))
+	if length < ipfixHeaderLength || length > len(buf) {
+		return nil, fmt.Errorf("invalid IPFIX message length %d for %d bytes", length, len(buf))
+	}
+	domain := binary.BigEndian.Uint32(buf[12:])
+	return d.decodeSets(exporter, "ipfix", domain, buf[ipfixHeaderLength:length])
+}
+
+// decodeSets decodes the flow sets of v9, or the sets of IPFIX, which only
+// differ by the ids of the template sets and by the encoding of the fields
+// of the templates.
+func (d *Decoder) decodeSets(exporter net.IP, version string, domain uint32, buf []byte) ([]telegraf.Metric, error) {
+	var metrics []telegraf.Metric
+	for len(buf) >= setHeaderLength {
+		id := binary.BigEndian.Uint16(buf)
+		length := int(binary.BigEndian.Uint16(buf[2:]))
+		if length < setHeaderLength || length > len(buf) {
+			return nil, fmt.Errorf("invalid length %d of set %d", length, id)
+		}
+		set := buf[setHeaderLength:length]
+		buf = buf[length:]
+
+		var err error
+		switch {
+		case version == "v9" && id == v9TemplateSetID:
+			err = d.decodeV9Templates(exporter, domain, set, false)
+		case version == "v9" && id == v9OptionsSetID:
+			err = d.decodeV9Templates(exporter, domain, set, true)
+		case version == "ipfix" && id == ipfixTemplateSetID:
+			err = d.decodeIPFIXTemplates(exporter, domain, set, false)
+		case version == "ipfix" && id == ipfixOptionsSetID:
+			err = d.decodeIPFIXTemplates(exporter, domain, set, true)
+		case id >= minDataSetID:
+			var ms []telegraf.Metric
+			ms, err = d.decodeDataSet(exporter, version, domain, id, set)
+			metrics = append(metrics, ms...)
+		default:
+			d.Log.Debugf("Ignoring set %d from %s", id, exporter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return metrics, nil
+}
+
+func (d *Decoder) decodeV9Templates(exporter net.IP, domain uint32, buf []byte, options bool) error {
+	for len(buf) >= 4 {
+		id := binary.BigEndian.Uint16(buf)
+		var count, start int
+		if options {
+			if len(buf) < 6 {
+				break
+			}
+			// The lengths of the scope and option fields are in bytes.
+			scopeLength := int(binary.BigEndian.Uint16(buf[2:]))
+			optionLength := int(binary.BigEndian.Uint16(buf[4:]))
+			count = (scopeLength + optionLength) / 4
+			start = 6
+		} else {
+			count = int(binary.BigEndian.Uint16(buf[2:]))
+			start = 4
+		}
+		// The templates may be followed by padding, of no valid id.
+		if id < minDataSetID {
+			break
+		}
+
+		end := start + count*4
+		if end > len(buf) {
+			return fmt.Errorf("template %d too short for %d fields", id, count)
+		}
+		t := &template{options: options, received: d.now()}
+		for i := start; i < end; i += 4 {
+			t.fields = append(t.fields, templateField{
+				id:     binary.BigEndian.Uint16(buf[i:]),
+				length: binary.BigEndian.Uint16(buf[i+2:]),
+			})
+		}
+		d.templates[templateKey{exporter.String(), domain, id}] = t
+		buf = buf[end:]
+	}
+	return nil
+}
+
+func (d *Decoder) decodeIPFIXTemplates(exporter net.IP, domain uint32, buf []byte, options bool) error {
+	for len(buf) >= 4 {
+		id := binary.BigEndian.Uint16(buf)
+		count := int(binary.BigEndian.Uint16(buf[2:]))
+		// The templates may be followed by padding, of no valid id.
+		if id < minDataSetID {
+			break
+		}
+		key := templateKey{exporter.String(), domain, id}
+
+		// A template without fields withdraws it.
+		if count == 0 {
+			delete(d.templates, key)
+			buf = buf[4:]
+			continue
+		}
+
+		i := 4
+		if options {
+			// The number of scope fields is not needed to skip the
+			// options records.
+			i = 6
+		}
+		t := &template{options: options, received: d.now()}
+		for n := 0; n < count; n++ {
+			if i+4 > len(buf) {
+				return fmt.Errorf("template %d too short for %d fields", id, count)
+			}
+			f := templateField{
+				id:     binary.BigEndian.Uint16(buf[i:]),
+				length: binary.BigEndian.Uint16(buf[i+2:]),
+			}
+			i += 4
+			// The enterprise bit tells that the field is followed by
+			// the enterprise number.
+			if f.id&0x8000 != 0 {
+				if i+4 > len(buf) {
+					return fmt.Errorf("template %d too short for %d fields", id, count)
+				}
+				f.id &= 0x7fff
+				f.pen = binary.BigEndian.Uint32(buf[i:])
+				i += 4
+			}
+			t.fields = append(t.fields, f)
+		}
+		d.templates[key] = t
+		buf = buf[i:]
+	}
+	return nil
+}
+
+// expire removes the templates not received within the template timeout,
+// such as those of exporters which are gone.
+func (d *Decoder) expire(now time.Time) {
+	for key, t := range d.templates {
+		if now.Sub(t.received) > d.TemplateTimeout {
+			d.Log.Debugf("Removing template %d from %s in domain %d not received for %s",
+				key.id, key.exporter, key.domain, d.TemplateTimeout)
+			delete(d.templates, key)
+		}
+	}
+}
+
+func (d *Decoder) decodeDataSet(exporter net.IP, version string, domain uint32, id uint16, buf []byte) ([]telegraf.Metric, error) {
+	t, ok := d.templates[templateKey{exporter.String(), domain, id}]
+	if !ok {
+		// Exporters send their templates periodically, the records are
+		// decoded once the template is known.
+		d.Log.Debugf("Dropping data set %d from %s in domain %d without template", id, exporter, domain)
+		return nil, nil
+	}
+
+	now := time.Now()
+	var metrics []telegraf.Metric
+	for len(buf) > 0 {
+		values, n, ok := t.split(buf)
+		// The rest of the set too short for a record is padding.
+		if !ok {
+			break
+		}
+		buf = buf[n:]
+		if t.options {
+			continue
+		}
+
+		tags := map[string]string{
+			"agent_address": exporter.String(),
+			"version":       version,
+		}
+		fields := make(map[string]interface{})
+		for i, f := range t.fields {
+			el, ok := elements[elementKey{f.pen, f.id}]
+			if !ok {
+				continue
+			}
+			v, ok := el.decode(values[i])
+			if !ok {
+				continue
+			}
+			switch v := v.(type) {
+			case icmpTypeCode:
+				tags["icmp_type"] = strconv.FormatUint(uint64(v.typ), 10)
+				tags["icmp_code"] = strconv.FormatUint(uint64(v.code), 10)
+			case ipTOS:
+				setTOS(fields, v)
+			case string:
+				if el.tag {
+					tags[el.name] = v
+				} else {
+					fields[el.name] = v
+				}
+			default:
+				fields[el.name] = v
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		m, err := metric.New(measurement, tags, fields, now)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// split splits the values of the first record of a data set.  It returns the
+// values of the fields and the length of the record, or false if the data
+// set is too short for a record.
+func (t *template) split(buf []byte) ([][]byte, int, bool) {
+	values := make([][]byte, 0, len(t.fields))
+	i := 0
+	for _, f := range t.fields {
+		length := int(f.length)
+		// Fields of variable length are prefixed by their length on one
+		// byte, or on three bytes for lengths of 255 bytes and more.
+		if f.length == variableLength {
+			if i+1 > len(buf) {
+				return nil, 0, false
+			}
+			length = int(buf[i])
+			i++
+			if length == 255 {
+				if i+2 > len(buf) {
+					return nil, 0, false
+				}
+				length = int(binary.BigEndian.Uint16(buf[i:]))
+				i += 2
+			}
+		}
+		if i+length > len(buf) {
+			return nil, 0, false
+		}
+		values = append(values, buf[i:i+length])
+		i += length
+	}
+	// A template without fields would never consume the set.
+	if i == 0 {
+		return nil, 0, false
+	}
+	return values, i, true
+}
+
+// setTOS sets the DSCP and ECN of the type of service as fields, like the
+// sflow input does.
+func setTOS(fields map[string]interface{}, tos ipTOS) {
+	fields["ip_dscp"] = strconv.FormatUint(uint64(tos>>2), 10)
+	fields["ip_ecn"] = strconv.FormatUint(uint64(tos&0x3), 10)
+}
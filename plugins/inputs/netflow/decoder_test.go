@@ -0,0 +1,299 @@
+package netflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// pack encodes the values in network byte order.
+func pack(values ...interface{}) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+			panic(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func v9Packet(sourceID uint32, sets ...[]byte) []byte {
+	p := pack(uint16(9), uint16(len(sets)), uint32(1000), uint32(1600000000), uint32(1), sourceID)
+	return append(p, bytes.Join(sets, nil)...)
+}
+
+func ipfixPacket(domain uint32, sets ...[]byte) []byte {
+	body := bytes.Join(sets, nil)
+	p := pack(uint16(10), uint16(ipfixHeaderLength+len(body)), uint32(1600000000), uint32(1), domain)
+	return append(p, body...)
+}
+
+func flowSet(id uint16, records ...[]byte) []byte {
+	body := bytes.Join(records, nil)
+	return append(pack(id, uint16(setHeaderLength+len(body))), body...)
+}
+
+var exporter = net.ParseIP("10.0.0.1")
+
+func TestDecodeV9(t *testing.T) {
+	d := NewDecoder()
+	d.Log = testutil.Logger{}
+
+	templates := flowSet(v9TemplateSetID,
+		pack(uint16(256), uint16(7),
+			uint16(8), uint16(4), // sourceIPv4Address
+			uint16(12), uint16(4), // destinationIPv4Address
+			uint16(4), uint16(1), // protocolIdentifier
+			uint16(32), uint16(2), // icmpTypeCodeIPv4
+			uint16(1), uint16(4), // octetDeltaCount
+			uint16(2), uint16(4), // packetDeltaCount
+			uint16(999), uint16(2), // unknown
+		))
+	data := flowSet(256,
+		pack([]byte{192, 168, 0, 1}, []byte{192, 168, 0, 2}, uint8(1), []byte{8, 0}, uint32(84), uint32(1), uint16(7)),
+		pack([]byte{192, 168, 0, 2}, []byte{192, 168, 0, 1}, uint8(1), []byte{0, 0}, uint32(168), uint32(2), uint16(7)),
+		// padding
+		[]byte{0, 0, 0},
+	)
+
+	metrics, err := d.Decode(exporter, v9Packet(1, templates, data))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"netflow",
+			map[string]string{
+				"agent_address": "10.0.0.1",
+				"version":       "v9",
+				"src_ip":        "192.168.0.1",
+				"dst_ip":        "192.168.0.2",
+				"ip_protocol":   "1",
+				"icmp_type":     "8",
+				"icmp_code":     "0",
+			},
+			map[string]interface{}{
+				"bytes":   uint64(84),
+				"packets": uint64(1),
+			},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric(
+			"netflow",
+			map[string]string{
+				"agent_address": "10.0.0.1",
+				"version":       "v9",
+				"src_ip":        "192.168.0.2",
+				"dst_ip":        "192.168.0.1",
+				"ip_protocol":   "1",
+				"icmp_type":     "0",
+				"icmp_code":     "0",
+			},
+			map[string]interface{}{
+				"bytes":   uint64(168),
+				"packets": uint64(2),
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+
+	// The templates are kept per source id.
+	metrics, err = d.Decode(exporter, v9Packet(2, data))
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+
+	metrics, err = d.Decode(exporter, v9Packet(1, data))
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+
+	// The templates are kept per exporter.
+	metrics, err = d.Decode(net.ParseIP("10.0.0.2"), v9Packet(1, data))
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+}
+
+func TestDecodeV9OptionsAndNSEL(t *testing.T) {
+	d := NewDecoder()
+	d.Log = testutil.Logger{}
+
+	options := flowSet(v9OptionsSetID,
+		pack(uint16(257), uint16(4), uint16(8),
+			uint16(1), uint16(4), // scope system
+			uint16(34), uint16(4), // samplingInterval
+			uint16(35), uint16(1), // samplingAlgorithm
+		),
+		// padding
+		[]byte{0, 0},
+	)
+	templates := flowSet(v9TemplateSetID,
+		pack(uint16(258), uint16(5),
+			uint16(8), uint16(4), // sourceIPv4Address
+			uint16(33000), uint16(12), // NF_F_INGRESS_ACL_ID
+			uint16(40000), uint16(8), // NF_F_USERNAME
+			uint16(40001), uint16(4), // NF_F_XLATE_SRC_ADDR_IPV4
+			uint16(40005), uint16(1), // NF_F_FW_EVENT
+		))
+	data := flowSet(258,
+		pack([]byte{192, 168, 0, 1}, uint32(0x1), uint32(0x2), uint32(0x3),
+			[]byte("alice\x00\x00\x00"), []byte{203, 0, 113, 1}, uint8(1)))
+	optionsData := flowSet(257, pack(uint32(1), uint32(100), uint8(2)))
+
+	metrics, err := d.Decode(exporter, v9Packet(1, options, templates, data, optionsData))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"netflow",
+			map[string]string{
+				"agent_address":   "10.0.0.1",
+				"version":         "v9",
+				"src_ip":          "192.168.0.1",
+				"ingress_acl_id":  "00000001-00000002-00000003",
+				"post_nat_src_ip": "203.0.113.1",
+				"firewall_event":  "1",
+			},
+			map[string]interface{}{
+				"username": "alice",
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+}
+
+func TestDecodeIPFIX(t *testing.T) {
+	d := NewDecoder()
+	d.Log = testutil.Logger{}
+
+	templates := flowSet(ipfixTemplateSetID,
+		pack(uint16(300), uint16(6),
+			uint16(27), uint16(16), // sourceIPv6Address
+			uint16(95), uint16(4), // applicationId
+			uint16(96), uint16(variableLength), // applicationName
+			uint16(5), uint16(1), // ipClassOfService
+			uint16(0x8000|881), uint16(4), penVMware, // tenantSourceIPv4
+			uint16(0x8000|1), uint16(4), uint32(9999), // unknown enterprise
+		))
+	data := flowSet(300,
+		pack(net.ParseIP("2001:db8::1").To16(), uint8(13), []byte{0, 0, 80},
+			uint8(4), []byte("http"), uint8(0xb9), []byte{172, 16, 0, 1}, uint32(42)),
+	)
+
+	metrics, err := d.Decode(exporter, ipfixPacket(7, templates, data))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"netflow",
+			map[string]string{
+				"agent_address":    "10.0.0.1",
+				"version":          "ipfix",
+				"src_ip":           "2001:db8::1",
+				"application_id":   "13:80",
+				"application_name": "http",
+				"tenant_src_ip":    "172.16.0.1",
+			},
+			map[string]interface{}{
+				"ip_dscp": "46",
+				"ip_ecn":  "1",
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.IgnoreTime())
+
+	// A template without fields is withdrawn.
+	withdrawal := flowSet(ipfixTemplateSetID, pack(uint16(300), uint16(0)))
+	metrics, err = d.Decode(exporter, ipfixPacket(7, withdrawal, data))
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{
+			name:   "empty",
+			packet: []byte{},
+		},
+		{
+			name:   "unsupported version",
+			packet: pack(uint16(7), uint16(0)),
+		},
+		{
+			name:   "truncated v5",
+			packet: pack(uint16(5), uint16(2), make([]byte, 20+48)),
+		},
+		{
+			name:   "invalid set length",
+			packet: v9Packet(1, pack(uint16(256), uint16(100))),
+		},
+		{
+			name:   "truncated template",
+			packet: v9Packet(1, flowSet(v9TemplateSetID, pack(uint16(256), uint16(2), uint16(8), uint16(4)))),
+		},
+		{
+			name:   "invalid IPFIX length",
+			packet: pack(uint16(10), uint16(100), uint32(0), uint32(0), uint32(0)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder()
+			d.Log = testutil.Logger{}
+			_, err := d.Decode(exporter, tt.packet)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeTemplateTimeout(t *testing.T) {
+	now := time.Unix(1000, 0)
+	d := NewDecoder()
+	d.Log = testutil.Logger{}
+	d.TemplateTimeout = time.Minute
+	d.now = func() time.Time { return now }
+
+	templates := flowSet(v9TemplateSetID,
+		pack(uint16(256), uint16(1),
+			uint16(1), uint16(4), // octetDeltaCount
+		))
+	data := flowSet(256, pack(uint32(84)))
+
+	metrics, err := d.Decode(exporter, v9Packet(1, templates, data))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+
+	// The template is kept within the timeout.
+	now = now.Add(50 * time.Second)
+	metrics, err = d.Decode(exporter, v9Packet(1, data))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+
+	// The template is removed once it was not received for the timeout.
+	now = now.Add(20 * time.Second)
+	metrics, err = d.Decode(exporter, v9Packet(1, data))
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+	require.Empty(t, d.templates)
+
+	// A template received again is kept for another timeout.
+	metrics, err = d.Decode(exporter, v9Packet(1, templates, data))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	now = now.Add(50 * time.Second)
+	_, err = d.Decode(exporter, v9Packet(1, templates))
+	require.NoError(t, err)
+	now = now.Add(50 * time.Second)
+	metrics, err = d.Decode(exporter, v9Packet(1, data))
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+}
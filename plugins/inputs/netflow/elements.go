@@ -0,0 +1,234 @@
+package netflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Private enterprise numbers of the vendors of the elements decoded.
+const (
+	penStandard uint32 = 0
+	penVMware   uint32 = 6876
+)
+
+// elementKey identifies an information element by its enterprise number and
+// its id.  The elements of NetFlow v9 have no enterprise number; the Cisco
+// specific ones are numbered above the range of the standard elements.
+type elementKey struct {
+	pen uint32
+	id  uint16
+}
+
+// element describes how the value of an information element is turned into
+// a tag or a field of a flow metric.
+type element struct {
+	name   string
+	tag    bool
+	decode func(b []byte) (interface{}, bool)
+}
+
+// icmpTypeCode is the value of the elements with both the ICMP type and code,
+// which are set as separate tags.
+type icmpTypeCode struct {
+	typ  uint8
+	code uint8
+}
+
+// ipTOS is the value of the type of service element, which is set as the
+// separate DSCP and ECN fields.
+type ipTOS uint8
+
+// elements are the information elements decoded from v9 and IPFIX records.
+// Elements missing are ignored.
+var elements = map[elementKey]element{
+	// Standard elements, as registered by IANA for IPFIX.  NetFlow v9 uses
+	// the same ids.
+	{penStandard, 1}:   {"bytes", false, decodeUint},
+	{penStandard, 2}:   {"packets", false, decodeUint},
+	{penStandard, 3}:   {"flows", false, decodeUint},
+	{penStandard, 4}:   {"ip_protocol", true, decodeUintString},
+	{penStandard, 5}:   {"ip_tos", false, decodeTOS},
+	{penStandard, 6}:   {"tcp_flags", false, decodeUint},
+	{penStandard, 7}:   {"src_port", true, decodeUintString},
+	{penStandard, 8}:   {"src_ip", true, decodeIP},
+	{penStandard, 9}:   {"src_mask_len", true, decodeUintString},
+	{penStandard, 10}:  {"input_ifindex", true, decodeUintString},
+	{penStandard, 11}:  {"dst_port", true, decodeUintString},
+	{penStandard, 12}:  {"dst_ip", true, decodeIP},
+	{penStandard, 13}:  {"dst_mask_len", true, decodeUintString},
+	{penStandard, 14}:  {"output_ifindex", true, decodeUintString},
+	{penStandard, 15}:  {"next_hop", true, decodeIP},
+	{penStandard, 16}:  {"src_as", true, decodeUintString},
+	{penStandard, 17}:  {"dst_as", true, decodeUintString},
+	{penStandard, 18}:  {"bgp_next_hop", true, decodeIP},
+	{penStandard, 21}:  {"last_switched", false, decodeUint},
+	{penStandard, 22}:  {"first_switched", false, decodeUint},
+	{penStandard, 27}:  {"src_ip", true, decodeIP},
+	{penStandard, 28}:  {"dst_ip", true, decodeIP},
+	{penStandard, 29}:  {"src_mask_len", true, decodeUintString},
+	{penStandard, 30}:  {"dst_mask_len", true, decodeUintString},
+	{penStandard, 31}:  {"ipv6_flow_label", false, decodeUint},
+	{penStandard, 32}:  {"icmp_type_code", true, decodeICMPTypeCode},
+	{penStandard, 56}:  {"src_mac", true, decodeMAC},
+	{penStandard, 58}:  {"src_vlan", true, decodeUintString},
+	{penStandard, 59}:  {"dst_vlan", true, decodeUintString},
+	{penStandard, 60}:  {"ip_version", true, decodeUintString},
+	{penStandard, 61}:  {"direction", true, decodeDirection},
+	{penStandard, 62}:  {"next_hop", true, decodeIP},
+	{penStandard, 63}:  {"bgp_next_hop", true, decodeIP},
+	{penStandard, 80}:  {"dst_mac", true, decodeMAC},
+	{penStandard, 95}:  {"application_id", true, decodeApplicationID},
+	{penStandard, 96}:  {"application_name", true, decodeString},
+	{penStandard, 136}: {"flow_end_reason", true, decodeUintString},
+	{penStandard, 139}: {"icmp_type_code", true, decodeICMPTypeCode},
+	{penStandard, 148}: {"flow_id", false, decodeUint},
+	{penStandard, 150}: {"flow_start", false, decodeSeconds},
+	{penStandard, 151}: {"flow_end", false, decodeSeconds},
+	{penStandard, 152}: {"flow_start", false, decodeUint},
+	{penStandard, 153}: {"flow_end", false, decodeUint},
+	{penStandard, 176}: {"icmp_type", true, decodeUintString},
+	{penStandard, 177}: {"icmp_code", true, decodeUintString},
+	{penStandard, 178}: {"icmp_type", true, decodeUintString},
+	{penStandard, 179}: {"icmp_code", true, decodeUintString},
+	{penStandard, 192}: {"ip_ttl", false, decodeUint},
+	{penStandard, 225}: {"post_nat_src_ip", true, decodeIP},
+	{penStandard, 226}: {"post_nat_dst_ip", true, decodeIP},
+	{penStandard, 227}: {"post_nat_src_port", true, decodeUintString},
+	{penStandard, 228}: {"post_nat_dst_port", true, decodeUintString},
+	{penStandard, 231}: {"initiator_bytes", false, decodeUint},
+	{penStandard, 232}: {"responder_bytes", false, decodeUint},
+	{penStandard, 233}: {"firewall_event", true, decodeUintString},
+	{penStandard, 234}: {"ingress_vrf", true, decodeUintString},
+	{penStandard, 235}: {"egress_vrf", true, decodeUintString},
+	{penStandard, 298}: {"initiator_packets", false, decodeUint},
+	{penStandard, 299}: {"responder_packets", false, decodeUint},
+
+	// Cisco ASA NetFlow Security Event Logging (NSEL), exported as v9.
+	{penStandard, 33000}: {"ingress_acl_id", true, decodeACLID},
+	{penStandard, 33001}: {"egress_acl_id", true, decodeACLID},
+	{penStandard, 33002}: {"firewall_ext_event", true, decodeUintString},
+	{penStandard, 40000}: {"username", false, decodeString},
+	{penStandard, 40001}: {"post_nat_src_ip", true, decodeIP},
+	{penStandard, 40002}: {"post_nat_dst_ip", true, decodeIP},
+	{penStandard, 40003}: {"post_nat_src_port", true, decodeUintString},
+	{penStandard, 40004}: {"post_nat_dst_port", true, decodeUintString},
+	{penStandard, 40005}: {"firewall_event", true, decodeUintString},
+
+	// VMware NSX tenant elements of overlay traffic, exported as IPFIX.
+	{penVMware, 880}: {"tenant_ip_protocol", true, decodeUintString},
+	{penVMware, 881}: {"tenant_src_ip", true, decodeIP},
+	{penVMware, 882}: {"tenant_dst_ip", true, decodeIP},
+	{penVMware, 883}: {"tenant_src_ip", true, decodeIP},
+	{penVMware, 884}: {"tenant_dst_ip", true, decodeIP},
+	{penVMware, 886}: {"tenant_src_port", true, decodeUintString},
+	{penVMware, 887}: {"tenant_dst_port", true, decodeUintString},
+	{penVMware, 888}: {"egress_interface_attr", true, decodeUintString},
+	{penVMware, 889}: {"vxlan_export_role", true, decodeUintString},
+	{penVMware, 890}: {"ingress_interface_attr", true, decodeUintString},
+}
+
+func decodeUint(b []byte) (interface{}, bool) {
+	if len(b) == 0 || len(b) > 8 {
+		return nil, false
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, true
+}
+
+func decodeUintString(b []byte) (interface{}, bool) {
+	v, ok := decodeUint(b)
+	if !ok {
+		return nil, false
+	}
+	return strconv.FormatUint(v.(uint64), 10), true
+}
+
+// decodeSeconds decodes a time in seconds to milliseconds, the unit of the
+// other flow times.
+func decodeSeconds(b []byte) (interface{}, bool) {
+	v, ok := decodeUint(b)
+	if !ok {
+		return nil, false
+	}
+	return v.(uint64) * 1000, true
+}
+
+func decodeIP(b []byte) (interface{}, bool) {
+	if len(b) != net.IPv4len && len(b) != net.IPv6len {
+		return nil, false
+	}
+	return net.IP(b).String(), true
+}
+
+func decodeMAC(b []byte) (interface{}, bool) {
+	if len(b) != 6 {
+		return nil, false
+	}
+	return net.HardwareAddr(b).String(), true
+}
+
+func decodeString(b []byte) (interface{}, bool) {
+	s := strings.TrimRight(string(b), "\x00")
+	if s == "" {
+		return nil, false
+	}
+	return s, true
+}
+
+func decodeDirection(b []byte) (interface{}, bool) {
+	if len(b) != 1 {
+		return nil, false
+	}
+	switch b[0] {
+	case 0:
+		return "ingress", true
+	case 1:
+		return "egress", true
+	}
+	return strconv.Itoa(int(b[0])), true
+}
+
+func decodeTOS(b []byte) (interface{}, bool) {
+	if len(b) != 1 {
+		return nil, false
+	}
+	return ipTOS(b[0]), true
+}
+
+func decodeICMPTypeCode(b []byte) (interface{}, bool) {
+	if len(b) != 2 {
+		return nil, false
+	}
+	return icmpTypeCode{typ: b[0], code: b[1]}, true
+}
+
+// decodeApplicationID decodes the application of the flow, as classified by
+// Cisco NBAR and others, into the classification engine and the selector.
+func decodeApplicationID(b []byte) (interface{}, bool) {
+	if len(b) < 2 {
+		return nil, false
+	}
+	v, ok := decodeUint(b[1:])
+	if !ok {
+		return nil, false
+	}
+	return fmt.Sprintf("%d:%d", b[0], v), true
+}
+
+// decodeACLID decodes the id of an access control entry of a Cisco ASA, made
+// of the hashes of the ACL, the ACE and the extended ACE.
+func decodeACLID(b []byte) (interface{}, bool) {
+	if len(b) != 12 {
+		return nil, false
+	}
+	return fmt.Sprintf("%08x-%08x-%08x",
+		binary.BigEndian.Uint32(b[0:4]),
+		binary.BigEndian.Uint32(b[4:8]),
+		binary.BigEndian.Uint32(b[8:12])), true
+}
@@ -0,0 +1,74 @@
+package netflow
+
+import (
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetFlow(t *testing.T) {
+	netflow := &NetFlow{
+		ServiceAddress: "udp://127.0.0.1:0",
+		Log:            testutil.Logger{},
+	}
+	err := netflow.Init()
+	require.NoError(t, err)
+
+	var acc testutil.Accumulator
+	err = netflow.Start(&acc)
+	require.NoError(t, err)
+	defer netflow.Stop()
+
+	client, err := net.Dial(netflow.Address().Network(), netflow.Address().String())
+	require.NoError(t, err)
+
+	packetBytes, err := hex.DecodeString(
+		"00050001000000645f5e100000000000000000010000400a" +
+			"c0a80001c0a8000200000000000100020000000a000003e80000001000000020" +
+			"0050c350001b06280000fde818180000")
+	require.NoError(t, err)
+	client.Write(packetBytes)
+
+	acc.Wait(1)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"netflow",
+			map[string]string{
+				"agent_address":  "127.0.0.1",
+				"version":        "v5",
+				"src_ip":         "192.168.0.1",
+				"dst_ip":         "192.168.0.2",
+				"next_hop":       "0.0.0.0",
+				"input_ifindex":  "1",
+				"output_ifindex": "2",
+				"src_port":       "80",
+				"dst_port":       "50000",
+				"ip_protocol":    "6",
+				"src_as":         "0",
+				"dst_as":         "65000",
+				"src_mask_len":   "24",
+				"dst_mask_len":   "24",
+			},
+			map[string]interface{}{
+				"packets":           uint64(10),
+				"bytes":             uint64(1000),
+				"first_switched":    uint64(16),
+				"last_switched":     uint64(32),
+				"tcp_flags":         uint64(0x1b),
+				"sampling_interval": uint64(10),
+				"ip_dscp":           "10",
+				"ip_ecn":            "0",
+			},
+			time.Unix(0, 0),
+		),
+	}
+
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(),
+		testutil.IgnoreTime())
+}
@@ -0,0 +1,237 @@
+package opentelemetry
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// libraryNameTag is the tag set to the name of the instrumentation library
+// of a metric.
+const libraryNameTag = "otel.library.name"
+
+// converter converts the metrics of an OTLP export request to telegraf
+// metrics, in the format of the prometheus input with metric_version = 1:
+// the measurement is the name of the metric, with a gauge or counter field,
+// or the count, sum and buckets or quantiles of histograms and summaries.
+type converter struct {
+	now time.Time
+}
+
+func (c *converter) resourceMetrics(rms []*metricspb.ResourceMetrics) []telegraf.Metric {
+	var metrics []telegraf.Metric
+	for _, rm := range rms {
+		resourceTags := make(map[string]string)
+		addAttributes(resourceTags, rm.GetResource().GetAttributes())
+
+		for _, ilm := range rm.GetInstrumentationLibraryMetrics() {
+			tags := resourceTags
+			if name := ilm.GetInstrumentationLibrary().GetName(); name != "" {
+				tags = copyTags(resourceTags)
+				tags[libraryNameTag] = name
+			}
+
+			for _, m := range ilm.GetMetrics() {
+				metrics = append(metrics, c.metric(m, tags)...)
+			}
+		}
+	}
+	return metrics
+}
+
+func (c *converter) metric(m *metricspb.Metric, tags map[string]string) []telegraf.Metric {
+	var metrics []telegraf.Metric
+	add := func(pointTags map[string]string, fields map[string]interface{}, ts uint64, tp telegraf.ValueType) {
+		if len(fields) == 0 {
+			return
+		}
+		metric, err := metric.New(m.GetName(), pointTags, fields, c.time(ts), tp)
+		if err == nil {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	switch data := m.GetData().(type) {
+	case *metricspb.Metric_Gauge:
+		for _, dp := range data.Gauge.GetDataPoints() {
+			add(c.tags(tags, dp.GetAttributes(), dp.GetLabels()),
+				numberFields("gauge", dp), dp.GetTimeUnixNano(), telegraf.Gauge)
+		}
+	case *metricspb.Metric_Sum:
+		field, tp := sumType(data.Sum.GetIsMonotonic(), data.Sum.GetAggregationTemporality())
+		for _, dp := range data.Sum.GetDataPoints() {
+			add(c.tags(tags, dp.GetAttributes(), dp.GetLabels()),
+				numberFields(field, dp), dp.GetTimeUnixNano(), tp)
+		}
+	case *metricspb.Metric_Histogram:
+		for _, dp := range data.Histogram.GetDataPoints() {
+			add(c.tags(tags, dp.GetAttributes(), dp.GetLabels()),
+				histogramFields(dp.GetCount(), dp.GetSum(), dp.GetBucketCounts(), dp.GetExplicitBounds()),
+				dp.GetTimeUnixNano(), telegraf.Histogram)
+		}
+	case *metricspb.Metric_Summary:
+		for _, dp := range data.Summary.GetDataPoints() {
+			fields := map[string]interface{}{
+				"count": float64(dp.GetCount()),
+				"sum":   dp.GetSum(),
+			}
+			for _, q := range dp.GetQuantileValues() {
+				if !math.IsNaN(q.GetValue()) {
+					fields[fmt.Sprint(q.GetQuantile())] = q.GetValue()
+				}
+			}
+			add(c.tags(tags, dp.GetAttributes(), dp.GetLabels()),
+				fields, dp.GetTimeUnixNano(), telegraf.Summary)
+		}
+
+	// The integer types are deprecated, but still sent by older SDKs.
+	case *metricspb.Metric_IntGauge:
+		for _, dp := range data.IntGauge.GetDataPoints() {
+			add(c.tags(tags, nil, dp.GetLabels()),
+				map[string]interface{}{"gauge": dp.GetValue()}, dp.GetTimeUnixNano(), telegraf.Gauge)
+		}
+	case *metricspb.Metric_IntSum:
+		field, tp := sumType(data.IntSum.GetIsMonotonic(), data.IntSum.GetAggregationTemporality())
+		for _, dp := range data.IntSum.GetDataPoints() {
+			add(c.tags(tags, nil, dp.GetLabels()),
+				map[string]interface{}{field: dp.GetValue()}, dp.GetTimeUnixNano(), tp)
+		}
+	case *metricspb.Metric_IntHistogram:
+		for _, dp := range data.IntHistogram.GetDataPoints() {
+			add(c.tags(tags, nil, dp.GetLabels()),
+				histogramFields(dp.GetCount(), float64(dp.GetSum()), dp.GetBucketCounts(), dp.GetExplicitBounds()),
+				dp.GetTimeUnixNano(), telegraf.Histogram)
+		}
+	}
+	return metrics
+}
+
+func (c *converter) time(ts uint64) time.Time {
+	if ts == 0 {
+		return c.now
+	}
+	return time.Unix(0, int64(ts))
+}
+
+// tags returns the tags of a data point: the tags of its resource and
+// library, and its attributes and deprecated labels.
+func (c *converter) tags(tags map[string]string, attributes []*commonpb.KeyValue, labels []*commonpb.StringKeyValue) map[string]string {
+	if len(attributes) == 0 && len(labels) == 0 {
+		return tags
+	}
+	tags = copyTags(tags)
+	for _, l := range labels {
+		tags[l.GetKey()] = l.GetValue()
+	}
+	addAttributes(tags, attributes)
+	return tags
+}
+
+// sumType returns the field and type of the points of a sum.  The points of
+// delta monotonic sums are the increase since the previous point rather than
+// a running total, so that they are gauges with a delta field.
+func sumType(monotonic bool, temporality metricspb.AggregationTemporality) (string, telegraf.ValueType) {
+	switch {
+	case !monotonic:
+		return "gauge", telegraf.Gauge
+	case temporality == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+		return "delta", telegraf.Gauge
+	default:
+		return "counter", telegraf.Counter
+	}
+}
+
+func numberFields(field string, dp *metricspb.NumberDataPoint) map[string]interface{} {
+	switch v := dp.GetValue().(type) {
+	case *metricspb.NumberDataPoint_AsInt:
+		return map[string]interface{}{field: v.AsInt}
+	case *metricspb.NumberDataPoint_AsDouble:
+		if math.IsNaN(v.AsDouble) {
+			return nil
+		}
+		return map[string]interface{}{field: v.AsDouble}
+	default:
+		return nil
+	}
+}
+
+// histogramFields returns the count, sum and cumulative count of each bucket
+// of a histogram, with the upper bound of the bucket as the field.  OTLP
+// buckets are not cumulative, and the last bucket has no upper bound.
+func histogramFields(count uint64, sum float64, bucketCounts []uint64, bounds []float64) map[string]interface{} {
+	fields := map[string]interface{}{
+		"count": float64(count),
+		"sum":   sum,
+	}
+
+	var cumulative uint64
+	for i, n := range bucketCounts {
+		cumulative += n
+		bound := math.Inf(1)
+		if i < len(bounds) {
+			bound = bounds[i]
+		}
+		fields[fmt.Sprint(bound)] = float64(cumulative)
+	}
+	return fields
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	dup := make(map[string]string, len(tags))
+	for k, v := range tags {
+		dup[k] = v
+	}
+	return dup
+}
+
+func addAttributes(tags map[string]string, attributes []*commonpb.KeyValue) {
+	for _, kv := range attributes {
+		if kv.GetValue() == nil {
+			continue
+		}
+		tags[kv.GetKey()] = attributeString(kv.GetValue())
+	}
+}
+
+// attributeString returns the value of an attribute as a tag value.
+func attributeString(v *commonpb.AnyValue) string {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	case *commonpb.AnyValue_BytesValue:
+		return hex.EncodeToString(v.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		s := "["
+		for i, value := range v.ArrayValue.GetValues() {
+			if i > 0 {
+				s += ","
+			}
+			s += attributeString(value)
+		}
+		return s + "]"
+	case *commonpb.AnyValue_KvlistValue:
+		s := "{"
+		for i, kv := range v.KvlistValue.GetValues() {
+			if i > 0 {
+				s += ","
+			}
+			s += kv.GetKey() + "=" + attributeString(kv.GetValue())
+		}
+		return s + "}"
+	default:
+		return ""
+	}
+}
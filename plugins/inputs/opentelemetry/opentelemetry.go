@@ -0,0 +1,248 @@
+package opentelemetry
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // Register the gzip decompressor
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultServiceAddress = "0.0.0.0:4317"
+	defaultMaxMsgSize     = 4 * 1024 * 1024
+
+	// metricsPath is the path of the OTLP/HTTP metrics endpoint.
+	metricsPath = "/v1/metrics"
+
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+const sampleConfig = `
+  ## Address and port to listen on for OTLP over gRPC.
+  # service_address = "0.0.0.0:4317"
+
+  ## Address and port to listen on for OTLP over HTTP, in the protobuf and
+  ## JSON encodings, at the path /v1/metrics.  Disabled if not set.
+  # http_service_address = "0.0.0.0:4318"
+
+  ## Maximum size of a message, or of the body of an HTTP request.
+  # max_msg_size = "4MB"
+
+  ## Set one or more allowed client CA certificate file names to
+  ## enable mutually authenticated TLS connections.
+  # tls_allowed_cacerts = ["/etc/telegraf/clientca.pem"]
+
+  ## Add service certificate and key.
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+`
+
+// OpenTelemetry receives metrics from OpenTelemetry SDKs and collectors with
+// the OTLP protocol.
+type OpenTelemetry struct {
+	ServiceAddress     string        `toml:"service_address"`
+	HTTPServiceAddress string        `toml:"http_service_address"`
+	MaxMsgSize         internal.Size `toml:"max_msg_size"`
+	tlsint.ServerConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	acc          telegraf.Accumulator
+	listener     net.Listener
+	httpListener net.Listener
+	grpcServer   *grpc.Server
+	httpServer   *http.Server
+	wg           sync.WaitGroup
+
+	collectorpb.UnimplementedMetricsServiceServer
+}
+
+func (o *OpenTelemetry) SampleConfig() string {
+	return sampleConfig
+}
+
+func (o *OpenTelemetry) Description() string {
+	return "Receive OpenTelemetry metrics over the OTLP protocol"
+}
+
+func (o *OpenTelemetry) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (o *OpenTelemetry) Start(acc telegraf.Accumulator) error {
+	o.acc = acc
+
+	if o.ServiceAddress == "" {
+		o.ServiceAddress = defaultServiceAddress
+	}
+	if o.MaxMsgSize.Size == 0 {
+		o.MaxMsgSize.Size = defaultMaxMsgSize
+	}
+
+	tlsConfig, err := o.ServerConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	o.listener, err = net.Listen("tcp", o.ServiceAddress)
+	if err != nil {
+		return err
+	}
+
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(int(o.MaxMsgSize.Size))}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	o.grpcServer = grpc.NewServer(opts...)
+	collectorpb.RegisterMetricsServiceServer(o.grpcServer, o)
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		if err := o.grpcServer.Serve(o.listener); err != nil {
+			o.Log.Errorf("Serving gRPC failed: %v", err)
+		}
+	}()
+	o.Log.Infof("Listening for OTLP over gRPC on %s", o.listener.Addr())
+
+	if o.HTTPServiceAddress == "" {
+		return nil
+	}
+
+	o.httpListener, err = net.Listen("tcp", o.HTTPServiceAddress)
+	if err != nil {
+		o.grpcServer.Stop()
+		o.wg.Wait()
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, o.serveHTTP)
+	o.httpServer = &http.Server{
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		var err error
+		if tlsConfig != nil {
+			err = o.httpServer.ServeTLS(o.httpListener, "", "")
+		} else {
+			err = o.httpServer.Serve(o.httpListener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			o.Log.Errorf("Serving HTTP failed: %v", err)
+		}
+	}()
+	o.Log.Infof("Listening for OTLP over HTTP on %s", o.httpListener.Addr())
+
+	return nil
+}
+
+func (o *OpenTelemetry) Stop() {
+	if o.httpServer != nil {
+		o.httpServer.Close()
+	}
+	if o.grpcServer != nil {
+		o.grpcServer.Stop()
+	}
+	o.wg.Wait()
+}
+
+// Export implements the OTLP metrics service.
+func (o *OpenTelemetry) Export(_ context.Context, req *collectorpb.ExportMetricsServiceRequest) (*collectorpb.ExportMetricsServiceResponse, error) {
+	o.addMetrics(req)
+	return &collectorpb.ExportMetricsServiceResponse{}, nil
+}
+
+func (o *OpenTelemetry) addMetrics(req *collectorpb.ExportMetricsServiceRequest) {
+	c := &converter{now: time.Now()}
+	for _, m := range c.resourceMetrics(req.GetResourceMetrics()) {
+		o.acc.AddMetric(m)
+	}
+}
+
+// serveHTTP implements the OTLP/HTTP metrics endpoint, with the request and
+// response encoded as protobuf or JSON.
+func (o *OpenTelemetry) serveHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	var unmarshal func([]byte, proto.Message) error
+	var marshal func(proto.Message) ([]byte, error)
+	switch contentType {
+	case contentTypeProtobuf:
+		unmarshal, marshal = proto.Unmarshal, proto.Marshal
+	case contentTypeJSON:
+		unmarshal, marshal = protojson.Unmarshal, protojson.Marshal
+	default:
+		http.Error(res, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var body io.Reader = http.MaxBytesReader(res, req.Body, o.MaxMsgSize.Size)
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer r.Close()
+		body = io.LimitReader(r, o.MaxMsgSize.Size)
+	}
+
+	buf, err := ioutil.ReadAll(body)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var exportReq collectorpb.ExportMetricsServiceRequest
+	if err := unmarshal(buf, &exportReq); err != nil {
+		o.Log.Debugf("Parse error: %v", err)
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	o.addMetrics(&exportReq)
+
+	resp, err := marshal(&collectorpb.ExportMetricsServiceResponse{})
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.Header().Set("Content-Type", contentType)
+	res.WriteHeader(http.StatusOK)
+	res.Write(resp)
+}
+
+func init() {
+	inputs.Add("opentelemetry", func() telegraf.Input {
+		return &OpenTelemetry{
+			ServiceAddress: defaultServiceAddress,
+			MaxMsgSize:     internal.Size{Size: defaultMaxMsgSize},
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package opentelemetry
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const ts = uint64(1614889298859000000)
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func exportRequest(metrics ...*metricspb.Metric) *collectorpb.ExportMetricsServiceRequest {
+	return &collectorpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource: &resourcepb.Resource{
+					Attributes: []*commonpb.KeyValue{stringAttribute("service.name", "checkout")},
+				},
+				InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{
+					{
+						InstrumentationLibrary: &commonpb.InstrumentationLibrary{Name: "app"},
+						Metrics:                metrics,
+					},
+				},
+			},
+		},
+	}
+}
+
+var gaugeMetric = &metricspb.Metric{
+	Name: "queue_length",
+	Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+		DataPoints: []*metricspb.NumberDataPoint{
+			{
+				Attributes:   []*commonpb.KeyValue{stringAttribute("queue", "orders")},
+				TimeUnixNano: ts,
+				Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: 42},
+			},
+		},
+	}},
+}
+
+var gaugeTelegrafMetric = testutil.MustMetric("queue_length",
+	map[string]string{"service.name": "checkout", "otel.library.name": "app", "queue": "orders"},
+	map[string]interface{}{"gauge": 42.0},
+	time.Unix(0, int64(ts)),
+	telegraf.Gauge,
+)
+
+func TestConvert(t *testing.T) {
+	tags := map[string]string{"service.name": "checkout", "otel.library.name": "app"}
+
+	tests := []struct {
+		name     string
+		metric   *metricspb.Metric
+		expected []telegraf.Metric
+	}{
+		{
+			name:     "gauge",
+			metric:   gaugeMetric,
+			expected: []telegraf.Metric{gaugeTelegrafMetric},
+		},
+		{
+			name: "monotonic sum",
+			metric: &metricspb.Metric{
+				Name: "requests",
+				Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+					IsMonotonic:            true,
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+					DataPoints: []*metricspb.NumberDataPoint{
+						{TimeUnixNano: ts, Value: &metricspb.NumberDataPoint_AsInt{AsInt: 7}},
+					},
+				}},
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("requests", tags,
+					map[string]interface{}{"counter": int64(7)},
+					time.Unix(0, int64(ts)), telegraf.Counter),
+			},
+		},
+		{
+			name: "delta monotonic sum",
+			metric: &metricspb.Metric{
+				Name: "requests",
+				Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+					IsMonotonic:            true,
+					AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+					DataPoints: []*metricspb.NumberDataPoint{
+						{TimeUnixNano: ts, Value: &metricspb.NumberDataPoint_AsInt{AsInt: 7}},
+					},
+				}},
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("requests", tags,
+					map[string]interface{}{"delta": int64(7)},
+					time.Unix(0, int64(ts)), telegraf.Gauge),
+			},
+		},
+		{
+			name: "non-monotonic sum",
+			metric: &metricspb.Metric{
+				Name: "connections",
+				Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+					DataPoints: []*metricspb.NumberDataPoint{
+						{TimeUnixNano: ts, Value: &metricspb.NumberDataPoint_AsInt{AsInt: -2}},
+					},
+				}},
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("connections", tags,
+					map[string]interface{}{"gauge": int64(-2)},
+					time.Unix(0, int64(ts)), telegraf.Gauge),
+			},
+		},
+		{
+			name: "histogram",
+			metric: &metricspb.Metric{
+				Name: "latency",
+				Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+					DataPoints: []*metricspb.HistogramDataPoint{
+						{
+							TimeUnixNano:   ts,
+							Count:          10,
+							Sum:            4.5,
+							BucketCounts:   []uint64{2, 5, 3},
+							ExplicitBounds: []float64{0.1, 1},
+						},
+					},
+				}},
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("latency", tags,
+					map[string]interface{}{
+						"count": 10.0,
+						"sum":   4.5,
+						"0.1":   2.0,
+						"1":     7.0,
+						"+Inf":  10.0,
+					},
+					time.Unix(0, int64(ts)), telegraf.Histogram),
+			},
+		},
+		{
+			name: "summary",
+			metric: &metricspb.Metric{
+				Name: "duration",
+				Data: &metricspb.Metric_Summary{Summary: &metricspb.Summary{
+					DataPoints: []*metricspb.SummaryDataPoint{
+						{
+							TimeUnixNano: ts,
+							Count:        3,
+							Sum:          6,
+							QuantileValues: []*metricspb.SummaryDataPoint_ValueAtQuantile{
+								{Quantile: 0.5, Value: 2},
+								{Quantile: 0.99, Value: 3},
+							},
+						},
+					},
+				}},
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("duration", tags,
+					map[string]interface{}{
+						"count": 3.0,
+						"sum":   6.0,
+						"0.5":   2.0,
+						"0.99":  3.0,
+					},
+					time.Unix(0, int64(ts)), telegraf.Summary),
+			},
+		},
+		{
+			name: "deprecated int sum with labels",
+			metric: &metricspb.Metric{
+				Name: "requests",
+				Data: &metricspb.Metric_IntSum{IntSum: &metricspb.IntSum{
+					IsMonotonic: true,
+					DataPoints: []*metricspb.IntDataPoint{
+						{
+							Labels:       []*commonpb.StringKeyValue{{Key: "method", Value: "GET"}},
+							TimeUnixNano: ts,
+							Value:        3,
+						},
+					},
+				}},
+			},
+			expected: []telegraf.Metric{
+				testutil.MustMetric("requests",
+					map[string]string{"service.name": "checkout", "otel.library.name": "app", "method": "GET"},
+					map[string]interface{}{"counter": int64(3)},
+					time.Unix(0, int64(ts)), telegraf.Counter),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &converter{now: time.Now()}
+			actual := c.resourceMetrics(exportRequest(tt.metric).GetResourceMetrics())
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+			for i := range actual {
+				require.Equal(t, tt.expected[i].Type(), actual[i].Type())
+			}
+		})
+	}
+}
+
+func TestAttributeString(t *testing.T) {
+	value := &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{
+		Values: []*commonpb.AnyValue{
+			{Value: &commonpb.AnyValue_IntValue{IntValue: 1}},
+			{Value: &commonpb.AnyValue_BoolValue{BoolValue: true}},
+			{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: 1.5}},
+		},
+	}}}
+	require.Equal(t, "[1,true,1.5]", attributeString(value))
+}
+
+func newTestOpenTelemetry() *OpenTelemetry {
+	return &OpenTelemetry{
+		ServiceAddress:     "127.0.0.1:0",
+		HTTPServiceAddress: "127.0.0.1:0",
+		Log:                testutil.Logger{},
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	plugin := newTestOpenTelemetry()
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	conn, err := grpc.Dial(plugin.listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := collectorpb.NewMetricsServiceClient(conn)
+	_, err = client.Export(context.Background(), exportRequest(gaugeMetric))
+	require.NoError(t, err)
+
+	acc.Wait(1)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{gaugeTelegrafMetric}, acc.GetTelegrafMetrics())
+}
+
+func TestHTTP(t *testing.T) {
+	protobuf, err := proto.Marshal(exportRequest(gaugeMetric))
+	require.NoError(t, err)
+	json, err := protojson.Marshal(exportRequest(gaugeMetric))
+	require.NoError(t, err)
+
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	_, err = w.Write(protobuf)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	tests := []struct {
+		name        string
+		contentType string
+		encoding    string
+		body        []byte
+	}{
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        protobuf,
+		},
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        json,
+		},
+		{
+			name:        "gzip",
+			contentType: "application/x-protobuf",
+			encoding:    "gzip",
+			body:        gzipped.Bytes(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newTestOpenTelemetry()
+			var acc testutil.Accumulator
+			require.NoError(t, plugin.Start(&acc))
+			defer plugin.Stop()
+
+			url := "http://" + plugin.httpListener.Addr().String() + "/v1/metrics"
+			req, err := http.NewRequest("POST", url, bytes.NewReader(tt.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, tt.contentType, resp.Header.Get("Content-Type"))
+
+			acc.Wait(1)
+			testutil.RequireMetricsEqual(t, []telegraf.Metric{gaugeTelegrafMetric}, acc.GetTelegrafMetrics())
+		})
+	}
+}
+
+func TestHTTPErrors(t *testing.T) {
+	plugin := newTestOpenTelemetry()
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	defer plugin.Stop()
+
+	url := "http://" + plugin.httpListener.Addr().String() + "/v1/metrics"
+
+	resp, err := http.Post(url, "text/plain", bytes.NewBufferString("up 1"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = http.Post(url, "application/x-protobuf", bytes.NewBufferString("up 1"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
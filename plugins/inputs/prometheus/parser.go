@@ -84,6 +84,12 @@ func Parse(buf []byte, header http.Header) ([]telegraf.Metric, error) {
 				}
 				metric, err := metric.New(metricName, tags, fields, t, ValueType(mf.GetType()))
 				if err == nil {
+					switch mf.GetType() {
+					case dto.MetricType_SUMMARY:
+						metric.AddDistribution(SummaryDistribution("", m.GetSummary()))
+					case dto.MetricType_HISTOGRAM:
+						metric.AddDistribution(HistogramDistribution("", m.GetHistogram()))
+					}
 					metrics = append(metrics, metric)
 				}
 			}
@@ -341,32 +341,21 @@ func (p *Prometheus) gatherURL(u URLAndAddress, acc telegraf.Accumulator) error
 			u.URL, err)
 	}
 
+	// strip user and password from URL
+	u.OriginalURL.User = nil
 	for _, metric := range metrics {
-		tags := metric.Tags()
-		// strip user and password from URL
-		u.OriginalURL.User = nil
+		// The metrics are added as parsed, so that the distributions of
+		// summaries and histograms are kept.
 		if p.URLTag != "" {
-			tags[p.URLTag] = u.OriginalURL.String()
+			metric.AddTag(p.URLTag, u.OriginalURL.String())
 		}
 		if u.Address != "" {
-			tags["address"] = u.Address
+			metric.AddTag("address", u.Address)
 		}
 		for k, v := range u.Tags {
-			tags[k] = v
-		}
-
-		switch metric.Type() {
-		case telegraf.Counter:
-			acc.AddCounter(metric.Name(), metric.Fields(), tags, metric.Time())
-		case telegraf.Gauge:
-			acc.AddGauge(metric.Name(), metric.Fields(), tags, metric.Time())
-		case telegraf.Summary:
-			acc.AddSummary(metric.Name(), metric.Fields(), tags, metric.Time())
-		case telegraf.Histogram:
-			acc.AddHistogram(metric.Name(), metric.Fields(), tags, metric.Time())
-		default:
-			acc.AddFields(metric.Name(), metric.Fields(), tags, metric.Time())
+			metric.AddTag(k, v)
 		}
+		acc.AddMetric(metric)
 	}
 
 	return nil
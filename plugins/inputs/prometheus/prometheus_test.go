@@ -150,6 +150,59 @@ func TestPrometheusGeneratesSummaryMetricsV2(t *testing.T) {
 
 }
 
+func TestPrometheusGeneratesDistributions(t *testing.T) {
+	const data = `# HELP http_request_duration_seconds A histogram of the request duration.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{le="0.1"} 3
+http_request_duration_seconds_bucket{le="1"} 5
+http_request_duration_seconds_bucket{le="+Inf"} 6
+http_request_duration_seconds_sum 2.5
+http_request_duration_seconds_count 6
+`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, sampleSummaryTextFormat+data)
+	}))
+	defer ts.Close()
+
+	for _, version := range []int{1, 2} {
+		t.Run(fmt.Sprintf("metric_version %d", version), func(t *testing.T) {
+			p := &Prometheus{
+				Log:           testutil.Logger{},
+				URLs:          []string{ts.URL},
+				URLTag:        "url",
+				MetricVersion: version,
+			}
+
+			var acc testutil.Accumulator
+			require.NoError(t, acc.GatherError(p.Gather))
+
+			var summary, histogram *telegraf.Distribution
+			for _, m := range acc.GetTelegrafMetrics() {
+				if len(m.DistributionList()) == 0 {
+					continue
+				}
+				require.Len(t, m.DistributionList(), 1)
+				require.Equal(t, ts.URL+"/metrics", m.Tags()["url"])
+				switch m.Type() {
+				case telegraf.Summary:
+					summary = m.DistributionList()[0]
+				case telegraf.Histogram:
+					histogram = m.DistributionList()[0]
+				}
+			}
+
+			require.NotNil(t, summary)
+			require.Equal(t, uint64(7), summary.Count)
+			require.Len(t, summary.Quantiles, 5)
+
+			require.NotNil(t, histogram)
+			require.Equal(t, uint64(6), histogram.Count)
+			require.Equal(t, 2.5, histogram.Sum)
+			require.Len(t, histogram.Buckets, 3)
+		})
+	}
+}
+
 func TestSummaryMayContainNaN(t *testing.T) {
 	const data = `# HELP go_gc_duration_seconds A summary of the GC invocation durations.
 # TYPE go_gc_duration_seconds summary
@@ -197,15 +250,22 @@ go_gc_duration_seconds_count 42
 			time.Unix(0, 0),
 			telegraf.Summary,
 		),
-		testutil.MustMetric(
-			"prometheus",
-			map[string]string{},
-			map[string]interface{}{
-				"go_gc_duration_seconds_sum":   42.0,
-				"go_gc_duration_seconds_count": 42.0,
+		testutil.WithDistributions(
+			testutil.MustMetric(
+				"prometheus",
+				map[string]string{},
+				map[string]interface{}{
+					"go_gc_duration_seconds_sum":   42.0,
+					"go_gc_duration_seconds_count": 42.0,
+				},
+				time.Unix(0, 0),
+				telegraf.Summary,
+			),
+			&telegraf.Distribution{
+				Key:   "go_gc_duration_seconds",
+				Count: 42,
+				Sum:   42,
 			},
-			time.Unix(0, 0),
-			telegraf.Summary,
 		),
 	}
 
@@ -0,0 +1,310 @@
+package snmp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/snmp"
+)
+
+// sysObjectIDOid is the OID of SNMPv2-MIB::sysObjectID.0, which identifies
+// the kind of device of an agent.
+const sysObjectIDOid = ".1.3.6.1.2.1.1.2.0"
+
+// maxDiscoveryAddresses limits the size of the networks probed, so that an
+// IPv6 prefix can't be probed by mistake.
+const maxDiscoveryAddresses = 1 << 16
+
+// Discovery holds the configuration of the discovery of agents in networks.
+type Discovery struct {
+	// Networks to probe, in CIDR notation.
+	Networks []string `toml:"networks"`
+	// Port of the agents.
+	Port uint16 `toml:"port"`
+	// Interval between probes of the networks, during which the devices
+	// found are cached.
+	Interval internal.Duration `toml:"interval"`
+	// Timeout of the probe of an address.
+	Timeout internal.Duration `toml:"timeout"`
+	// Maximum number of addresses probed concurrently.
+	MaxConcurrentProbes int `toml:"max_concurrent_probes"`
+	// Maximum time the start of the plugin waits for the first probe of the
+	// networks, which then goes on in the background.
+	StartupTimeout internal.Duration `toml:"startup_timeout"`
+
+	// probe returns the sysObjectID of the agent of an address.
+	probe func(agent string) (string, error)
+
+	mu      sync.Mutex
+	devices []*device
+}
+
+// Profile holds the tables gathered from the discovered devices of some kinds.
+type Profile struct {
+	// Prefixes of the sysObjectID of the devices of the profile.  A profile
+	// without prefixes matches all devices.
+	SysObjectIDs []string `toml:"sys_object_ids"`
+	// MaxRepetitions overrides the GETBULK max-repetitions parameter of the
+	// plugin for the devices of the profile.
+	MaxRepetitions uint8 `toml:"max_repetitions"`
+	// Interval between gathers of the devices of the profile, longer than
+	// the interval of the plugin.
+	Interval internal.Duration `toml:"interval"`
+
+	Tables []Table `toml:"table"`
+}
+
+// device is an agent found by the discovery.
+type device struct {
+	agent       string
+	sysObjectID string
+	profile     *Profile
+
+	// mu guards the connection, which is created by the first gather and
+	// closed once the device is dropped by the discovery, and the time of the
+	// last gather.
+	mu         sync.Mutex
+	conn       snmpConnection
+	lastGather time.Time
+}
+
+// due tells if the device is gathered at a collection, which is the case
+// once the interval of its profile has elapsed since the last gather, and
+// records the gather.
+func (dev *device) due(now time.Time) bool {
+	if dev.profile == nil || dev.profile.Interval.Duration == 0 {
+		return true
+	}
+
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	if !dev.lastGather.IsZero() && now.Sub(dev.lastGather) < dev.profile.Interval.Duration {
+		return false
+	}
+	dev.lastGather = now
+	return true
+}
+
+// close closes the connection of the device, if any.
+func (dev *device) close() {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	if gs, ok := dev.conn.(snmp.GosnmpWrapper); ok && gs.Conn != nil {
+		gs.Conn.Close()
+	}
+	dev.conn = nil
+}
+
+// init validates the networks and sets the defaults of the discovery.
+func (d *Discovery) init(config snmp.ClientConfig) error {
+	for _, network := range d.Networks {
+		_, ipnet, err := net.ParseCIDR(network)
+		if err != nil {
+			return fmt.Errorf("parsing network: %w", err)
+		}
+		ones, bits := ipnet.Mask.Size()
+		if bits-ones > 16 {
+			return fmt.Errorf("network %s has more than %d addresses", network, maxDiscoveryAddresses)
+		}
+	}
+
+	if d.Port == 0 {
+		d.Port = 161
+	}
+	if d.Interval.Duration == 0 {
+		d.Interval.Duration = time.Hour
+	}
+	if d.Timeout.Duration == 0 {
+		d.Timeout.Duration = time.Second
+	}
+	if d.MaxConcurrentProbes <= 0 {
+		d.MaxConcurrentProbes = 64
+	}
+	if d.StartupTimeout.Duration == 0 {
+		d.StartupTimeout.Duration = 10 * time.Second
+	}
+
+	if d.probe == nil {
+		// Unresponsive addresses are the common case, so that probes are
+		// not retried.
+		config.Timeout = d.Timeout
+		config.Retries = 0
+		d.probe = func(agent string) (string, error) {
+			return probe(config, agent)
+		}
+	}
+	return nil
+}
+
+// addresses returns the addresses of the networks, without the network and
+// broadcast addresses of IPv4 networks.
+func (d *Discovery) addresses() []string {
+	var addresses []string
+	for _, network := range d.Networks {
+		_, ipnet, err := net.ParseCIDR(network)
+		if err != nil {
+			continue
+		}
+		ones, bits := ipnet.Mask.Size()
+		skipEnds := bits == 8*net.IPv4len && bits-ones > 1
+
+		ip := make(net.IP, len(ipnet.IP))
+		copy(ip, ipnet.IP)
+		var ips []net.IP
+		for ; ipnet.Contains(ip); ip = nextIP(ip) {
+			ips = append(ips, ip)
+		}
+		if skipEnds {
+			ips = ips[1 : len(ips)-1]
+		}
+		for _, ip := range ips {
+			addresses = append(addresses, net.JoinHostPort(ip.String(), strconv.Itoa(int(d.Port))))
+		}
+	}
+	return addresses
+}
+
+// discover probes the addresses of the networks, and replaces the devices
+// cached with the ones answering.  The devices already known keep their
+// connection, and the connections of the devices dropped are closed.  The
+// probes stop once cancel is closed, and false is returned without changing
+// the devices.
+func (d *Discovery) discover(profiles []Profile, cancel <-chan struct{}) bool {
+	addresses := d.addresses()
+	found := make([]string, len(addresses))
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, d.MaxConcurrentProbes)
+	for i, address := range addresses {
+		select {
+		case limit <- struct{}{}:
+		case <-cancel:
+			wg.Wait()
+			return false
+		}
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			if sysObjectID, err := d.probe("udp://" + address); err == nil {
+				found[i] = sysObjectID
+			}
+		}(i, address)
+	}
+	wg.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	known := make(map[string]*device, len(d.devices))
+	for _, dev := range d.devices {
+		known[dev.agent] = dev
+	}
+	d.devices = d.devices[:0:0]
+	for i, sysObjectID := range found {
+		if sysObjectID == "" {
+			continue
+		}
+		agent := "udp://" + addresses[i]
+		if dev, ok := known[agent]; ok && dev.sysObjectID == sysObjectID {
+			d.devices = append(d.devices, dev)
+			delete(known, agent)
+			continue
+		}
+		d.devices = append(d.devices, &device{
+			agent:       agent,
+			sysObjectID: sysObjectID,
+			profile:     matchProfile(profiles, sysObjectID),
+		})
+	}
+	for _, dev := range known {
+		dev.close()
+	}
+	return true
+}
+
+// close closes the connections of the devices found.
+func (d *Discovery) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, dev := range d.devices {
+		dev.close()
+	}
+}
+
+// discovered returns the devices found by the last discovery.
+func (d *Discovery) discovered() []*device {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.devices
+}
+
+// matchProfile returns the profile with the longest sysObjectID prefix
+// matching a sysObjectID, or nil if no profile matches.
+func matchProfile(profiles []Profile, sysObjectID string) *Profile {
+	var match *Profile
+	matchLength := -1
+	for i := range profiles {
+		p := &profiles[i]
+		if len(p.SysObjectIDs) == 0 && matchLength < 0 {
+			match = p
+			matchLength = 0
+		}
+		for _, prefix := range p.SysObjectIDs {
+			if sysObjectID != prefix && !strings.HasPrefix(sysObjectID, prefix+".") {
+				continue
+			}
+			if len(prefix) > matchLength {
+				match = p
+				matchLength = len(prefix)
+			}
+		}
+	}
+	return match
+}
+
+// probe gets the sysObjectID of an agent.
+func probe(config snmp.ClientConfig, agent string) (string, error) {
+	gs, err := snmp.NewWrapper(config)
+	if err != nil {
+		return "", err
+	}
+	if err := gs.SetAgent(agent); err != nil {
+		return "", err
+	}
+	if err := gs.Connect(); err != nil {
+		return "", err
+	}
+	defer gs.Conn.Close()
+
+	pkt, err := gs.GoSNMP.Get([]string{sysObjectIDOid})
+	if err != nil {
+		return "", err
+	}
+	if len(pkt.Variables) == 0 || pkt.Variables[0].Type != gosnmp.ObjectIdentifier {
+		return "", fmt.Errorf("no sysObjectID")
+	}
+	sysObjectID, ok := pkt.Variables[0].Value.(string)
+	if !ok || sysObjectID == "" {
+		return "", fmt.Errorf("no sysObjectID")
+	}
+	return sysObjectID, nil
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
@@ -0,0 +1,276 @@
+package snmp
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/internal"
+	config "github.com/influxdata/telegraf/internal/snmp"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveryInit(t *testing.T) {
+	d := &Discovery{Networks: []string{"10.0.0.0/24", "2001:db8::/112"}}
+	require.NoError(t, d.init(config.ClientConfig{}))
+	require.Equal(t, uint16(161), d.Port)
+	require.Equal(t, 64, d.MaxConcurrentProbes)
+	require.NotNil(t, d.probe)
+
+	d = &Discovery{Networks: []string{"10.0.0.0"}}
+	require.Error(t, d.init(config.ClientConfig{}))
+
+	d = &Discovery{Networks: []string{"10.0.0.0/8"}}
+	require.Error(t, d.init(config.ClientConfig{}))
+
+	d = &Discovery{Networks: []string{"2001:db8::/64"}}
+	require.Error(t, d.init(config.ClientConfig{}))
+}
+
+func TestSnmpInit_profiles(t *testing.T) {
+	s := &Snmp{
+		Profiles: []Profile{
+			{SysObjectIDs: []string{"SNMPv2-SMI::enterprises.9", ".1.3.6.1.4.1.2636"}},
+		},
+		Discovery: &Discovery{Networks: []string{"10.0.0.0/24"}},
+		Log:       testutil.Logger{},
+	}
+	require.NoError(t, s.Init())
+	require.Equal(t, []string{".1.3.6.1.4.1.9", ".1.3.6.1.4.1.2636"}, s.Profiles[0].SysObjectIDs)
+}
+
+func TestDiscoveryAddresses(t *testing.T) {
+	d := &Discovery{
+		Networks: []string{"10.0.0.0/30", "10.0.1.1/32", "2001:db8::/127"},
+		Port:     1161,
+	}
+	require.Equal(t, []string{
+		"10.0.0.1:1161",
+		"10.0.0.2:1161",
+		"10.0.1.1:1161",
+		"[2001:db8::]:1161",
+		"[2001:db8::1]:1161",
+	}, d.addresses())
+}
+
+func TestMatchProfile(t *testing.T) {
+	profiles := []Profile{
+		{Tables: []Table{{Name: "default"}}},
+		{SysObjectIDs: []string{".1.3.6.1.4.1.9"}, Tables: []Table{{Name: "cisco"}}},
+		{SysObjectIDs: []string{".1.3.6.1.4.1.9.1.1", ".1.3.6.1.4.1.2636"}, Tables: []Table{{Name: "other"}}},
+	}
+
+	tests := []struct {
+		sysObjectID string
+		expected    string
+	}{
+		{".1.3.6.1.4.1.9.1.2", "cisco"},
+		{".1.3.6.1.4.1.9", "cisco"},
+		{".1.3.6.1.4.1.9.1.1", "other"},
+		{".1.3.6.1.4.1.9.1.12", "cisco"},
+		{".1.3.6.1.4.1.2636.1", "other"},
+		{".1.3.6.1.4.1.99", "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sysObjectID, func(t *testing.T) {
+			p := matchProfile(profiles, tt.sysObjectID)
+			require.NotNil(t, p)
+			require.Equal(t, tt.expected, p.Tables[0].Name)
+		})
+	}
+
+	require.Nil(t, matchProfile(profiles[1:], ".1.3.6.1.4.1.99"))
+}
+
+func TestDiscover(t *testing.T) {
+	sysObjectIDs := map[string]string{
+		"udp://10.0.0.1:161": ".1.3.6.1.4.1.9.1.1",
+		"udp://10.0.0.3:161": ".1.3.6.1.4.1.8072.3.2.10",
+	}
+	d := &Discovery{
+		Networks: []string{"10.0.0.0/29"},
+		probe: func(agent string) (string, error) {
+			if sysObjectID, ok := sysObjectIDs[agent]; ok {
+				return sysObjectID, nil
+			}
+			return "", fmt.Errorf("timeout")
+		},
+	}
+	require.NoError(t, d.init(config.ClientConfig{}))
+	profiles := []Profile{{SysObjectIDs: []string{".1.3.6.1.4.1.9"}}}
+
+	require.True(t, d.discover(profiles, nil))
+	devices := d.discovered()
+	require.Len(t, devices, 2)
+	require.Equal(t, "udp://10.0.0.1:161", devices[0].agent)
+	require.Equal(t, &profiles[0], devices[0].profile)
+	require.Equal(t, "udp://10.0.0.3:161", devices[1].agent)
+	require.Nil(t, devices[1].profile)
+
+	// The devices still answering keep their connection.
+	devices[0].conn = tsc
+	devices[1].conn = tsc
+	delete(sysObjectIDs, "udp://10.0.0.3:161")
+	sysObjectIDs["udp://10.0.0.2:161"] = ".1.3.6.1.4.1.2636"
+
+	require.True(t, d.discover(profiles, nil))
+	rediscovered := d.discovered()
+	require.Len(t, rediscovered, 2)
+	require.Same(t, devices[0], rediscovered[0])
+	require.Equal(t, "udp://10.0.0.2:161", rediscovered[1].agent)
+	require.Nil(t, rediscovered[1].conn)
+
+	// The connection of the device no longer answering is closed.
+	require.Nil(t, devices[1].conn)
+	require.NotNil(t, devices[0].conn)
+
+	d.close()
+	require.Nil(t, devices[0].conn)
+}
+
+func TestSnmpStart_discovery(t *testing.T) {
+	s := &Snmp{
+		Discovery: &Discovery{
+			Networks: []string{"10.0.0.0/30"},
+			probe: func(agent string) (string, error) {
+				return ".1.3.6.1.4.1.9", nil
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, s.Init())
+
+	// The first discovery is done by the time Start returns.
+	require.NoError(t, s.Start(nil))
+	defer s.Stop()
+	require.Len(t, s.Discovery.discovered(), 2)
+}
+
+func TestDiscover_cancel(t *testing.T) {
+	cancel := make(chan struct{})
+	var once sync.Once
+	d := &Discovery{
+		Networks:            []string{"10.0.0.0/24"},
+		MaxConcurrentProbes: 1,
+		probe: func(agent string) (string, error) {
+			once.Do(func() { close(cancel) })
+			return ".1.3.6.1.4.1.9", nil
+		},
+		devices: []*device{{agent: "udp://10.0.1.1:161"}},
+	}
+	require.NoError(t, d.init(config.ClientConfig{}))
+
+	// The probes stop once cancelled, and the devices are kept.
+	require.False(t, d.discover(nil, cancel))
+	require.Len(t, d.discovered(), 1)
+	require.Equal(t, "udp://10.0.1.1:161", d.discovered()[0].agent)
+}
+
+func TestSnmpStart_discoveryTimeout(t *testing.T) {
+	release := make(chan struct{})
+	s := &Snmp{
+		Discovery: &Discovery{
+			Networks:       []string{"10.0.0.0/24"},
+			StartupTimeout: internal.Duration{Duration: 10 * time.Millisecond},
+			probe: func(agent string) (string, error) {
+				<-release
+				return "", fmt.Errorf("timeout")
+			},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, s.Init())
+
+	// Start returns after the startup timeout, and Stop doesn't wait for
+	// the addresses not probed yet.
+	require.NoError(t, s.Start(nil))
+	require.Empty(t, s.Discovery.discovered())
+	close(release)
+	s.Stop()
+}
+
+func TestGather_discovered(t *testing.T) {
+	profile := &Profile{
+		Tables: []Table{
+			{
+				Name: "profileTable",
+				Fields: []Field{
+					{
+						Name: "profileField",
+						Oid:  ".1.0.0.0.1.5",
+					},
+				},
+			},
+		},
+	}
+	s := &Snmp{
+		Name: "mytable",
+		Fields: []Field{
+			{
+				Name: "myfield2",
+				Oid:  ".1.0.0.1.2",
+			},
+		},
+		MaxConcurrentAgents: 1,
+		Discovery: &Discovery{
+			devices: []*device{
+				{agent: "udp://10.0.0.1:161", conn: tsc, profile: profile},
+				{agent: "udp://10.0.0.2:161", conn: tsc},
+			},
+		},
+		Log: testutil.Logger{},
+
+		initialized: true,
+	}
+	acc := &testutil.Accumulator{}
+
+	require.NoError(t, s.Gather(acc))
+	require.Len(t, acc.Metrics, 3)
+	require.Equal(t, uint64(1), countMeasurement(acc, "profileTable"))
+	require.Equal(t, uint64(2), countMeasurement(acc, "mytable"))
+}
+
+func TestGather_discoveredInterval(t *testing.T) {
+	profile := &Profile{
+		Interval: internal.Duration{Duration: time.Hour},
+	}
+	s := &Snmp{
+		Name: "mytable",
+		Fields: []Field{
+			{
+				Name: "myfield2",
+				Oid:  ".1.0.0.1.2",
+			},
+		},
+		Discovery: &Discovery{
+			devices: []*device{
+				{agent: "udp://10.0.0.1:161", conn: tsc, profile: profile},
+				{agent: "udp://10.0.0.2:161", conn: tsc},
+			},
+		},
+		Log: testutil.Logger{},
+
+		initialized: true,
+	}
+
+	// The device of the profile is only gathered once in its interval.
+	acc := &testutil.Accumulator{}
+	require.NoError(t, s.Gather(acc))
+	require.Equal(t, uint64(2), countMeasurement(acc, "mytable"))
+
+	acc = &testutil.Accumulator{}
+	require.NoError(t, s.Gather(acc))
+	require.Equal(t, uint64(1), countMeasurement(acc, "mytable"))
+}
+
+func countMeasurement(acc *testutil.Accumulator, measurement string) uint64 {
+	var count uint64
+	for _, m := range acc.Metrics {
+		if m.Measurement == measurement {
+			count++
+		}
+	}
+	return count
+}
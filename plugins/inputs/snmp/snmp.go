@@ -1,14 +1,10 @@
 package snmp
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/binary"
 	"fmt"
-	"log"
 	"math"
 	"net"
-	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,7 +15,6 @@ import (
 	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/internal/snmp"
 	"github.com/influxdata/telegraf/plugins/inputs"
-	"github.com/influxdata/wlog"
 )
 
 const description = `Retrieves SNMP values from remote agents`
@@ -38,6 +33,10 @@ const sampleConfig = `
   ## Agent host tag; the tag used to reference the source host
   # agent_host_tag = "agent_host"
 
+  ## Paths of the MIB files used to translate OIDs; directories are searched
+  ## recursively.
+  # path = ["/usr/share/snmp/mibs"]
+
   ## SNMP community string.
   # community = "public"
 
@@ -47,6 +46,9 @@ const sampleConfig = `
   ## The GETBULK max-repetitions parameter.
   # max_repetitions = 10
 
+  ## Maximum number of agents gathered concurrently; 0 for no limit.
+  # max_concurrent_agents = 0
+
   ## SNMPv3 authentication and encryption options.
   ##
   ## Security Name.
@@ -64,35 +66,46 @@ const sampleConfig = `
   ## Privacy password used for encrypted messages.
   # priv_password = ""
 
+  ## Discover agents by probing networks for devices answering SNMP requests
+  ## with the settings above.  The devices found are gathered like the agents,
+  ## along with the tables of the profile matching their sysObjectID.
+  # [inputs.snmp.discovery]
+  #   ## Networks to probe, in CIDR notation; at most 65536 addresses each.
+  #   networks = ["192.168.1.0/24"]
+  #   ## Port of the agents.
+  #   # port = 161
+  #   ## Interval between probes of the networks, during which the devices
+  #   ## found are cached.
+  #   # interval = "1h"
+  #   ## Timeout of the probe of an address; probes are not retried.
+  #   # timeout = "1s"
+  #   ## Maximum number of addresses probed concurrently.
+  #   # max_concurrent_probes = 64
+  #   ## Maximum time the start waits for the first probe of the networks,
+  #   ## which then goes on in the background.
+  #   # startup_timeout = "10s"
+
+  ## Tables gathered from the discovered devices whose sysObjectID starts with
+  ## one of the prefixes; the profile of the longest prefix is used, and a
+  ## profile without prefixes matches any device.
+  # [[inputs.snmp.profile]]
+  #   sys_object_ids = [".1.3.6.1.4.1.9"]
+  #   ## The GETBULK max-repetitions parameter for the devices of the profile.
+  #   # max_repetitions = 25
+  #   ## Interval between gathers of the devices of the profile, at the first
+  #   ## collection after it has elapsed; by default they are gathered at
+  #   ## every collection.
+  #   # interval = "5m"
+  #
+  #   [[inputs.snmp.profile.table]]
+  #     oid = "IF-MIB::ifXTable"
+  #     name = "interface"
+
   ## Add fields and tables defining the variables you wish to collect.  This
   ## example collects the system uptime and interface variables.  Reference the
   ## full plugin documentation for configuration details.
 `
 
-// execCommand is so tests can mock out exec.Command usage.
-var execCommand = exec.Command
-
-// execCmd executes the specified command, returning the STDOUT content.
-// If command exits with error status, the output is captured into the returned error.
-func execCmd(arg0 string, args ...string) ([]byte, error) {
-	if wlog.LogLevel() == wlog.DEBUG {
-		quoted := make([]string, 0, len(args))
-		for _, arg := range args {
-			quoted = append(quoted, fmt.Sprintf("%q", arg))
-		}
-		log.Printf("D! [inputs.snmp] executing %q %s", arg0, strings.Join(quoted, " "))
-	}
-
-	out, err := execCommand(arg0, args...).Output()
-	if err != nil {
-		if err, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("%s: %w", bytes.TrimRight(err.Stderr, "\r\n"), err)
-		}
-		return nil, err
-	}
-	return out, nil
-}
-
 // Snmp holds the configuration for the plugin.
 type Snmp struct {
 	// The SNMP agent to query. Format is [SCHEME://]ADDR[:PORT] (e.g.
@@ -112,8 +125,43 @@ type Snmp struct {
 	Name   string  // deprecated in 1.14; use name_override
 	Fields []Field `toml:"field"`
 
+	// Maximum number of agents gathered concurrently; 0 for no limit.
+	MaxConcurrentAgents int `toml:"max_concurrent_agents"`
+
+	// Discovery of the agents of networks, and the profiles of the tables
+	// gathered from the devices discovered.
+	Discovery *Discovery `toml:"discovery"`
+	Profiles  []Profile  `toml:"profile"`
+
+	Log telegraf.Logger `toml:"-"`
+
 	connectionCache []snmpConnection
 	initialized     bool
+
+	cancel chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Init loads the MIB files, and validates the discovery.
+func (s *Snmp) Init() error {
+	if err := snmp.LoadMibsFromPath(s.Path, s.Log); err != nil {
+		return err
+	}
+
+	for i := range s.Profiles {
+		for j, oid := range s.Profiles[i].SysObjectIDs {
+			_, oidNum, _, _, err := SnmpTranslate(oid)
+			if err != nil {
+				return fmt.Errorf("translating sysObjectID %s: %w", oid, err)
+			}
+			s.Profiles[i].SysObjectIDs[j] = oidNum
+		}
+	}
+
+	if s.Discovery != nil {
+		return s.Discovery.init(s.ClientConfig)
+	}
+	return nil
 }
 
 func (s *Snmp) init() error {
@@ -129,6 +177,14 @@ func (s *Snmp) init() error {
 		}
 	}
 
+	for i := range s.Profiles {
+		for j := range s.Profiles[i].Tables {
+			if err := s.Profiles[i].Tables[j].Init(); err != nil {
+				return fmt.Errorf("initializing table %s: %w", s.Profiles[i].Tables[j].Name, err)
+			}
+		}
+	}
+
 	for i := range s.Fields {
 		if err := s.Fields[i].init(); err != nil {
 			return fmt.Errorf("initializing field %s: %w", s.Fields[i].Name, err)
@@ -187,7 +243,7 @@ func (t *Table) Init() error {
 }
 
 // initBuild initializes the table if it has an OID configured. If so, the
-// net-snmp tools will be used to look up the OID and auto-populate the table's
+// MIB files will be used to look up the OID and auto-populate the table's
 // fields.
 func (t *Table) initBuild() error {
 	if t.Oid == "" {
@@ -238,7 +294,7 @@ type Field struct {
 	//  "hwaddr" will convert a 6-byte string to a MAC address.
 	//  "ipaddr" will convert the value to an IPv4 or IPv6 address.
 	Conversion string
-	// Translate tells if the value of the field should be snmptranslated
+	// Translate tells if the value of the field, an OID, should be translated
 	Translate bool
 
 	initialized bool
@@ -310,6 +366,7 @@ func init() {
 				Timeout:        internal.Duration{Duration: 5 * time.Second},
 				Version:        2,
 				Community:      "public",
+				Path:           snmp.DefaultMibPath,
 			},
 		}
 	})
@@ -334,39 +391,136 @@ func (s *Snmp) Gather(acc telegraf.Accumulator) error {
 	}
 
 	var wg sync.WaitGroup
-	for i, agent := range s.Agents {
+	var limit chan struct{}
+	if s.MaxConcurrentAgents > 0 {
+		limit = make(chan struct{}, s.MaxConcurrentAgents)
+	}
+	gather := func(agent string, getConnection func() (snmpConnection, error), tables []Table) {
 		wg.Add(1)
-		go func(i int, agent string) {
+		go func() {
 			defer wg.Done()
-			gs, err := s.getConnection(i)
+			if limit != nil {
+				limit <- struct{}{}
+				defer func() { <-limit }()
+			}
+
+			gs, err := getConnection()
 			if err != nil {
 				acc.AddError(fmt.Errorf("agent %s: %w", agent, err))
 				return
 			}
+			s.gatherAgent(acc, gs, agent, tables)
+		}()
+	}
 
-			// First is the top-level fields. We treat the fields as table prefixes with an empty index.
-			t := Table{
-				Name:   s.Name,
-				Fields: s.Fields,
-			}
-			topTags := map[string]string{}
-			if err := s.gatherTable(acc, gs, t, topTags, false); err != nil {
-				acc.AddError(fmt.Errorf("agent %s: %w", agent, err))
-			}
+	for i, agent := range s.Agents {
+		i := i
+		gather(agent, func() (snmpConnection, error) {
+			return s.getConnection(i)
+		}, s.Tables)
+	}
 
-			// Now is the real tables.
-			for _, t := range s.Tables {
-				if err := s.gatherTable(acc, gs, t, topTags, true); err != nil {
-					acc.AddError(fmt.Errorf("agent %s: gathering table %s: %w", agent, t.Name, err))
-				}
+	if s.Discovery != nil {
+		now := time.Now()
+		for _, dev := range s.Discovery.discovered() {
+			if !dev.due(now) {
+				continue
+			}
+			dev := dev
+			tables := s.Tables
+			if dev.profile != nil {
+				tables = append(tables[:len(tables):len(tables)], dev.profile.Tables...)
 			}
-		}(i, agent)
+			gather(dev.agent, func() (snmpConnection, error) {
+				return s.getDeviceConnection(dev)
+			}, tables)
+		}
 	}
 	wg.Wait()
 
 	return nil
 }
 
+// gatherAgent gathers the top-level fields and the tables from an agent.
+func (s *Snmp) gatherAgent(acc telegraf.Accumulator, gs snmpConnection, agent string, tables []Table) {
+	// First is the top-level fields. We treat the fields as table prefixes with an empty index.
+	t := Table{
+		Name:   s.Name,
+		Fields: s.Fields,
+	}
+	topTags := map[string]string{}
+	if err := s.gatherTable(acc, gs, t, topTags, false); err != nil {
+		acc.AddError(fmt.Errorf("agent %s: %w", agent, err))
+	}
+
+	// Now is the real tables.
+	for _, t := range tables {
+		if err := s.gatherTable(acc, gs, t, topTags, true); err != nil {
+			acc.AddError(fmt.Errorf("agent %s: gathering table %s: %w", agent, t.Name, err))
+		}
+	}
+}
+
+// Start starts the discovery of the agents, if configured.  The networks
+// are probed again at every interval of the discovery.
+func (s *Snmp) Start(_ telegraf.Accumulator) error {
+	if s.Discovery == nil {
+		return nil
+	}
+
+	// The discovery runs in the background.  Start waits for the first one
+	// up to the startup timeout, so that the devices of small networks are
+	// gathered from the first interval, and with --test or --once.
+	s.cancel = make(chan struct{})
+	discover := func() {
+		start := time.Now()
+		if s.Discovery.discover(s.Profiles, s.cancel) {
+			s.Log.Debugf("Discovered %d devices in %s", len(s.Discovery.discovered()), time.Since(start))
+		}
+	}
+
+	discovered := make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		discover()
+		close(discovered)
+
+		ticker := time.NewTicker(s.Discovery.Interval.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.cancel:
+				return
+			case <-ticker.C:
+			}
+			discover()
+		}
+	}()
+
+	timeout := time.NewTimer(s.Discovery.StartupTimeout.Duration)
+	defer timeout.Stop()
+	select {
+	case <-discovered:
+	case <-timeout.C:
+		s.Log.Infof("Discovery not done after %s, going on in the background",
+			s.Discovery.StartupTimeout.Duration)
+	}
+	return nil
+}
+
+// Stop stops the discovery of the agents, and closes the connections to the
+// devices found.
+func (s *Snmp) Stop() {
+	if s.cancel != nil {
+		close(s.cancel)
+	}
+	s.wg.Wait()
+	if s.Discovery != nil {
+		s.Discovery.close()
+	}
+}
+
 func (s *Snmp) gatherTable(acc telegraf.Accumulator, gs snmpConnection, t Table, topTags map[string]string, walk bool) error {
 	rt, err := t.Build(gs, walk)
 	if err != nil {
@@ -463,7 +617,7 @@ func (t Table) Build(gs snmpConnection, walk bool) (*RTable, error) {
 					}, idx)
 				}
 
-				// snmptranslate table field value here
+				// translate table field value here
 				if f.Translate {
 					if entOid, ok := ent.Value.(string); ok {
 						_, _, oidText, _, err := SnmpTranslate(entOid)
@@ -574,6 +728,35 @@ func (s *Snmp) getConnection(idx int) (snmpConnection, error) {
 	return gs, nil
 }
 
+// getDeviceConnection creates the connection to a discovered device, with
+// the GETBULK settings of its profile, and caches it in the device.  It is
+// an error to use a connection in more than one goroutine.
+func (s *Snmp) getDeviceConnection(dev *device) (snmpConnection, error) {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	if dev.conn != nil {
+		return dev.conn, nil
+	}
+
+	config := s.ClientConfig
+	if dev.profile != nil && dev.profile.MaxRepetitions != 0 {
+		config.MaxRepetitions = dev.profile.MaxRepetitions
+	}
+	gs, err := snmp.NewWrapper(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := gs.SetAgent(dev.agent); err != nil {
+		return nil, err
+	}
+	if err := gs.Connect(); err != nil {
+		return nil, fmt.Errorf("setting up connection: %w", err)
+	}
+
+	dev.conn = gs
+	return gs, nil
+}
+
 // fieldConvert converts from any type according to the conv specification
 func fieldConvert(conv string, v interface{}) (interface{}, error) {
 	if conv == "" {
@@ -754,7 +937,11 @@ func snmpTable(oid string) (mibName string, oidNum string, oidText string, field
 	var ok bool
 	if stc, ok = snmpTableCaches[oid]; !ok {
 		stc.mibName, stc.oidNum, stc.oidText, stc.fields, stc.err = snmpTableCall(oid)
-		snmpTableCaches[oid] = stc
+		// Failures are not cached, as the MIB of the table may be loaded
+		// later by another plugin or a reload.
+		if stc.err == nil {
+			snmpTableCaches[oid] = stc
+		}
 	}
 
 	snmpTableCachesLock.Unlock()
@@ -762,62 +949,17 @@ func snmpTable(oid string) (mibName string, oidNum string, oidText string, field
 }
 
 func snmpTableCall(oid string) (mibName string, oidNum string, oidText string, fields []Field, err error) {
-	mibName, oidNum, oidText, _, err = SnmpTranslate(oid)
+	mibName, oidNum, oidText, columns, err := snmp.TranslateTable(oid)
 	if err != nil {
 		return "", "", "", nil, fmt.Errorf("translating: %w", err)
 	}
 
 	mibPrefix := mibName + "::"
-	oidFullName := mibPrefix + oidText
-
-	// first attempt to get the table's tags
-	tagOids := map[string]struct{}{}
-	// We have to guess that the "entry" oid is `oid+".1"`. snmptable and snmptranslate don't seem to have a way to provide the info.
-	if out, err := execCmd("snmptranslate", "-Td", oidFullName+".1"); err == nil {
-		scanner := bufio.NewScanner(bytes.NewBuffer(out))
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if !strings.HasPrefix(line, "  INDEX") {
-				continue
-			}
-
-			i := strings.Index(line, "{ ")
-			if i == -1 { // parse error
-				continue
-			}
-			line = line[i+2:]
-			i = strings.Index(line, " }")
-			if i == -1 { // parse error
-				continue
-			}
-			line = line[:i]
-			for _, col := range strings.Split(line, ", ") {
-				tagOids[mibPrefix+col] = struct{}{}
-			}
-		}
-	}
-
-	// this won't actually try to run a query. The `-Ch` will just cause it to dump headers.
-	out, err := execCmd("snmptable", "-Ch", "-Cl", "-c", "public", "127.0.0.1", oidFullName)
-	if err != nil {
-		return "", "", "", nil, fmt.Errorf("getting table columns: %w", err)
-	}
-	scanner := bufio.NewScanner(bytes.NewBuffer(out))
-	scanner.Scan()
-	cols := scanner.Text()
-	if len(cols) == 0 {
-		return "", "", "", nil, fmt.Errorf("could not find any columns in table")
-	}
-	for _, col := range strings.Split(cols, " ") {
-		if len(col) == 0 {
-			continue
-		}
-		_, isTag := tagOids[mibPrefix+col]
-		fields = append(fields, Field{Name: col, Oid: mibPrefix + col, IsTag: isTag})
+	for _, col := range columns {
+		fields = append(fields, Field{Name: col.Name, Oid: mibPrefix + col.Name, IsTag: col.IsIndex})
 	}
 
-	return mibName, oidNum, oidText, fields, err
+	return mibName, oidNum, oidText, fields, nil
 }
 
 type snmpTranslateCache struct {
@@ -841,16 +983,15 @@ func SnmpTranslate(oid string) (mibName string, oidNum string, oidText string, c
 	var stc snmpTranslateCache
 	var ok bool
 	if stc, ok = snmpTranslateCaches[oid]; !ok {
-		// This will result in only one call to snmptranslate running at a time.
-		// We could speed it up by putting a lock in snmpTranslateCache and then
-		// returning it immediately, and multiple callers would then release the
-		// snmpTranslateCachesLock and instead wait on the individual
-		// snmpTranslation.Lock to release. But I don't know that the extra complexity
-		// is worth it. Especially when it would slam the system pretty hard if lots
-		// of lookups are being performed.
-
-		stc.mibName, stc.oidNum, stc.oidText, stc.conversion, stc.err = snmpTranslateCall(oid)
-		snmpTranslateCaches[oid] = stc
+		// This will result in only one translation running at a time. As
+		// the MIBs are loaded in memory, translations are fast enough that
+		// the extra complexity of running them concurrently isn't worth it.
+		stc.mibName, stc.oidNum, stc.oidText, stc.conversion, stc.err = snmp.TranslateOID(oid)
+		// Failures and OIDs of no loaded MIB are not cached, as the MIB of
+		// the OID may be loaded later by another plugin or a reload.
+		if stc.err == nil && stc.mibName != "" {
+			snmpTranslateCaches[oid] = stc
+		}
 	}
 
 	snmpTranslateCachesLock.Unlock()
@@ -879,73 +1020,3 @@ func SnmpTranslateClear() {
 	defer snmpTranslateCachesLock.Unlock()
 	snmpTranslateCaches = map[string]snmpTranslateCache{}
 }
-
-func snmpTranslateCall(oid string) (mibName string, oidNum string, oidText string, conversion string, err error) {
-	var out []byte
-	if strings.ContainsAny(oid, ":abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
-		out, err = execCmd("snmptranslate", "-Td", "-Ob", oid)
-	} else {
-		out, err = execCmd("snmptranslate", "-Td", "-Ob", "-m", "all", oid)
-		if err, ok := err.(*exec.Error); ok && err.Err == exec.ErrNotFound {
-			// Silently discard error if snmptranslate not found and we have a numeric OID.
-			// Meaning we can get by without the lookup.
-			return "", oid, oid, "", nil
-		}
-	}
-	if err != nil {
-		return "", "", "", "", err
-	}
-
-	scanner := bufio.NewScanner(bytes.NewBuffer(out))
-	ok := scanner.Scan()
-	if !ok && scanner.Err() != nil {
-		return "", "", "", "", fmt.Errorf("getting OID text: %w", scanner.Err())
-	}
-
-	oidText = scanner.Text()
-
-	i := strings.Index(oidText, "::")
-	if i == -1 {
-		// was not found in MIB.
-		if bytes.Contains(out, []byte("[TRUNCATED]")) {
-			return "", oid, oid, "", nil
-		}
-		// not truncated, but not fully found. We still need to parse out numeric OID, so keep going
-		oidText = oid
-	} else {
-		mibName = oidText[:i]
-		oidText = oidText[i+2:]
-	}
-
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.HasPrefix(line, "  -- TEXTUAL CONVENTION ") {
-			tc := strings.TrimPrefix(line, "  -- TEXTUAL CONVENTION ")
-			switch tc {
-			case "MacAddress", "PhysAddress":
-				conversion = "hwaddr"
-			case "InetAddressIPv4", "InetAddressIPv6", "InetAddress", "IPSIpAddress":
-				conversion = "ipaddr"
-			}
-		} else if strings.HasPrefix(line, "::= { ") {
-			objs := strings.TrimPrefix(line, "::= { ")
-			objs = strings.TrimSuffix(objs, " }")
-
-			for _, obj := range strings.Split(objs, " ") {
-				if len(obj) == 0 {
-					continue
-				}
-				if i := strings.Index(obj, "("); i != -1 {
-					obj = obj[i+1:]
-					oidNum += "." + obj[:strings.Index(obj, ")")]
-				} else {
-					oidNum += "." + obj
-				}
-			}
-			break
-		}
-	}
-
-	return mibName, oidNum, oidText, conversion, nil
-}
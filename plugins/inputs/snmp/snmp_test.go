@@ -1,10 +1,9 @@
-//go:generate go run -tags generate snmp_mocks_generate.go
 package snmp
 
 import (
 	"fmt"
 	"net"
-	"os/exec"
+	"os"
 	"sync"
 	"testing"
 	"time"
@@ -85,6 +84,15 @@ var tsc = &testSNMPConnection{
 	},
 }
 
+func TestMain(m *testing.M) {
+	err := snmp.LoadMibsFromPath([]string{"testdata/mibs"}, testutil.Logger{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
 func TestSampleConfig(t *testing.T) {
 	conf := inputs.Inputs["snmp"]()
 	err := toml.Unmarshal([]byte(conf.SampleConfig()), conf)
@@ -99,6 +107,7 @@ func TestSampleConfig(t *testing.T) {
 			Community:      "public",
 			MaxRepetitions: 10,
 			Retries:        3,
+			Path:           []string{"/usr/share/snmp/mibs"},
 		},
 		Name: "snmp",
 	}
@@ -136,6 +145,7 @@ func TestFieldInit(t *testing.T) {
 		}
 		assert.Equal(t, txl.expectedOid, f.Oid, "inputOid='%s' inputName='%s' inputConversion='%s'", txl.inputOid, txl.inputName, txl.inputConversion)
 		assert.Equal(t, txl.expectedName, f.Name, "inputOid='%s' inputName='%s' inputConversion='%s'", txl.inputOid, txl.inputName, txl.inputConversion)
+		assert.Equal(t, txl.expectedConversion, f.Conversion, "inputOid='%s' inputName='%s' inputConversion='%s'", txl.inputOid, txl.inputName, txl.inputConversion)
 	}
 }
 
@@ -186,13 +196,20 @@ func TestSnmpInit(t *testing.T) {
 	}, s.Fields[0])
 }
 
-func TestSnmpInit_noTranslate(t *testing.T) {
-	// override execCommand so it returns exec.ErrNotFound
-	defer func(ec func(string, ...string) *exec.Cmd) { execCommand = ec }(execCommand)
-	execCommand = func(_ string, _ ...string) *exec.Cmd {
-		return exec.Command("snmptranslateExecErrNotFound")
+func TestFieldInit_unknown(t *testing.T) {
+	for _, oid := range []string{"TEST::foo", "FOO-MIB::server", "foo.0"} {
+		f := Field{Oid: oid}
+		require.Error(t, f.init(), oid)
 	}
+}
+
+func TestTableInit_notTable(t *testing.T) {
+	tbl := Table{Oid: "TEST::hostname"}
+	require.Error(t, tbl.Init())
+}
 
+func TestSnmpInit_noTranslate(t *testing.T) {
+	// The OIDs are not in the MIBs.
 	s := &Snmp{
 		Fields: []Field{
 			{Oid: ".1.1.1.1", Name: "one", IsTag: true},
@@ -908,6 +925,18 @@ func TestSnmpTranslateCache_hit(t *testing.T) {
 	snmpTranslateCaches = nil
 }
 
+func TestSnmpTranslateCache_error(t *testing.T) {
+	snmpTranslateCaches = nil
+	_, _, _, _, err := SnmpTranslate("NONEXISTENT-MIB::foo")
+	require.Error(t, err)
+	require.Empty(t, snmpTranslateCaches)
+
+	snmpTableCaches = nil
+	_, _, _, _, err = snmpTable("NONEXISTENT-MIB::fooTable")
+	require.Error(t, err)
+	require.Empty(t, snmpTableCaches)
+}
+
 func TestSnmpTableCache_miss(t *testing.T) {
 	snmpTableCaches = nil
 	oid := ".1.0.0.0"
@@ -1,11 +1,8 @@
 package snmp_trap
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"net"
-	"os/exec"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,15 +10,14 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/internal/snmp"
 	"github.com/influxdata/telegraf/plugins/inputs"
 
 	"github.com/soniah/gosnmp"
 )
 
-var defaultTimeout = internal.Duration{Duration: time.Second * 5}
-
 type handler func(*gosnmp.SnmpPacket, *net.UDPAddr)
-type execer func(internal.Duration, string, ...string) ([]byte, error)
+type translator func(oid string) (mibEntry, error)
 
 type mibEntry struct {
 	mibName string
@@ -30,8 +26,9 @@ type mibEntry struct {
 
 type SnmpTrap struct {
 	ServiceAddress string            `toml:"service_address"`
-	Timeout        internal.Duration `toml:"timeout"`
+	Timeout        internal.Duration `toml:"timeout"` // deprecated in 1.18; value is ignored
 	Version        string            `toml:"version"`
+	Path           []string          `toml:"path"`
 
 	// Settings for version 3
 	// Values: "noAuthNoPriv", "authNoPriv", "authPriv"
@@ -56,7 +53,7 @@ type SnmpTrap struct {
 	cacheLock sync.Mutex
 	cache     map[string]mibEntry
 
-	execCmd execer
+	translate translator
 }
 
 var sampleConfig = `
@@ -68,8 +65,11 @@ var sampleConfig = `
   ## 1024.  See README.md for details
   ##
   # service_address = "udp://:162"
-  ## Timeout running snmptranslate command
-  # timeout = "5s"
+  ##
+  ## Paths of the MIB files used to translate OIDs; directories are searched
+  ## recursively.
+  # path = ["/usr/share/snmp/mibs"]
+  ##
   ## Snmp version, defaults to 2c
   # version = "2c"
   ## SNMPv3 authentication and encryption options.
@@ -105,27 +105,16 @@ func init() {
 		return &SnmpTrap{
 			timeFunc:       time.Now,
 			ServiceAddress: "udp://:162",
-			Timeout:        defaultTimeout,
 			Version:        "2c",
+			Path:           snmp.DefaultMibPath,
 		}
 	})
 }
 
-func realExecCmd(Timeout internal.Duration, arg0 string, args ...string) ([]byte, error) {
-	cmd := exec.Command(arg0, args...)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := internal.RunTimeout(cmd, Timeout.Duration)
-	if err != nil {
-		return nil, err
-	}
-	return out.Bytes(), nil
-}
-
 func (s *SnmpTrap) Init() error {
 	s.cache = map[string]mibEntry{}
-	s.execCmd = realExecCmd
-	return nil
+	s.translate = translate
+	return snmp.LoadMibsFromPath(s.Path, s.Log)
 }
 
 func (s *SnmpTrap) Start(acc telegraf.Accumulator) error {
@@ -372,8 +361,8 @@ func (s *SnmpTrap) lookup(oid string) (e mibEntry, err error) {
 	defer s.cacheLock.Unlock()
 	var ok bool
 	if e, ok = s.cache[oid]; !ok {
-		// cache miss.  exec snmptranslate
-		e, err = s.snmptranslate(oid)
+		// cache miss.  translate with the MIBs
+		e, err = s.translate(oid)
 		if err == nil {
 			s.cache[oid] = e
 		}
@@ -394,27 +383,15 @@ func (s *SnmpTrap) load(oid string, e mibEntry) {
 	s.cache[oid] = e
 }
 
-func (s *SnmpTrap) snmptranslate(oid string) (e mibEntry, err error) {
-	var out []byte
-	out, err = s.execCmd(s.Timeout, "snmptranslate", "-Td", "-Ob", "-m", "all", oid)
-
+// translate resolves an OID with the MIBs.  Unlike the snmp input, OIDs of
+// no MIB object are not returned numerically.
+func translate(oid string) (e mibEntry, err error) {
+	e.mibName, _, e.oidText, _, err = snmp.TranslateOID(oid)
 	if err != nil {
 		return e, err
 	}
-
-	scanner := bufio.NewScanner(bytes.NewBuffer(out))
-	ok := scanner.Scan()
-	if err = scanner.Err(); !ok && err != nil {
-		return e, err
-	}
-
-	e.oidText = scanner.Text()
-
-	i := strings.Index(e.oidText, "::")
-	if i == -1 {
+	if e.mibName == "" {
 		return e, fmt.Errorf("not found")
 	}
-	e.mibName = e.oidText[:i]
-	e.oidText = e.oidText[i+2:]
 	return e, nil
 }
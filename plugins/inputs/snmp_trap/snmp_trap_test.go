@@ -11,7 +11,6 @@ import (
 	"github.com/soniah/gosnmp"
 
 	"github.com/influxdata/telegraf"
-	"github.com/influxdata/telegraf/internal"
 	"github.com/influxdata/telegraf/testutil"
 
 	"github.com/stretchr/testify/require"
@@ -36,8 +35,27 @@ func TestLoad(t *testing.T) {
 	require.Equal(t, "coldStart", e.oidText)
 }
 
-func fakeExecCmd(_ internal.Duration, x string, y ...string) ([]byte, error) {
-	return nil, fmt.Errorf("mock " + x + " " + strings.Join(y, " "))
+func TestTranslate(t *testing.T) {
+	s := &SnmpTrap{
+		Path: []string{"testdata/mibs"},
+		Log:  testutil.Logger{},
+	}
+	require.NoError(t, s.Init())
+
+	e, err := s.lookup(".1.3.6.1.6.3.1.1.5.1")
+	require.NoError(t, err)
+	require.Equal(t, mibEntry{"SNMPv2-MIB", "coldStart"}, e)
+
+	e, err = s.lookup(".1.3.6.1.6.3.1.1.4.1.0")
+	require.NoError(t, err)
+	require.Equal(t, mibEntry{"SNMPv2-MIB", "snmpTrapOID.0"}, e)
+
+	_, err = s.lookup(".1.2.3")
+	require.Error(t, err)
+}
+
+func fakeTranslate(oid string) (mibEntry, error) {
+	return mibEntry{}, fmt.Errorf("mock translate " + oid)
 }
 
 func sendTrap(t *testing.T, port uint16, now uint32, trap gosnmp.SnmpTrap, version gosnmp.SnmpVersion, secLevel string, username string, authProto string, authPass string, privProto string, privPass string, contextName string, engineID string) {
@@ -238,7 +256,7 @@ func TestReceiveTrap(t *testing.T) {
 				),
 			},
 		},
-		//Check that we're not running snmptranslate to look up oids
+		//Check that we're not using the MIBs to look up oids
 		//when we shouldn't be.  This sends and receives a valid trap
 		//but metric production should fail because the oids aren't in
 		//the cache and oid lookup is intentionally mocked to fail.
@@ -1290,14 +1308,14 @@ func TestReceiveTrap(t *testing.T) {
 				PrivPassword: tt.privPass,
 			}
 			require.Nil(t, s.Init())
-			// Don't look up oid with snmptranslate.
-			s.execCmd = fakeExecCmd
+			// Don't look up oid with the MIBs.
+			s.translate = fakeTranslate
 			var acc testutil.Accumulator
 			require.Nil(t, s.Start(&acc))
 			defer s.Stop()
 
 			// Preload the cache with the oids we'll use in this test
-			// so snmptranslate and mibs don't need to be installed.
+			// so mibs don't need to be installed.
 			for _, entry := range tt.entries {
 				s.load(entry.oid, entry.e)
 			}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	tlsint "github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/parsers"
@@ -117,6 +118,21 @@ func (ssl *streamSocketListener) read(c net.Conn) {
 		return
 	}
 
+	// The lines of the multiline events of the connection are joined
+	// before being parsed.
+	joiner := ssl.multiline.NewJoiner(func(text string, _ interface{}) {
+		metrics, err := ssl.Parse([]byte(text))
+		if err != nil {
+			ssl.Log.Errorf("Unable to parse incoming line: %s", err.Error())
+			// TODO rate limit
+			return
+		}
+		for _, m := range metrics {
+			ssl.AddMetric(m)
+		}
+	})
+	defer joiner.Stop()
+
 	scnr := bufio.NewScanner(decoder)
 	for {
 		if ssl.ReadTimeout != nil && ssl.ReadTimeout.Duration > 0 {
@@ -126,17 +142,7 @@ func (ssl *streamSocketListener) read(c net.Conn) {
 			break
 		}
 
-		body := scnr.Bytes()
-
-		metrics, err := ssl.Parse(body)
-		if err != nil {
-			ssl.Log.Errorf("Unable to parse incoming line: %s", err.Error())
-			// TODO rate limit
-			continue
-		}
-		for _, m := range metrics {
-			ssl.AddMetric(m)
-		}
+		joiner.Add(scnr.Text(), nil)
 	}
 
 	if err := scnr.Err(); err != nil {
@@ -190,6 +196,7 @@ type SocketListener struct {
 	KeepAlivePeriod *internal.Duration `toml:"keep_alive_period"`
 	SocketMode      string             `toml:"socket_mode"`
 	ContentEncoding string             `toml:"content_encoding"`
+	Multiline       multiline.Config   `toml:"multiline"`
 	tlsint.ServerConfig
 
 	wg sync.WaitGroup
@@ -199,6 +206,8 @@ type SocketListener struct {
 	parsers.Parser
 	telegraf.Accumulator
 	io.Closer
+
+	multiline *multiline.Multiline
 }
 
 func (sl *SocketListener) Description() string {
@@ -264,7 +273,12 @@ func (sl *SocketListener) SampleConfig() string {
   ## Content encoding for message payloads, can be set to "gzip" to or
   ## "identity" to apply no encoding.
   # content_encoding = "identity"
-`
+
+  ## Join the lines of multiline events, such as stack traces, of each
+  ## connection before parsing them.  The pattern is used like in the tail
+  ## input.
+  ## Only applies to stream sockets (e.g. TCP).
+` + multiline.SampleConfig("inputs.socket_listener")
 }
 
 func (sl *SocketListener) Gather(_ telegraf.Accumulator) error {
@@ -277,6 +291,13 @@ func (sl *SocketListener) SetParser(parser parsers.Parser) {
 
 func (sl *SocketListener) Start(acc telegraf.Accumulator) error {
 	sl.Accumulator = acc
+
+	var err error
+	sl.multiline, err = sl.Multiline.NewMultiline()
+	if err != nil {
+		return fmt.Errorf("compiling multiline pattern: %w", err)
+	}
+
 	spl := strings.SplitN(sl.ServiceAddress, "://", 2)
 	if len(spl) != 2 {
 		return fmt.Errorf("invalid service address: %s", sl.ServiceAddress)
@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
+	"github.com/influxdata/telegraf/plugins/parsers/logfmt"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/influxdata/wlog"
 	"github.com/stretchr/testify/assert"
@@ -226,6 +228,36 @@ func TestSocketListenerDecode_udp(t *testing.T) {
 	testSocketListener(t, sl, client)
 }
 
+func TestSocketListenerMultiline_tcp(t *testing.T) {
+	defer testEmptyLog(t)()
+
+	sl := newSocketListener()
+	sl.Log = testutil.Logger{}
+	sl.ServiceAddress = "tcp://127.0.0.1:0"
+	sl.Parser = logfmt.NewParser("log", nil)
+	sl.Multiline = multiline.Config{
+		Pattern:        `^\s`,
+		MatchWhichLine: multiline.Previous,
+	}
+
+	acc := &testutil.Accumulator{}
+	err := sl.Start(acc)
+	require.NoError(t, err)
+	defer sl.Stop()
+
+	client, err := net.Dial("tcp", sl.Closer.(net.Listener).Addr().String())
+	require.NoError(t, err)
+
+	client.Write([]byte("level=error msg=failed\n stack=trace\nlevel=info msg=done\n"))
+	client.Close()
+
+	acc.Wait(2)
+	acc.Lock()
+	defer acc.Unlock()
+	require.Equal(t, map[string]interface{}{"level": "error", "msg": "failed", "stack": "trace"}, acc.Metrics[0].Fields)
+	require.Equal(t, map[string]interface{}{"level": "info", "msg": "done"}, acc.Metrics[1].Fields)
+}
+
 func testSocketListener(t *testing.T, sl *SocketListener, client net.Conn) {
 	mstr12 := []byte("test,foo=bar v=1i 123456789\ntest,foo=baz v=2i 123456790\n")
 	mstr3 := []byte("test,foo=zab v=3i 123456791\n")
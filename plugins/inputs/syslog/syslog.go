@@ -21,6 +21,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
 	framing "github.com/influxdata/telegraf/internal/syslog"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	tlsConfig "github.com/influxdata/telegraf/plugins/common/tls"
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
@@ -39,6 +40,11 @@ type Syslog struct {
 	Trailer         nontransparent.TrailerType
 	BestEffort      bool
 	Separator       string `toml:"sdparam_separator"`
+	MessageFormat   string `toml:"message_format"`
+
+	Multiline multiline.Config `toml:"multiline"`
+
+	Log telegraf.Logger `toml:"-"`
 
 	now      func() time.Time
 	lastTime time.Time
@@ -54,6 +60,19 @@ type Syslog struct {
 	connectionsMu sync.Mutex
 
 	udpListener net.PacketConn
+
+	multiline *multiline.Multiline
+	joiners   map[string]*multiline.Joiner
+	joinersMu sync.Mutex
+
+	parser *multiline.MessageParser
+}
+
+// message is a message waiting for the other lines of its multiline event.
+type message struct {
+	fields map[string]interface{}
+	tags   map[string]string
+	time   time.Time
 }
 
 var sampleConfig = `
@@ -103,11 +122,20 @@ var sampleConfig = `
   ## For each combination a field is created.
   ## Its name is created concatenating identifier, sdparam_separator, and parameter name.
   # sdparam_separator = "_"
+
+  ## Data format of the messages, parsed into fields and tags added to the
+  ## metrics; one of "json" or "logfmt".  Messages which can't be parsed are
+  ## kept as is.  By default the messages are not parsed.
+  # message_format = ""
+
+  ## Join the messages of multiline events, such as stack traces, sent as
+  ## consecutive messages by an application of a host.  The pattern, matched
+  ## against the messages, is used like in the tail input.
 `
 
 // SampleConfig returns sample configuration message
 func (s *Syslog) SampleConfig() string {
-	return sampleConfig
+	return sampleConfig + multiline.SampleConfig("inputs.syslog")
 }
 
 // Description returns the plugin description
@@ -115,6 +143,18 @@ func (s *Syslog) Description() string {
 	return "Accepts syslog messages following RFC5424 format with transports as per RFC5426, RFC5425, or RFC6587"
 }
 
+// Init sets up the joining and the parsing of the messages.
+func (s *Syslog) Init() error {
+	var err error
+	s.multiline, err = s.Multiline.NewMultiline()
+	if err != nil {
+		return fmt.Errorf("compiling multiline pattern: %w", err)
+	}
+
+	s.parser, err = multiline.NewMessageParser(s.MessageFormat, s.Log)
+	return err
+}
+
 // Gather ...
 func (s *Syslog) Gather(_ telegraf.Accumulator) error {
 	return nil
@@ -186,6 +226,13 @@ func (s *Syslog) Stop() {
 		s.Close()
 	}
 	s.wg.Wait()
+
+	s.joinersMu.Lock()
+	for _, j := range s.joiners {
+		j.Stop()
+	}
+	s.joiners = nil
+	s.joinersMu.Unlock()
 }
 
 // getAddressParts returns the address scheme and host
@@ -240,7 +287,7 @@ func (s *Syslog) listenPacket(acc telegraf.Accumulator) {
 
 		message, err := p.Parse(b[:n])
 		if message != nil {
-			acc.AddFields("syslog", fields(message, s), tags(message), s.time())
+			s.add(acc, fields(message, s), tags(message), s.time())
 		}
 		if err != nil {
 			acc.AddError(err)
@@ -354,8 +401,57 @@ func (s *Syslog) store(res syslog.Result, acc telegraf.Accumulator) {
 		acc.AddError(res.Error)
 	}
 	if res.Message != nil {
-		acc.AddFields("syslog", fields(res.Message, s), tags(res.Message), s.time())
+		s.add(acc, fields(res.Message, s), tags(res.Message), s.time())
+	}
+}
+
+// add adds the metric of a message.  With multiline enabled, the messages of
+// an application of a host are joined, and the metric of the first message
+// of an event is added with the message of the event.
+func (s *Syslog) add(acc telegraf.Accumulator, flds map[string]interface{}, tgs map[string]string, t time.Time) {
+	text, ok := flds["message"].(string)
+	if !ok || s.multiline == nil || !s.multiline.IsEnabled() {
+		s.addMetric(acc, flds, tgs, t)
+		return
+	}
+
+	// The messages are added holding the lock of the joiners, so that a
+	// joiner is not removed on timeout while a message is added to it.
+	key := tgs["hostname"] + "/" + tgs["appname"]
+	s.joinersMu.Lock()
+	defer s.joinersMu.Unlock()
+	if s.joiners == nil {
+		s.joiners = make(map[string]*multiline.Joiner)
+	}
+	j, ok := s.joiners[key]
+	if !ok {
+		j = s.multiline.NewJoiner(func(text string, value interface{}) {
+			m := value.(*message)
+			m.fields["message"] = text
+			s.addMetric(acc, m.fields, m.tags, m.time)
+		})
+		j.OnTimeout(func() { s.removeJoiner(key, j) })
+		s.joiners[key] = j
+	}
+	j.Add(text, &message{fields: flds, tags: tgs, time: t})
+}
+
+// removeJoiner removes the joiner of a host and application once its last
+// event was emitted on timeout, so that the joiners of the senders which
+// stopped sending are not kept.
+func (s *Syslog) removeJoiner(key string, j *multiline.Joiner) {
+	s.joinersMu.Lock()
+	defer s.joinersMu.Unlock()
+	if s.joiners[key] == j && j.Idle() {
+		delete(s.joiners, key)
+	}
+}
+
+func (s *Syslog) addMetric(acc telegraf.Accumulator, flds map[string]interface{}, tgs map[string]string, t time.Time) {
+	if text, ok := flds["message"].(string); ok && s.parser != nil {
+		s.parser.Parse(text, flds, tgs)
 	}
+	acc.AddFields("syslog", flds, tgs, t)
 }
 
 func tags(msg syslog.Message) map[string]string {
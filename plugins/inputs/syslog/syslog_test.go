@@ -9,8 +9,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/influxdata/go-syslog/v2"
+	"github.com/influxdata/go-syslog/v2/rfc5424"
 	"github.com/stretchr/testify/require"
 
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	"github.com/influxdata/telegraf/testutil"
 )
 
@@ -71,3 +76,159 @@ func TestAddress(t *testing.T) {
 	require.Equal(t, "localhost:6514", rec.Address)
 	rec.Stop()
 }
+
+func newTestSyslog(t *testing.T, s *Syslog) *Syslog {
+	s.now = func() time.Time { return defaultTime }
+	s.Separator = "_"
+	s.Log = testutil.Logger{}
+	require.NoError(t, s.Init())
+	return s
+}
+
+func storeMessage(t *testing.T, s *Syslog, acc *testutil.Accumulator, raw string) {
+	msg, err := rfc5424.NewParser().Parse([]byte(raw))
+	require.NoError(t, err)
+	s.store(syslog.Result{Message: msg}, acc)
+}
+
+func TestMultiline(t *testing.T) {
+	s := newTestSyslog(t, &Syslog{
+		Multiline: multiline.Config{
+			Pattern:        `^\s`,
+			MatchWhichLine: multiline.Previous,
+		},
+	})
+	acc := &testutil.Accumulator{}
+
+	storeMessage(t, s, acc, "<11>1 - host1 app - - - Exception in thread main")
+	storeMessage(t, s, acc, "<11>1 - host2 app - - - other")
+	storeMessage(t, s, acc, "<11>1 - host1 app - - - \tat Foo.main")
+	storeMessage(t, s, acc, "<11>1 - host1 app - - - next")
+	s.Stop()
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"syslog",
+			map[string]string{
+				"severity": "err",
+				"facility": "user",
+				"hostname": "host1",
+				"appname":  "app",
+			},
+			map[string]interface{}{
+				"version":       uint16(1),
+				"severity_code": 3,
+				"facility_code": 1,
+				"message":       "Exception in thread main\tat Foo.main",
+			},
+			defaultTime,
+		),
+		testutil.MustMetric(
+			"syslog",
+			map[string]string{
+				"severity": "err",
+				"facility": "user",
+				"hostname": "host1",
+				"appname":  "app",
+			},
+			map[string]interface{}{
+				"version":       uint16(1),
+				"severity_code": 3,
+				"facility_code": 1,
+				"message":       "next",
+			},
+			defaultTime,
+		),
+		testutil.MustMetric(
+			"syslog",
+			map[string]string{
+				"severity": "err",
+				"facility": "user",
+				"hostname": "host2",
+				"appname":  "app",
+			},
+			map[string]interface{}{
+				"version":       uint16(1),
+				"severity_code": 3,
+				"facility_code": 1,
+				"message":       "other",
+			},
+			defaultTime,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(),
+		testutil.SortMetrics(), testutil.IgnoreTime())
+}
+
+func TestMultilineRemovesIdleJoiners(t *testing.T) {
+	s := newTestSyslog(t, &Syslog{
+		Multiline: multiline.Config{
+			Pattern:        `^\s`,
+			MatchWhichLine: multiline.Previous,
+			Timeout:        &internal.Duration{Duration: 10 * time.Millisecond},
+		},
+	})
+	defer s.Stop()
+	acc := &testutil.Accumulator{}
+
+	storeMessage(t, s, acc, "<11>1 - host1 app - - - first")
+	storeMessage(t, s, acc, "<11>1 - host2 app - - - second")
+
+	acc.Wait(2)
+	require.Eventually(t, func() bool {
+		s.joinersMu.Lock()
+		defer s.joinersMu.Unlock()
+		return len(s.joiners) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMessageFormat(t *testing.T) {
+	s := newTestSyslog(t, &Syslog{MessageFormat: "logfmt"})
+	acc := &testutil.Accumulator{}
+
+	storeMessage(t, s, acc, `<11>1 - host1 app - - - method=GET status=200 version=2 path="/v1/ok"`)
+	storeMessage(t, s, acc, `<11>1 - host1 app - - - not="closed`)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"syslog",
+			map[string]string{
+				"severity": "err",
+				"facility": "user",
+				"hostname": "host1",
+				"appname":  "app",
+			},
+			map[string]interface{}{
+				"version":       uint16(1),
+				"severity_code": 3,
+				"facility_code": 1,
+				"message":       `method=GET status=200 version=2 path="/v1/ok"`,
+				"method":        "GET",
+				"status":        int64(200),
+				"path":          "/v1/ok",
+			},
+			defaultTime,
+		),
+		testutil.MustMetric(
+			"syslog",
+			map[string]string{
+				"severity": "err",
+				"facility": "user",
+				"hostname": "host1",
+				"appname":  "app",
+			},
+			map[string]interface{}{
+				"version":       uint16(1),
+				"severity_code": 3,
+				"facility_code": 1,
+				"message":       `not="closed`,
+			},
+			defaultTime,
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics(),
+		testutil.IgnoreTime())
+
+	s = &Syslog{MessageFormat: "xml"}
+	require.Error(t, s.Init())
+}
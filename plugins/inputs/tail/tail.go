@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
@@ -16,6 +17,7 @@ import (
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal/globpath"
 	"github.com/influxdata/telegraf/plugins/common/encoding"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	"github.com/influxdata/telegraf/plugins/inputs"
 	"github.com/influxdata/telegraf/plugins/parsers"
 	"github.com/influxdata/telegraf/plugins/parsers/csv"
@@ -42,7 +44,10 @@ type Tail struct {
 	MaxUndeliveredLines int      `toml:"max_undelivered_lines"`
 	CharacterEncoding   string   `toml:"character_encoding"`
 
-	Log        telegraf.Logger `toml:"-"`
+	Log telegraf.Logger `toml:"-"`
+
+	// mu guards the tailers and offsets, read by GetState while running.
+	mu         sync.Mutex
 	tailers    map[string]*tail.Tail
 	offsets    map[string]int64
 	parserFunc parsers.ParserFunc
@@ -50,8 +55,8 @@ type Tail struct {
 
 	acc telegraf.TrackingAccumulator
 
-	MultilineConfig MultilineConfig `toml:"multiline"`
-	multiline       *Multiline
+	MultilineConfig multiline.Config `toml:"multiline"`
+	multiline       *multiline.Multiline
 
 	ctx     context.Context
 	cancel  context.CancelFunc
@@ -157,6 +162,8 @@ func (t *Tail) Init() error {
 }
 
 func (t *Tail) Gather(acc telegraf.Accumulator) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	return t.tailNewFiles(true)
 }
 
@@ -185,6 +192,9 @@ func (t *Tail) Start(acc telegraf.Accumulator) error {
 		return err
 	}
 
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	t.tailers = make(map[string]*tail.Tail)
 
 	err = t.tailNewFiles(t.FromBeginning)
@@ -407,12 +417,16 @@ func (t *Tail) receiver(parser parsers.Parser, tailer *tail.Tail) {
 }
 
 func (t *Tail) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	for _, tailer := range t.tailers {
 		if !t.Pipe && !t.FromBeginning {
 			// store offset for resume
 			offset, err := tailer.Tell()
 			if err == nil {
 				t.Log.Debugf("Recording offset %d for %q", offset, tailer.Filename)
+				t.offsets[tailer.Filename] = offset
 			} else {
 				t.Log.Errorf("Recording offset for %q: %s", tailer.Filename, err.Error())
 			}
@@ -423,6 +437,8 @@ func (t *Tail) Stop() {
 		}
 	}
 
+	t.tailers = nil
+
 	t.cancel()
 	t.wg.Wait()
 
@@ -434,6 +450,46 @@ func (t *Tail) Stop() {
 	offsetsMutex.Unlock()
 }
 
+// GetState returns the offsets of the files, read from the tailed files
+// while running.
+func (t *Tail) GetState() interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	offsets := make(map[string]int64, len(t.offsets)+len(t.tailers))
+	for file, offset := range t.offsets {
+		offsets[file] = offset
+	}
+	if t.Pipe || t.FromBeginning {
+		return offsets
+	}
+	for file, tailer := range t.tailers {
+		if offset, err := tailer.Tell(); err == nil {
+			offsets[file] = offset
+		}
+	}
+	return offsets
+}
+
+// SetState restores the offsets of the files, from which they are read when
+// the plugin starts.
+func (t *Tail) SetState(state interface{}) error {
+	offsets, ok := state.(map[string]int64)
+	if !ok {
+		return fmt.Errorf("invalid state of type %T", state)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.offsets == nil {
+		t.offsets = make(map[string]int64, len(offsets))
+	}
+	for file, offset := range offsets {
+		t.offsets[file] = offset
+	}
+	return nil
+}
+
 func (t *Tail) SetParserFunc(fn parsers.ParserFunc) {
 	t.parserFunc = fn
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/multiline"
 	"github.com/influxdata/telegraf/plugins/parsers"
 	"github.com/influxdata/telegraf/plugins/parsers/csv"
 	"github.com/influxdata/telegraf/plugins/parsers/influx"
@@ -103,9 +104,9 @@ func TestGrokParseLogFilesWithMultiline(t *testing.T) {
 	tt.Log = testutil.Logger{}
 	tt.FromBeginning = true
 	tt.Files = []string{filepath.Join(testdataDir, "test_multiline.log")}
-	tt.MultilineConfig = MultilineConfig{
+	tt.MultilineConfig = multiline.Config{
 		Pattern:        `^[^\[]`,
-		MatchWhichLine: Previous,
+		MatchWhichLine: multiline.Previous,
 		InvertMatch:    false,
 		Timeout:        &internal.Duration{Duration: duration},
 	}
@@ -166,9 +167,9 @@ func TestGrokParseLogFilesWithMultilineTimeout(t *testing.T) {
 	tt.Log = testutil.Logger{}
 	tt.FromBeginning = true
 	tt.Files = []string{tmpfile.Name()}
-	tt.MultilineConfig = MultilineConfig{
+	tt.MultilineConfig = multiline.Config{
 		Pattern:        `^[^\[]`,
-		MatchWhichLine: Previous,
+		MatchWhichLine: multiline.Previous,
 		InvertMatch:    false,
 		Timeout:        &internal.Duration{Duration: duration},
 	}
@@ -219,9 +220,9 @@ func TestGrokParseLogFilesWithMultilineTailerCloseFlushesMultilineBuffer(t *test
 	tt.Log = testutil.Logger{}
 	tt.FromBeginning = true
 	tt.Files = []string{filepath.Join(testdataDir, "test_multiline.log")}
-	tt.MultilineConfig = MultilineConfig{
+	tt.MultilineConfig = multiline.Config{
 		Pattern:        `^[^\[]`,
-		MatchWhichLine: Previous,
+		MatchWhichLine: multiline.Previous,
 		InvertMatch:    false,
 		Timeout:        &internal.Duration{Duration: duration},
 	}
@@ -563,6 +564,53 @@ func TestTailEOF(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTailState(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "")
+	require.NoError(t, err)
+	defer os.Remove(tmpfile.Name())
+	defer tmpfile.Close()
+	_, err = tmpfile.WriteString("cpu usage_idle=100\n")
+	require.NoError(t, err)
+
+	tt := NewTail()
+	tt.Log = testutil.Logger{}
+	tt.Files = []string{tmpfile.Name()}
+	tt.SetParserFunc(parsers.NewInfluxParser)
+	require.NoError(t, tt.Init())
+	require.NoError(t, tt.SetState(map[string]int64{tmpfile.Name(): 0}))
+
+	acc := testutil.Accumulator{}
+	require.NoError(t, tt.Start(&acc))
+	acc.Wait(1)
+	require.Equal(t, map[string]int64{tmpfile.Name(): 19}, tt.GetState())
+	tt.Stop()
+
+	state := tt.GetState()
+	require.Equal(t, map[string]int64{tmpfile.Name(): 19}, state)
+
+	_, err = tmpfile.WriteString("cpu2 usage_idle=200\n")
+	require.NoError(t, err)
+
+	// A new instance continues from the offset of the saved state.
+	tt = NewTail()
+	tt.Log = testutil.Logger{}
+	tt.Files = []string{tmpfile.Name()}
+	tt.SetParserFunc(parsers.NewInfluxParser)
+	require.NoError(t, tt.Init())
+	require.NoError(t, tt.SetState(state))
+
+	acc = testutil.Accumulator{}
+	require.NoError(t, tt.Start(&acc))
+	defer tt.Stop()
+	acc.Wait(1)
+
+	require.Len(t, acc.Metrics, 1)
+	acc.AssertContainsFields(t, "cpu2",
+		map[string]interface{}{
+			"usage_idle": float64(200),
+		})
+}
+
 func getTestdataDir() string {
 	dir, err := os.Getwd()
 	if err != nil {
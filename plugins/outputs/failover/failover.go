@@ -0,0 +1,159 @@
+package failover
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/plugins/outputs"
+)
+
+const sampleConfig = `
+  ## Each batch is written to the first output that is not backing off after
+  ## a failure, in the order they are defined, moving on to the next output
+  ## only when a write fails.  Enable the circuit breaker of the outputs to
+  ## skip a failing output until it recovers:
+  ##   circuit_breaker_threshold = 3
+  ##
+  ## Metric filtering and buffering are configured on the failover output;
+  ## the outputs are written the batches of its buffer.
+
+  [[outputs.failover.outputs.influxdb]]
+    alias = "primary"
+    urls = ["http://primary.example.com:8086"]
+    circuit_breaker_threshold = 3
+
+  [[outputs.failover.outputs.influxdb]]
+    alias = "secondary"
+    urls = ["http://secondary.example.com:8086"]
+`
+
+// Failover writes each batch of metrics to the first healthy of its outputs.
+type Failover struct {
+	Log telegraf.Logger `toml:"-"`
+
+	outputs   []*models.RunningOutput
+	connected []bool
+	active    int
+}
+
+func (f *Failover) SampleConfig() string {
+	return sampleConfig
+}
+
+func (f *Failover) Description() string {
+	return "Write metrics to the first healthy of a list of outputs"
+}
+
+// AddOutput adds an output to fail over to, after the outputs already added.
+func (f *Failover) AddOutput(output *models.RunningOutput) {
+	f.outputs = append(f.outputs, output)
+	f.connected = append(f.connected, false)
+}
+
+func (f *Failover) Init() error {
+	if len(f.outputs) == 0 {
+		return errors.New("no outputs configured")
+	}
+
+	for _, output := range f.outputs {
+		if _, ok := output.Output.(telegraf.AggregatingOutput); ok {
+			return fmt.Errorf("aggregating output %s cannot be failed over to", output.LogName())
+		}
+		// The outputs are written the batches of the failover output, which
+		// are already filtered.
+		if output.Config.Filter.IsActive() {
+			return fmt.Errorf("metric filtering cannot be set on output %s, set it on the failover output instead", output.LogName())
+		}
+		if err := output.Init(); err != nil {
+			return fmt.Errorf("could not initialize output %s: %v", output.LogName(), err)
+		}
+	}
+	return nil
+}
+
+// Connect connects the outputs, succeeding if any output connects.  Outputs
+// that fail to connect are connected again before they are written to.
+func (f *Failover) Connect() error {
+	var lastErr error
+	for i, output := range f.outputs {
+		if err := f.connect(i); err != nil {
+			f.Log.Errorf("Failed to connect to %s: %v", output.LogName(), err)
+			lastErr = err
+		}
+	}
+
+	for _, connected := range f.connected {
+		if connected {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+func (f *Failover) connect(i int) error {
+	if f.connected[i] {
+		return nil
+	}
+	if err := f.outputs[i].Output.Connect(); err != nil {
+		return err
+	}
+	f.connected[i] = true
+	return nil
+}
+
+func (f *Failover) Close() error {
+	for i, output := range f.outputs {
+		if f.connected[i] {
+			output.Close()
+			f.connected[i] = false
+		}
+	}
+	return nil
+}
+
+func (f *Failover) Stop() {
+	for _, output := range f.outputs {
+		output.Stop()
+	}
+}
+
+// Write writes the metrics to the first output that is not backing off, and
+// moves on to the next output when a write fails.
+func (f *Failover) Write(metrics []telegraf.Metric) error {
+	var lastErr error
+	for i, output := range f.outputs {
+		if !output.WriteAllowed() {
+			continue
+		}
+
+		err := f.connect(i)
+		if err == nil {
+			err = output.WriteMetrics(metrics)
+		}
+		if err != nil {
+			f.Log.Errorf("Writing to %s failed: %v", output.LogName(), err)
+			lastErr = err
+			continue
+		}
+
+		if i != f.active {
+			f.Log.Infof("Switching from %s to %s",
+				f.outputs[f.active].LogName(), output.LogName())
+			f.active = i
+		}
+		return nil
+	}
+
+	if lastErr == nil {
+		return errors.New("all outputs are backing off after failures")
+	}
+	return fmt.Errorf("all outputs failed, last error: %v", lastErr)
+}
+
+func init() {
+	outputs.Add("failover", func() telegraf.Output {
+		return &Failover{}
+	})
+}
@@ -0,0 +1,128 @@
+package failover
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type mockOutput struct {
+	connectErr error
+	writeErr   error
+	written    int
+}
+
+func (m *mockOutput) SampleConfig() string { return "" }
+func (m *mockOutput) Description() string  { return "" }
+func (m *mockOutput) Connect() error       { return m.connectErr }
+func (m *mockOutput) Close() error         { return nil }
+func (m *mockOutput) Write(metrics []telegraf.Metric) error {
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	m.written += len(metrics)
+	return nil
+}
+
+func newFailover(t *testing.T, threshold int, outputs ...*mockOutput) *Failover {
+	f := &Failover{Log: testutil.Logger{}}
+	for _, output := range outputs {
+		f.AddOutput(models.NewRunningOutput("mock", output, &models.OutputConfig{
+			Name:                    "mock",
+			CircuitBreakerThreshold: threshold,
+			CircuitBreakerTimeout:   time.Hour,
+		}, 0, 0))
+	}
+	require.NoError(t, f.Init())
+	require.NoError(t, f.Connect())
+	return f
+}
+
+func TestWrite(t *testing.T) {
+	primary := &mockOutput{}
+	secondary := &mockOutput{}
+	f := newFailover(t, 0, primary, secondary)
+	metrics := []telegraf.Metric{testutil.TestMetric(1)}
+
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 1, primary.written)
+	require.Equal(t, 0, secondary.written)
+
+	// Fail over to the secondary only after the primary fails.
+	primary.writeErr = errors.New("connection refused")
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 1, primary.written)
+	require.Equal(t, 1, secondary.written)
+
+	// Fail back once the primary recovers.
+	primary.writeErr = nil
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 2, primary.written)
+	require.Equal(t, 1, secondary.written)
+
+	primary.writeErr = errors.New("connection refused")
+	secondary.writeErr = errors.New("connection refused")
+	require.Error(t, f.Write(metrics))
+}
+
+func TestWriteCircuitBreaker(t *testing.T) {
+	primary := &mockOutput{writeErr: errors.New("connection refused")}
+	secondary := &mockOutput{}
+	f := newFailover(t, 1, primary, secondary)
+	metrics := []telegraf.Metric{testutil.TestMetric(1)}
+
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 1, secondary.written)
+
+	// The primary is skipped while its circuit breaker is open.
+	primary.writeErr = nil
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 0, primary.written)
+	require.Equal(t, 2, secondary.written)
+
+	secondary.writeErr = errors.New("connection refused")
+	require.Error(t, f.Write(metrics))
+}
+
+func TestConnect(t *testing.T) {
+	primary := &mockOutput{connectErr: errors.New("connection refused")}
+	secondary := &mockOutput{}
+	f := newFailover(t, 0, primary, secondary)
+	metrics := []telegraf.Metric{testutil.TestMetric(1)}
+
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 1, secondary.written)
+
+	// The primary is connected before it is written to.
+	primary.connectErr = nil
+	require.NoError(t, f.Write(metrics))
+	require.Equal(t, 1, primary.written)
+
+	f = &Failover{Log: testutil.Logger{}}
+	f.AddOutput(models.NewRunningOutput("mock", &mockOutput{connectErr: errors.New("connection refused")},
+		&models.OutputConfig{Name: "mock"}, 0, 0))
+	require.NoError(t, f.Init())
+	require.Error(t, f.Connect())
+}
+
+func TestInitNoOutputs(t *testing.T) {
+	f := &Failover{Log: testutil.Logger{}}
+	require.Error(t, f.Init())
+}
+
+func TestInitOutputFilter(t *testing.T) {
+	filter := models.Filter{NamePass: []string{"cpu"}}
+	require.NoError(t, filter.Compile())
+
+	f := &Failover{Log: testutil.Logger{}}
+	f.AddOutput(models.NewRunningOutput("mock", &mockOutput{}, &models.OutputConfig{
+		Name:   "mock",
+		Filter: filter,
+	}, 0, 0))
+	require.Error(t, f.Init())
+}
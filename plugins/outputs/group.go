@@ -0,0 +1,21 @@
+package outputs
+
+import (
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/models"
+)
+
+// Group is an output that writes to child outputs.  The children are
+// configured as sub-tables of the group, for example
+// [[outputs.failover.outputs.influxdb]], and are added in the order they
+// appear in the configuration.
+type Group interface {
+	telegraf.Output
+
+	// AddOutput adds a child output to the group.
+	AddOutput(output *models.RunningOutput)
+
+	// Stop interrupts the writes of the child outputs waiting for their rate
+	// limits, it is called when the group is stopped.
+	Stop()
+}
@@ -0,0 +1,280 @@
+package opentelemetry
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/telegraf"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// converter converts telegraf metrics to OTLP metrics, the reverse of the
+// opentelemetry input.  Metrics in the format of the prometheus input with
+// metric_version = 1 keep their name, and histograms and summaries their
+// buckets and quantiles; other fields are named <measurement>_<field>.
+type converter struct {
+	// startTime is the start time of cumulative sums and histograms.
+	startTime uint64
+
+	metrics []*metricspb.Metric
+	index   map[metricKey]*metricspb.Metric
+}
+
+type metricKey struct {
+	name string
+	kind int
+}
+
+// The kinds of OTLP metric, to keep a metric of each kind per name.
+const (
+	kindGauge = iota
+	kindSum
+	kindHistogram
+	kindSummary
+)
+
+func newConverter(startTime uint64) *converter {
+	return &converter{
+		startTime: startTime,
+		index:     make(map[metricKey]*metricspb.Metric),
+	}
+}
+
+func (c *converter) add(m telegraf.Metric) {
+	ts := uint64(m.Time().UnixNano())
+	attributes := tagAttributes(m.TagList())
+
+	switch m.Type() {
+	case telegraf.Histogram:
+		if dp, ok := histogramPoint(m.FieldList()); ok {
+			dp.Attributes = attributes
+			dp.StartTimeUnixNano = c.startTime
+			dp.TimeUnixNano = ts
+			h := c.metric(m.Name(), kindHistogram).GetHistogram()
+			h.DataPoints = append(h.DataPoints, dp)
+			return
+		}
+	case telegraf.Summary:
+		if dp, ok := summaryPoint(m.FieldList()); ok {
+			dp.Attributes = attributes
+			dp.StartTimeUnixNano = c.startTime
+			dp.TimeUnixNano = ts
+			s := c.metric(m.Name(), kindSummary).GetSummary()
+			s.DataPoints = append(s.DataPoints, dp)
+			return
+		}
+	}
+
+	for _, field := range m.FieldList() {
+		dp := numberPoint(field.Value)
+		if dp == nil {
+			continue
+		}
+		dp.Attributes = attributes
+		dp.TimeUnixNano = ts
+
+		name := metricName(m.Name(), field.Key)
+		if m.Type() == telegraf.Counter {
+			dp.StartTimeUnixNano = c.startTime
+			s := c.metric(name, kindSum).GetSum()
+			s.DataPoints = append(s.DataPoints, dp)
+			continue
+		}
+		g := c.metric(name, kindGauge).GetGauge()
+		g.DataPoints = append(g.DataPoints, dp)
+	}
+}
+
+// metric returns the metric of the name and kind, adding it if needed.
+func (c *converter) metric(name string, kind int) *metricspb.Metric {
+	key := metricKey{name: name, kind: kind}
+	if m, ok := c.index[key]; ok {
+		return m
+	}
+
+	m := &metricspb.Metric{Name: name}
+	switch kind {
+	case kindGauge:
+		m.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{}}
+	case kindSum:
+		m.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			IsMonotonic:            true,
+		}}
+	case kindHistogram:
+		m.Data = &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+		}}
+	case kindSummary:
+		m.Data = &metricspb.Metric_Summary{Summary: &metricspb.Summary{}}
+	}
+	c.index[key] = m
+	c.metrics = append(c.metrics, m)
+	return m
+}
+
+// metricName returns the name of the OTLP metric of a field.  The value
+// fields of the prometheus input are not part of the name, and with
+// metric_version = 2 the field is the name.
+func metricName(measurement, field string) string {
+	switch {
+	case measurement == "prometheus":
+		return field
+	case field == "value" || field == "gauge" || field == "counter":
+		return measurement
+	default:
+		return measurement + "_" + field
+	}
+}
+
+func numberPoint(value interface{}) *metricspb.NumberDataPoint {
+	dp := &metricspb.NumberDataPoint{}
+	switch v := value.(type) {
+	case int64:
+		dp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: v}
+	case uint64:
+		if v > math.MaxInt64 {
+			dp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: float64(v)}
+		} else {
+			dp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: int64(v)}
+		}
+	case float64:
+		dp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: v}
+	case bool:
+		var i int64
+		if v {
+			i = 1
+		}
+		dp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: i}
+	default:
+		return nil
+	}
+	return dp
+}
+
+func floatValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// histogramPoint returns the data point of a histogram with count and sum
+// fields, and a field with the cumulative count of each bucket named after
+// its upper bound.
+func histogramPoint(fields []*telegraf.Field) (*metricspb.HistogramDataPoint, bool) {
+	type bucket struct {
+		bound float64
+		count uint64
+	}
+
+	dp := &metricspb.HistogramDataPoint{}
+	var buckets []bucket
+	var hasCount bool
+	for _, field := range fields {
+		value, ok := floatValue(field.Value)
+		if !ok {
+			return nil, false
+		}
+		switch field.Key {
+		case "count":
+			dp.Count = uint64(value)
+			hasCount = true
+		case "sum":
+			dp.Sum = value
+		default:
+			bound, err := strconv.ParseFloat(field.Key, 64)
+			if err != nil {
+				return nil, false
+			}
+			buckets = append(buckets, bucket{bound: bound, count: uint64(value)})
+		}
+	}
+	if !hasCount {
+		return nil, false
+	}
+
+	// OTLP buckets are not cumulative, and the last bucket has no upper bound.
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].bound < buckets[j].bound })
+	var previous uint64
+	for _, b := range buckets {
+		if b.count < previous {
+			return nil, false
+		}
+		if !math.IsInf(b.bound, 1) {
+			dp.ExplicitBounds = append(dp.ExplicitBounds, b.bound)
+		}
+		dp.BucketCounts = append(dp.BucketCounts, b.count-previous)
+		previous = b.count
+	}
+	if len(buckets) > 0 && !math.IsInf(buckets[len(buckets)-1].bound, 1) {
+		var rest uint64
+		if dp.Count > previous {
+			rest = dp.Count - previous
+		}
+		dp.BucketCounts = append(dp.BucketCounts, rest)
+	}
+	return dp, true
+}
+
+// summaryPoint returns the data point of a summary with count and sum fields,
+// and a field with the value of each quantile named after the quantile.
+func summaryPoint(fields []*telegraf.Field) (*metricspb.SummaryDataPoint, bool) {
+	dp := &metricspb.SummaryDataPoint{}
+	var hasCount bool
+	for _, field := range fields {
+		value, ok := floatValue(field.Value)
+		if !ok {
+			return nil, false
+		}
+		switch field.Key {
+		case "count":
+			dp.Count = uint64(value)
+			hasCount = true
+		case "sum":
+			dp.Sum = value
+		default:
+			quantile, err := strconv.ParseFloat(field.Key, 64)
+			if err != nil {
+				return nil, false
+			}
+			dp.QuantileValues = append(dp.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{
+				Quantile: quantile,
+				Value:    value,
+			})
+		}
+	}
+	if !hasCount {
+		return nil, false
+	}
+
+	sort.Slice(dp.QuantileValues, func(i, j int) bool {
+		return dp.QuantileValues[i].Quantile < dp.QuantileValues[j].Quantile
+	})
+	return dp, true
+}
+
+func tagAttributes(tags []*telegraf.Tag) []*commonpb.KeyValue {
+	attributes := make([]*commonpb.KeyValue, 0, len(tags))
+	for _, tag := range tags {
+		attributes = append(attributes, stringAttribute(tag.Key, tag.Value))
+	}
+	return attributes
+}
+
+func stringAttribute(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key,
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: value},
+		},
+	}
+}
@@ -0,0 +1,188 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultServiceAddress = "localhost:4317"
+	defaultTimeout        = 5 * time.Second
+
+	// libraryName is the name of the instrumentation library of the metrics.
+	libraryName = "telegraf"
+)
+
+const sampleConfig = `
+  ## Address and port of the OTLP gRPC receiver, such as an OpenTelemetry
+  ## collector.
+  # service_address = "localhost:4317"
+
+  ## Timeout of a write.
+  # timeout = "5s"
+
+  ## Compression of the requests, "gzip" or "none".
+  # compression = "none"
+
+  ## Attributes of the resource of the metrics, such as the service name.
+  # [outputs.opentelemetry.resource_attributes]
+  #   "service.name" = "telegraf"
+
+  ## Additional gRPC request metadata, such as authentication headers.
+  # [outputs.opentelemetry.headers]
+  #   key1 = "value1"
+
+  ## Optional TLS Config
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification
+  # insecure_skip_verify = false
+`
+
+// OpenTelemetry writes metrics to an OTLP receiver over gRPC.
+type OpenTelemetry struct {
+	ServiceAddress     string            `toml:"service_address"`
+	Timeout            internal.Duration `toml:"timeout"`
+	Compression        string            `toml:"compression"`
+	ResourceAttributes map[string]string `toml:"resource_attributes"`
+	Headers            map[string]string `toml:"headers"`
+	tls.ClientConfig
+
+	Log telegraf.Logger `toml:"-"`
+
+	conn      *grpc.ClientConn
+	client    collectorpb.MetricsServiceClient
+	callOpts  []grpc.CallOption
+	resource  *resourcepb.Resource
+	startTime uint64
+}
+
+func (o *OpenTelemetry) SampleConfig() string {
+	return sampleConfig
+}
+
+func (o *OpenTelemetry) Description() string {
+	return "Send metrics to an OpenTelemetry receiver over the OTLP protocol"
+}
+
+func (o *OpenTelemetry) Init() error {
+	if o.ServiceAddress == "" {
+		o.ServiceAddress = defaultServiceAddress
+	}
+	if o.Timeout.Duration == 0 {
+		o.Timeout.Duration = defaultTimeout
+	}
+
+	switch o.Compression {
+	case "", "none":
+	case "gzip":
+		o.callOpts = append(o.callOpts, grpc.UseCompressor(gzip.Name))
+	default:
+		return fmt.Errorf("unknown compression %q", o.Compression)
+	}
+
+	keys := make([]string, 0, len(o.ResourceAttributes))
+	for key := range o.ResourceAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	o.resource = &resourcepb.Resource{}
+	for _, key := range keys {
+		o.resource.Attributes = append(o.resource.Attributes, stringAttribute(key, o.ResourceAttributes[key]))
+	}
+	return nil
+}
+
+func (o *OpenTelemetry) Connect() error {
+	tlsConfig, err := o.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if tlsConfig != nil {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+
+	// The connection is established in the background, and when writing.
+	conn, err := grpc.Dial(o.ServiceAddress, opts...)
+	if err != nil {
+		return err
+	}
+	o.conn = conn
+	o.client = collectorpb.NewMetricsServiceClient(conn)
+	o.startTime = uint64(time.Now().UnixNano())
+	return nil
+}
+
+func (o *OpenTelemetry) Close() error {
+	if o.conn == nil {
+		return nil
+	}
+	err := o.conn.Close()
+	o.conn = nil
+	return err
+}
+
+func (o *OpenTelemetry) Write(metrics []telegraf.Metric) error {
+	c := newConverter(o.startTime)
+	for _, m := range metrics {
+		c.add(m)
+	}
+	if len(c.metrics) == 0 {
+		return nil
+	}
+
+	req := &collectorpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource: o.resource,
+				InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{
+					{
+						InstrumentationLibrary: &commonpb.InstrumentationLibrary{
+							Name:    libraryName,
+							Version: internal.Version(),
+						},
+						Metrics: c.metrics,
+					},
+				},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout.Duration)
+	defer cancel()
+	if len(o.Headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(o.Headers))
+	}
+
+	if _, err := o.client.Export(ctx, req, o.callOpts...); err != nil {
+		return fmt.Errorf("exporting metrics failed: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	outputs.Add("opentelemetry", func() telegraf.Output {
+		return &OpenTelemetry{
+			ServiceAddress: defaultServiceAddress,
+			Timeout:        internal.Duration{Duration: defaultTimeout},
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package opentelemetry
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/inputs/opentelemetry"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// receiver is an OTLP receiver keeping the requests it is sent.
+type receiver struct {
+	collectorpb.UnimplementedMetricsServiceServer
+
+	sync.Mutex
+	requests []*collectorpb.ExportMetricsServiceRequest
+	metadata []metadata.MD
+}
+
+func (r *receiver) Export(ctx context.Context, req *collectorpb.ExportMetricsServiceRequest) (*collectorpb.ExportMetricsServiceResponse, error) {
+	r.Lock()
+	defer r.Unlock()
+	md, _ := metadata.FromIncomingContext(ctx)
+	r.requests = append(r.requests, req)
+	r.metadata = append(r.metadata, md)
+	return &collectorpb.ExportMetricsServiceResponse{}, nil
+}
+
+// freeAddress returns the address of a free local port.
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func startReceiver(t *testing.T) (*receiver, string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	r := &receiver{}
+	server := grpc.NewServer()
+	collectorpb.RegisterMetricsServiceServer(server, r)
+	go server.Serve(listener)
+	return r, listener.Addr().String(), server.Stop
+}
+
+func TestConvert(t *testing.T) {
+	c := newConverter(1)
+	c.add(testutil.MustMetric("queue_length",
+		map[string]string{"queue": "orders"},
+		map[string]interface{}{"gauge": 42.0},
+		time.Unix(0, 10),
+		telegraf.Gauge,
+	))
+	c.add(testutil.MustMetric("requests",
+		map[string]string{},
+		map[string]interface{}{"counter": int64(7)},
+		time.Unix(0, 10),
+		telegraf.Counter,
+	))
+	c.add(testutil.MustMetric("cpu",
+		map[string]string{"cpu": "cpu0"},
+		map[string]interface{}{"usage_idle": 90.0, "usage_user": 5.0, "state": "ok"},
+		time.Unix(0, 10),
+	))
+	c.add(testutil.MustMetric("prometheus",
+		map[string]string{},
+		map[string]interface{}{"up": 1.0},
+		time.Unix(0, 10),
+	))
+	c.add(testutil.MustMetric("latency",
+		map[string]string{},
+		map[string]interface{}{"count": 10.0, "sum": 4.5, "0.1": 2.0, "1": 7.0, "+Inf": 10.0},
+		time.Unix(0, 10),
+		telegraf.Histogram,
+	))
+	c.add(testutil.MustMetric("duration",
+		map[string]string{},
+		map[string]interface{}{"count": 3.0, "sum": 6.0, "0.99": 3.0, "0.5": 2.0},
+		time.Unix(0, 10),
+		telegraf.Summary,
+	))
+
+	metrics := make(map[string]*metricspb.Metric)
+	for _, m := range c.metrics {
+		metrics[m.GetName()] = m
+	}
+	require.Len(t, metrics, 7)
+	require.Contains(t, metrics, "cpu_usage_idle")
+	require.Contains(t, metrics, "cpu_usage_user")
+	require.Contains(t, metrics, "up")
+
+	gauge := metrics["queue_length"].GetGauge().GetDataPoints()[0]
+	require.Equal(t, 42.0, gauge.GetAsDouble())
+	require.Equal(t, uint64(10), gauge.GetTimeUnixNano())
+	require.Equal(t, "queue", gauge.GetAttributes()[0].GetKey())
+	require.Equal(t, "orders", gauge.GetAttributes()[0].GetValue().GetStringValue())
+
+	sum := metrics["requests"].GetSum()
+	require.True(t, sum.GetIsMonotonic())
+	require.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.GetAggregationTemporality())
+	require.Equal(t, int64(7), sum.GetDataPoints()[0].GetAsInt())
+	require.Equal(t, uint64(1), sum.GetDataPoints()[0].GetStartTimeUnixNano())
+
+	histogram := metrics["latency"].GetHistogram().GetDataPoints()[0]
+	require.Equal(t, uint64(10), histogram.GetCount())
+	require.Equal(t, 4.5, histogram.GetSum())
+	require.Equal(t, []float64{0.1, 1}, histogram.GetExplicitBounds())
+	require.Equal(t, []uint64{2, 5, 3}, histogram.GetBucketCounts())
+
+	summary := metrics["duration"].GetSummary().GetDataPoints()[0]
+	require.Equal(t, uint64(3), summary.GetCount())
+	require.Len(t, summary.GetQuantileValues(), 2)
+	require.Equal(t, 0.5, summary.GetQuantileValues()[0].GetQuantile())
+	require.Equal(t, 2.0, summary.GetQuantileValues()[0].GetValue())
+}
+
+func TestConvertInvalidHistogram(t *testing.T) {
+	// A histogram without the expected fields is written as gauges.
+	c := newConverter(1)
+	c.add(testutil.MustMetric("latency",
+		map[string]string{},
+		map[string]interface{}{"p99": 4.5},
+		time.Unix(0, 10),
+		telegraf.Histogram,
+	))
+	require.Len(t, c.metrics, 1)
+	require.Equal(t, "latency_p99", c.metrics[0].GetName())
+	require.NotNil(t, c.metrics[0].GetGauge())
+}
+
+func TestWrite(t *testing.T) {
+	r, address, stop := startReceiver(t)
+	defer stop()
+
+	plugin := &OpenTelemetry{
+		ServiceAddress:     address,
+		Compression:        "gzip",
+		ResourceAttributes: map[string]string{"service.name": "telegraf", "host.name": "example"},
+		Headers:            map[string]string{"authorization": "Bearer token"},
+		Log:                testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	require.NoError(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage_idle": 90.0},
+			time.Unix(0, 10),
+		),
+	}))
+
+	r.Lock()
+	defer r.Unlock()
+	require.Len(t, r.requests, 1)
+	require.Equal(t, []string{"Bearer token"}, r.metadata[0].Get("authorization"))
+
+	rm := r.requests[0].GetResourceMetrics()[0]
+	attributes := rm.GetResource().GetAttributes()
+	require.Len(t, attributes, 2)
+	require.Equal(t, "host.name", attributes[0].GetKey())
+	require.Equal(t, "service.name", attributes[1].GetKey())
+
+	ilm := rm.GetInstrumentationLibraryMetrics()[0]
+	require.Equal(t, "telegraf", ilm.GetInstrumentationLibrary().GetName())
+	require.Len(t, ilm.GetMetrics(), 1)
+	require.Equal(t, "cpu_usage_idle", ilm.GetMetrics()[0].GetName())
+}
+
+func TestWriteError(t *testing.T) {
+	plugin := &OpenTelemetry{
+		ServiceAddress: freeAddress(t),
+		Timeout:        internal.Duration{Duration: 100 * time.Millisecond},
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	require.Error(t, plugin.Write([]telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{},
+			map[string]interface{}{"usage_idle": 90.0},
+			time.Unix(0, 10),
+		),
+	}))
+}
+
+// Metrics written to the opentelemetry input are received as they were
+// written.
+func TestRoundTrip(t *testing.T) {
+	address := freeAddress(t)
+	input := &opentelemetry.OpenTelemetry{
+		ServiceAddress: address,
+		Log:            testutil.Logger{},
+	}
+	var acc testutil.Accumulator
+	require.NoError(t, input.Start(&acc))
+	defer input.Stop()
+
+	plugin := &OpenTelemetry{
+		ServiceAddress: address,
+		Log:            testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.Connect())
+	defer plugin.Close()
+
+	metrics := []telegraf.Metric{
+		testutil.MustMetric("queue_length",
+			map[string]string{"queue": "orders"},
+			map[string]interface{}{"gauge": 42.0},
+			time.Unix(0, 10),
+			telegraf.Gauge,
+		),
+		testutil.MustMetric("requests",
+			map[string]string{"method": "GET"},
+			map[string]interface{}{"counter": int64(7)},
+			time.Unix(0, 10),
+			telegraf.Counter,
+		),
+		testutil.MustMetric("latency",
+			map[string]string{},
+			map[string]interface{}{"count": 10.0, "sum": 4.5, "0.1": 2.0, "1": 7.0, "+Inf": 10.0},
+			time.Unix(0, 10),
+			telegraf.Histogram,
+		),
+		testutil.MustMetric("duration",
+			map[string]string{},
+			map[string]interface{}{"count": 3.0, "sum": 6.0, "0.5": 2.0, "0.99": 3.0},
+			time.Unix(0, 10),
+			telegraf.Summary,
+		),
+	}
+	require.NoError(t, plugin.Write(metrics))
+
+	acc.Wait(len(metrics))
+	actual := acc.GetTelegrafMetrics()
+	for _, m := range actual {
+		m.RemoveTag("otel.library.name")
+	}
+	testutil.RequireMetricsEqual(t, metrics, actual)
+	for i := range metrics {
+		require.Equal(t, metrics[i].Type(), actual[i].Type())
+	}
+}
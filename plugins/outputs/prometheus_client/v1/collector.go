@@ -224,6 +224,20 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 			}
 		}
 
+		switch point.Type() {
+		case telegraf.Histogram, telegraf.Summary:
+			if len(point.DistributionList()) > 0 {
+				c.addDistributions(point, labels, sampleID, now)
+				continue
+			}
+
+			// Buckets and quantiles flattened into a metric each are part
+			// of the distribution of the metric with the count and sum.
+			if point.HasTag("le") || point.HasTag("quantile") {
+				continue
+			}
+		}
+
 		switch point.Type() {
 		case telegraf.Summary:
 			var mname string
@@ -370,6 +384,37 @@ func (c *Collector) Add(metrics []telegraf.Metric) error {
 	return nil
 }
 
+// addDistributions adds the histograms or summaries carried by the metric.
+func (c *Collector) addDistributions(point telegraf.Metric, labels map[string]string, sampleID SampleID, now time.Time) {
+	for _, d := range point.DistributionList() {
+		mname := sanitize(serializer.DistributionName(point.Name(), d.Key))
+		if !isValidTagName(mname) {
+			continue
+		}
+
+		sample := &Sample{
+			Labels:     labels,
+			Count:      d.Count,
+			Sum:        d.Sum,
+			Timestamp:  point.Time(),
+			Expiration: now.Add(c.ExpirationInterval),
+		}
+		if point.Type() == telegraf.Histogram {
+			sample.HistogramValue = make(map[float64]uint64, len(d.Buckets))
+			for _, b := range d.Buckets {
+				sample.HistogramValue[b.UpperBound] = b.Count
+			}
+		} else {
+			sample.SummaryValue = make(map[float64]float64, len(d.Quantiles))
+			for _, q := range d.Quantiles {
+				sample.SummaryValue[q.Quantile] = q.Value
+			}
+		}
+
+		c.addMetricFamily(point, sample, mname, sampleID)
+	}
+}
+
 func (c *Collector) Expire(now time.Time, age time.Duration) {
 	if age == 0 {
 		return
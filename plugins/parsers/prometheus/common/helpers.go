@@ -1,6 +1,9 @@
 package common
 
 import (
+	"math"
+	"sort"
+
 	"github.com/influxdata/telegraf"
 	dto "github.com/prometheus/client_model/go"
 )
@@ -34,3 +37,49 @@ func MakeLabels(m *dto.Metric, defaultTags map[string]string) map[string]string
 
 	return result
 }
+
+// HistogramDistribution returns the distribution of a histogram, with the
+// +Inf bucket added if the histogram has none.
+func HistogramDistribution(key string, h *dto.Histogram) *telegraf.Distribution {
+	d := &telegraf.Distribution{
+		Key:   key,
+		Count: h.GetSampleCount(),
+		Sum:   h.GetSampleSum(),
+	}
+	for _, b := range h.GetBucket() {
+		d.Buckets = append(d.Buckets, telegraf.Bucket{
+			UpperBound: b.GetUpperBound(),
+			Count:      b.GetCumulativeCount(),
+		})
+	}
+	sort.Slice(d.Buckets, func(i, j int) bool {
+		return d.Buckets[i].UpperBound < d.Buckets[j].UpperBound
+	})
+
+	if len(d.Buckets) == 0 || !math.IsInf(d.Buckets[len(d.Buckets)-1].UpperBound, 1) {
+		d.Buckets = append(d.Buckets, telegraf.Bucket{UpperBound: math.Inf(1), Count: d.Count})
+	}
+	return d
+}
+
+// SummaryDistribution returns the distribution of a summary.
+func SummaryDistribution(key string, s *dto.Summary) *telegraf.Distribution {
+	d := &telegraf.Distribution{
+		Key:   key,
+		Count: s.GetSampleCount(),
+		Sum:   s.GetSampleSum(),
+	}
+	for _, q := range s.GetQuantile() {
+		if math.IsNaN(q.GetValue()) {
+			continue
+		}
+		d.Quantiles = append(d.Quantiles, telegraf.Quantile{
+			Quantile: q.GetQuantile(),
+			Value:    q.GetValue(),
+		})
+	}
+	sort.Slice(d.Quantiles, func(i, j int) bool {
+		return d.Quantiles[i].Quantile < d.Quantiles[j].Quantile
+	})
+	return d
+}
@@ -124,6 +124,7 @@ func makeQuantiles(m *dto.Metric, tags map[string]string, metricName string, met
 	fields[metricName+"_sum"] = float64(m.GetSummary().GetSampleSum())
 	met, err := metric.New("prometheus", tags, fields, t, ValueType(metricType))
 	if err == nil {
+		met.AddDistribution(SummaryDistribution(metricName, m.GetSummary()))
 		metrics = append(metrics, met)
 	}
 
@@ -153,6 +154,7 @@ func makeBuckets(m *dto.Metric, tags map[string]string, metricName string, metri
 
 	met, err := metric.New("prometheus", tags, fields, t, ValueType(metricType))
 	if err == nil {
+		met.AddDistribution(HistogramDistribution(metricName, m.GetHistogram()))
 		metrics = append(metrics, met)
 	}
 
@@ -3,6 +3,7 @@ package prometheus
 import (
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -99,17 +100,29 @@ func TestParsingValieCounter(t *testing.T) {
 
 func TestParsingValidSummary(t *testing.T) {
 	expected := []telegraf.Metric{
-		testutil.MustMetric(
-			"prometheus",
-			map[string]string{
-				"handler": "prometheus",
-			},
-			map[string]interface{}{
-				"http_request_duration_microseconds_sum":   float64(1.8909097205e+07),
-				"http_request_duration_microseconds_count": float64(9.0),
+		testutil.WithDistributions(
+			testutil.MustMetric(
+				"prometheus",
+				map[string]string{
+					"handler": "prometheus",
+				},
+				map[string]interface{}{
+					"http_request_duration_microseconds_sum":   float64(1.8909097205e+07),
+					"http_request_duration_microseconds_count": float64(9.0),
+				},
+				time.Unix(0, 0),
+				telegraf.Summary,
+			),
+			&telegraf.Distribution{
+				Key:   "http_request_duration_microseconds",
+				Count: 9,
+				Sum:   1.8909097205e+07,
+				Quantiles: []telegraf.Quantile{
+					{Quantile: 0.5, Value: 552048.506},
+					{Quantile: 0.9, Value: 5.876804288e+06},
+					{Quantile: 0.99, Value: 5.876804288e+06},
+				},
 			},
-			time.Unix(0, 0),
-			telegraf.Summary,
 		),
 		testutil.MustMetric(
 			"prometheus",
@@ -158,18 +171,35 @@ func TestParsingValidSummary(t *testing.T) {
 
 func TestParsingValidHistogram(t *testing.T) {
 	expected := []telegraf.Metric{
-		testutil.MustMetric(
-			"prometheus",
-			map[string]string{
-				"verb":     "POST",
-				"resource": "bindings",
+		testutil.WithDistributions(
+			testutil.MustMetric(
+				"prometheus",
+				map[string]string{
+					"verb":     "POST",
+					"resource": "bindings",
+				},
+				map[string]interface{}{
+					"apiserver_request_latencies_count": float64(2025.0),
+					"apiserver_request_latencies_sum":   float64(1.02726334e+08),
+				},
+				time.Unix(0, 0),
+				telegraf.Histogram,
+			),
+			&telegraf.Distribution{
+				Key:   "apiserver_request_latencies",
+				Count: 2025,
+				Sum:   1.02726334e+08,
+				Buckets: []telegraf.Bucket{
+					{UpperBound: 125000, Count: 1994},
+					{UpperBound: 250000, Count: 1997},
+					{UpperBound: 500000, Count: 2000},
+					{UpperBound: 1e+06, Count: 2005},
+					{UpperBound: 2e+06, Count: 2012},
+					{UpperBound: 4e+06, Count: 2017},
+					{UpperBound: 8e+06, Count: 2024},
+					{UpperBound: math.Inf(1), Count: 2025},
+				},
 			},
-			map[string]interface{}{
-				"apiserver_request_latencies_count": float64(2025.0),
-				"apiserver_request_latencies_sum":   float64(1.02726334e+08),
-			},
-			time.Unix(0, 0),
-			telegraf.Histogram,
 		),
 		testutil.MustMetric(
 			"prometheus",
@@ -0,0 +1,104 @@
+package prometheusremotewrite
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// Parser parses the snappy compressed protocol buffer WriteRequest bodies
+// of the Prometheus remote write protocol.
+//
+// The metrics follow the format of the prometheus parser: the measurement is
+// "prometheus", the name of the series is the field, and its other labels are
+// the tags.
+type Parser struct {
+	DefaultTags map[string]string
+}
+
+func (p *Parser) Parse(buf []byte) ([]telegraf.Metric, error) {
+	data, err := snappy.Decode(nil, buf)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing snappy body failed: %v", err)
+	}
+
+	var req prompb.WriteRequest
+	if err := proto.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("unmarshaling write request failed: %v", err)
+	}
+
+	now := time.Now()
+
+	var metrics []telegraf.Metric
+	for _, ts := range req.Timeseries {
+		tags := make(map[string]string, len(p.DefaultTags)+len(ts.Labels))
+		for key, value := range p.DefaultTags {
+			tags[key] = value
+		}
+
+		var name string
+		for _, l := range ts.Labels {
+			if l.Name == "__name__" {
+				name = l.Value
+				continue
+			}
+			tags[l.Name] = l.Value
+		}
+		// A series without a name can't be turned into a field, but the
+		// other series of the request are still parsed.
+		if name == "" {
+			log.Printf("D! [parsers.prometheusremotewrite] Dropping series without a __name__ label: %v", tags)
+			continue
+		}
+
+		for _, s := range ts.Samples {
+			// Stale markers, and any other NaN, are dropped like in the
+			// prometheus parser.
+			if math.IsNaN(s.Value) {
+				continue
+			}
+
+			t := now
+			if s.Timestamp > 0 {
+				t = time.Unix(0, s.Timestamp*int64(time.Millisecond))
+			}
+
+			fields := map[string]interface{}{name: s.Value}
+			m, err := metric.New("prometheus", tags, fields, t)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m)
+		}
+	}
+
+	return metrics, nil
+}
+
+func (p *Parser) ParseLine(line string) (telegraf.Metric, error) {
+	metrics, err := p.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metrics) < 1 {
+		return nil, fmt.Errorf("No metrics in line")
+	}
+
+	if len(metrics) > 1 {
+		return nil, fmt.Errorf("More than one metric in line")
+	}
+
+	return metrics[0], nil
+}
+
+func (p *Parser) SetDefaultTags(tags map[string]string) {
+	p.DefaultTags = tags
+}
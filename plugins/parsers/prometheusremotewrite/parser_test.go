@@ -0,0 +1,211 @@
+package prometheusremotewrite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/serializers/prometheusremotewrite"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, req *prompb.WriteRequest) []byte {
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	return snappy.Encode(nil, data)
+}
+
+func TestParse(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "go_gc_duration_seconds"},
+					{Name: "instance", Value: "localhost:9090"},
+					{Name: "quantile", Value: "0.99"},
+				},
+				Samples: []prompb.Sample{
+					{Value: 0.0012, Timestamp: 1614889298859},
+					{Value: 0.0015, Timestamp: 1614889313859},
+				},
+			},
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "up"},
+					{Name: "job", Value: "prometheus"},
+				},
+				Samples: []prompb.Sample{
+					{Value: 1, Timestamp: 1614889298859},
+				},
+			},
+		},
+	}
+
+	parser := &Parser{}
+	metrics, err := parser.Parse(encode(t, req))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("prometheus",
+			map[string]string{"instance": "localhost:9090", "quantile": "0.99"},
+			map[string]interface{}{"go_gc_duration_seconds": 0.0012},
+			time.Unix(0, 1614889298859*int64(time.Millisecond)),
+		),
+		testutil.MustMetric("prometheus",
+			map[string]string{"instance": "localhost:9090", "quantile": "0.99"},
+			map[string]interface{}{"go_gc_duration_seconds": 0.0015},
+			time.Unix(0, 1614889313859*int64(time.Millisecond)),
+		),
+		testutil.MustMetric("prometheus",
+			map[string]string{"job": "prometheus"},
+			map[string]interface{}{"up": 1.0},
+			time.Unix(0, 1614889298859*int64(time.Millisecond)),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestParseDefaultTags(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "up"},
+					{Name: "job", Value: "prometheus"},
+				},
+				Samples: []prompb.Sample{
+					{Value: 1, Timestamp: 1614889298859},
+				},
+			},
+		},
+	}
+
+	parser := &Parser{}
+	parser.SetDefaultTags(map[string]string{"source": "remote", "job": "default"})
+	metrics, err := parser.Parse(encode(t, req))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("prometheus",
+			map[string]string{"source": "remote", "job": "prometheus"},
+			map[string]interface{}{"up": 1.0},
+			time.Unix(0, 1614889298859*int64(time.Millisecond)),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestParseDropsNaN(t *testing.T) {
+	// The stale marker of Prometheus is a NaN with this bit pattern.
+	staleNaN := math.Float64frombits(0x7ff0000000000002)
+
+	req := &prompb.WriteRequest{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "up"},
+				},
+				Samples: []prompb.Sample{
+					{Value: staleNaN, Timestamp: 1614889298859},
+					{Value: math.NaN(), Timestamp: 1614889298859},
+				},
+			},
+		},
+	}
+
+	parser := &Parser{}
+	metrics, err := parser.Parse(encode(t, req))
+	require.NoError(t, err)
+	require.Empty(t, metrics)
+}
+
+func TestParseErrors(t *testing.T) {
+	parser := &Parser{}
+
+	_, err := parser.Parse([]byte("up 1"))
+	require.Error(t, err)
+
+	_, err = parser.Parse(snappy.Encode(nil, []byte("up 1")))
+	require.Error(t, err)
+}
+
+func TestParseDropsSeriesWithoutName(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels: []*prompb.Label{
+					{Name: "job", Value: "prometheus"},
+				},
+				Samples: []prompb.Sample{
+					{Value: 1, Timestamp: 1614889298859},
+				},
+			},
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "up"},
+				},
+				Samples: []prompb.Sample{
+					{Value: 1, Timestamp: 1614889298859},
+				},
+			},
+		},
+	}
+
+	parser := &Parser{}
+	metrics, err := parser.Parse(encode(t, req))
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("prometheus",
+			map[string]string{},
+			map[string]interface{}{"up": 1.0},
+			time.Unix(0, 1614889298859*int64(time.Millisecond)),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics)
+}
+
+func TestParseSerialized(t *testing.T) {
+	serializer, err := prometheusremotewrite.NewSerializer(prometheusremotewrite.FormatConfig{
+		MetricSortOrder: prometheusremotewrite.SortMetrics,
+	})
+	require.NoError(t, err)
+
+	input := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "example.org"},
+			map[string]interface{}{"time_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("prometheus",
+			map[string]string{"job": "prometheus"},
+			map[string]interface{}{"up": 1.0},
+			time.Unix(0, 0),
+		),
+	}
+	data, err := serializer.SerializeBatch(input)
+	require.NoError(t, err)
+
+	parser := &Parser{}
+	metrics, err := parser.Parse(data)
+	require.NoError(t, err)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("prometheus",
+			map[string]string{"host": "example.org"},
+			map[string]interface{}{"cpu_time_idle": 42.0},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("prometheus",
+			map[string]string{"job": "prometheus"},
+			map[string]interface{}{"up": 1.0},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, metrics, testutil.SortMetrics(), testutil.IgnoreTime())
+}
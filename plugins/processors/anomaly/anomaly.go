@@ -0,0 +1,241 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Fields to check for anomalies; globs accepted.
+  fields = ["usage_idle"]
+
+  ## Baseline of the values of each field of a series:
+  ##   ewma: exponentially weighted moving average and standard deviation.
+  ##   mad:  median and median absolute deviation of the latest values.
+  # method = "ewma"
+
+  ## Smoothing factor of the ewma method, between 0 and 1; higher values
+  ## follow changes of the values faster.
+  # alpha = 0.1
+
+  ## Number of latest values of the mad method.
+  # window_size = 60
+
+  ## Number of values in a baseline before values are checked against it.
+  # min_samples = 10
+
+  ## Deviation from the baseline, in standard deviations, above which a value
+  ## is an anomaly.
+  # threshold = 3.0
+
+  ## Suffix of the field added with the deviation of each value; set to ""
+  ## to not add the fields.
+  # score_suffix = "_anomaly_score"
+
+  ## Tag set to "true" on metrics with an anomaly; set to "" to not add the
+  ## tag.
+  # tag = "anomaly"
+
+  ## The time that a series is not updated until its baseline is removed.
+  # series_timeout = "1h"
+`
+
+const (
+	methodEWMA = "ewma"
+	methodMAD  = "mad"
+)
+
+type Anomaly struct {
+	Fields        []string        `toml:"fields"`
+	Method        string          `toml:"method"`
+	Alpha         float64         `toml:"alpha"`
+	WindowSize    int             `toml:"window_size"`
+	MinSamples    int             `toml:"min_samples"`
+	Threshold     float64         `toml:"threshold"`
+	ScoreSuffix   string          `toml:"score_suffix"`
+	Tag           string          `toml:"tag"`
+	SeriesTimeout config.Duration `toml:"series_timeout"`
+	Log           telegraf.Logger `toml:"-"`
+
+	fieldFilter filter.Filter
+	series      map[uint64]*series
+	lastExpire  time.Time
+	now         func() time.Time
+}
+
+// series holds the baselines of the fields of a series.
+type series struct {
+	lastSeen  time.Time
+	baselines map[string]baseline
+}
+
+func (a *Anomaly) SampleConfig() string {
+	return sampleConfig
+}
+
+func (a *Anomaly) Description() string {
+	return "Score field values against a rolling baseline of their series and tag anomalies"
+}
+
+func (a *Anomaly) Init() error {
+	if len(a.Fields) == 0 {
+		return fmt.Errorf("fields must be set")
+	}
+	var err error
+	a.fieldFilter, err = filter.Compile(a.Fields)
+	if err != nil {
+		return fmt.Errorf("invalid fields: %v", err)
+	}
+
+	switch a.Method {
+	case methodEWMA:
+		if a.Alpha <= 0 || a.Alpha > 1 {
+			return fmt.Errorf("alpha must be between 0 and 1")
+		}
+	case methodMAD:
+		if a.WindowSize < 1 {
+			return fmt.Errorf("window_size must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown method %q", a.Method)
+	}
+	if a.MinSamples < 1 {
+		return fmt.Errorf("min_samples must be positive")
+	}
+	if a.Threshold <= 0 {
+		return fmt.Errorf("threshold must be positive")
+	}
+	if a.SeriesTimeout <= 0 {
+		return fmt.Errorf("series_timeout must be positive")
+	}
+
+	a.series = make(map[uint64]*series)
+	if a.now == nil {
+		a.now = time.Now
+	}
+	a.lastExpire = a.now()
+	return nil
+}
+
+func (a *Anomaly) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	now := a.now()
+	if now.Sub(a.lastExpire) >= time.Duration(a.SeriesTimeout)/10 {
+		a.expire(now)
+		a.lastExpire = now
+	}
+
+	for _, metric := range in {
+		a.check(metric, now)
+	}
+	return in
+}
+
+// check scores the values of the fields of a metric against the baselines
+// of its series, before adding them to the baselines.
+func (a *Anomaly) check(metric telegraf.Metric, now time.Time) {
+	var s *series
+	anomaly := false
+	scores := make(map[string]float64)
+	for _, field := range metric.FieldList() {
+		if !a.fieldFilter.Match(field.Key) {
+			continue
+		}
+		// NaN and infinite values can't be scored and would poison the
+		// baseline.
+		value, ok := toFloat(field.Value)
+		if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+
+		if s == nil {
+			s = a.getSeries(metric, now)
+		}
+		b, ok := s.baselines[field.Key]
+		if !ok {
+			b = a.newBaseline()
+			s.baselines[field.Key] = b
+		}
+
+		if b.samples() >= a.MinSamples {
+			score, ok := b.score(value)
+			if ok {
+				scores[field.Key] = score
+			}
+			if !ok || score > a.Threshold {
+				anomaly = true
+			}
+		}
+		b.add(value)
+	}
+
+	if a.ScoreSuffix != "" {
+		for key, score := range scores {
+			metric.AddField(key+a.ScoreSuffix, score)
+		}
+	}
+	if anomaly && a.Tag != "" {
+		metric.AddTag(a.Tag, "true")
+	}
+}
+
+func (a *Anomaly) getSeries(metric telegraf.Metric, now time.Time) *series {
+	id := metric.HashID()
+	s, ok := a.series[id]
+	if !ok {
+		s = &series{baselines: make(map[string]baseline)}
+		a.series[id] = s
+	}
+	s.lastSeen = now
+	return s
+}
+
+func (a *Anomaly) newBaseline() baseline {
+	if a.Method == methodMAD {
+		return newMAD(a.WindowSize)
+	}
+	return &ewma{alpha: a.Alpha}
+}
+
+// expire removes the baselines of the series not updated within the series
+// timeout.
+func (a *Anomaly) expire(now time.Time) {
+	for id, s := range a.series {
+		if now.Sub(s.lastSeen) > time.Duration(a.SeriesTimeout) {
+			delete(a.series, id)
+		}
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	processors.Add("anomaly", func() telegraf.Processor {
+		return &Anomaly{
+			Method:        methodEWMA,
+			Alpha:         0.1,
+			WindowSize:    60,
+			MinSamples:    10,
+			Threshold:     3.0,
+			ScoreSuffix:   "_anomaly_score",
+			Tag:           "anomaly",
+			SeriesTimeout: config.Duration(time.Hour),
+		}
+	})
+}
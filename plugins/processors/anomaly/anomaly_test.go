@@ -0,0 +1,188 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newAnomaly(method string) (*Anomaly, *time.Time) {
+	now := time.Unix(1000, 0)
+	a := &Anomaly{
+		Fields:        []string{"usage_*"},
+		Method:        method,
+		Alpha:         0.1,
+		WindowSize:    20,
+		MinSamples:    10,
+		Threshold:     3.0,
+		ScoreSuffix:   "_anomaly_score",
+		Tag:           "anomaly",
+		SeriesTimeout: config.Duration(time.Minute),
+		Log:           testutil.Logger{},
+		now:           func() time.Time { return now },
+	}
+	return a, &now
+}
+
+func cpu(host string, value float64) telegraf.Metric {
+	return testutil.MustMetric("cpu",
+		map[string]string{"host": host},
+		map[string]interface{}{"usage_idle": value, "count": int64(1)},
+		time.Unix(0, 0),
+	)
+}
+
+// baselineValues alternate around 50, so that the baseline has a spread.
+func baselineValues(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 50 + float64(i%3-1)
+	}
+	return values
+}
+
+func TestAnomaly(t *testing.T) {
+	for _, method := range []string{"ewma", "mad"} {
+		t.Run(method, func(t *testing.T) {
+			a, _ := newAnomaly(method)
+			require.NoError(t, a.Init())
+
+			for i, value := range baselineValues(20) {
+				m := a.Apply(cpu("a", value))[0]
+				_, hasScore := m.GetField("usage_idle_anomaly_score")
+				require.Equal(t, i >= 10, hasScore)
+				require.False(t, m.HasTag("anomaly"))
+				require.False(t, m.HasField("count_anomaly_score"))
+			}
+
+			m := a.Apply(cpu("a", 50))[0]
+			require.False(t, m.HasTag("anomaly"))
+
+			m = a.Apply(cpu("a", 90))[0]
+			tag, _ := m.GetTag("anomaly")
+			require.Equal(t, "true", tag)
+			score, _ := m.GetField("usage_idle_anomaly_score")
+			require.Greater(t, score.(float64), 3.0)
+
+			// Other series have their own baseline.
+			m = a.Apply(cpu("b", 90))[0]
+			require.False(t, m.HasTag("anomaly"))
+			require.False(t, m.HasField("usage_idle_anomaly_score"))
+		})
+	}
+}
+
+func TestConstantBaseline(t *testing.T) {
+	a, _ := newAnomaly("mad")
+	require.NoError(t, a.Init())
+
+	for i := 0; i < 10; i++ {
+		a.Apply(cpu("a", 50))
+	}
+
+	m := a.Apply(cpu("a", 50))[0]
+	require.False(t, m.HasTag("anomaly"))
+	score, _ := m.GetField("usage_idle_anomaly_score")
+	require.Equal(t, 0.0, score)
+
+	// A deviation from a baseline without spread can't be scored.
+	m = a.Apply(cpu("a", 51))[0]
+	require.True(t, m.HasTag("anomaly"))
+	require.False(t, m.HasField("usage_idle_anomaly_score"))
+}
+
+func TestNonFiniteValues(t *testing.T) {
+	for _, method := range []string{"ewma", "mad"} {
+		t.Run(method, func(t *testing.T) {
+			a, _ := newAnomaly(method)
+			require.NoError(t, a.Init())
+
+			for _, value := range baselineValues(10) {
+				a.Apply(cpu("a", value))
+			}
+
+			for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+				m := a.Apply(cpu("a", value))[0]
+				require.False(t, m.HasTag("anomaly"))
+				require.False(t, m.HasField("usage_idle_anomaly_score"))
+			}
+
+			// The baseline is unaffected by the skipped values.
+			m := a.Apply(cpu("a", 50))[0]
+			require.False(t, m.HasTag("anomaly"))
+			score, ok := m.GetField("usage_idle_anomaly_score")
+			require.True(t, ok)
+			require.False(t, math.IsNaN(score.(float64)))
+			require.Less(t, score.(float64), 3.0)
+		})
+	}
+}
+
+func TestSeriesTimeout(t *testing.T) {
+	a, now := newAnomaly("ewma")
+	require.NoError(t, a.Init())
+
+	a.Apply(cpu("a", 50), cpu("b", 50))
+	require.Len(t, a.series, 2)
+
+	*now = now.Add(40 * time.Second)
+	a.Apply(cpu("a", 50))
+	require.Len(t, a.series, 2)
+
+	*now = now.Add(30 * time.Second)
+	a.Apply()
+	require.Len(t, a.series, 1)
+}
+
+func TestEWMA(t *testing.T) {
+	e := &ewma{alpha: 0.5}
+	e.add(10)
+	e.add(20)
+	require.Equal(t, 15.0, e.mean)
+	require.Equal(t, 25.0, e.variance)
+
+	score, ok := e.score(25)
+	require.True(t, ok)
+	require.Equal(t, 2.0, score)
+}
+
+func TestMAD(t *testing.T) {
+	m := newMAD(5)
+	for _, v := range []float64{1, 2, 3, 4, 100, 5, 6} {
+		m.add(v)
+	}
+	// The window holds 3, 4, 100, 5, 6 with a median of 5 and a median
+	// absolute deviation of 1.
+	score, ok := m.score(8)
+	require.True(t, ok)
+	require.InDelta(t, 3/madScale, score, 1e-9)
+	require.Equal(t, 7, m.samples())
+	require.False(t, math.IsNaN(score))
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(a *Anomaly)
+	}{
+		{name: "no fields", modify: func(a *Anomaly) { a.Fields = nil }},
+		{name: "unknown method", modify: func(a *Anomaly) { a.Method = "zscore" }},
+		{name: "invalid alpha", modify: func(a *Anomaly) { a.Alpha = 1.5 }},
+		{name: "invalid window size", modify: func(a *Anomaly) { a.Method = "mad"; a.WindowSize = 0 }},
+		{name: "invalid min samples", modify: func(a *Anomaly) { a.MinSamples = 0 }},
+		{name: "invalid threshold", modify: func(a *Anomaly) { a.Threshold = 0 }},
+		{name: "invalid series timeout", modify: func(a *Anomaly) { a.SeriesTimeout = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := newAnomaly("ewma")
+			tt.modify(a)
+			require.Error(t, a.Init())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+)
+
+// madScale scales the median absolute deviation to estimate the standard
+// deviation of normally distributed values.
+const madScale = 1.4826
+
+// baseline is the rolling baseline of the values of a field of a series.
+type baseline interface {
+	// score returns the deviation of a value from the baseline, in units of
+	// its spread.  It returns false if the baseline has no spread and the
+	// value deviates from it, as the deviation can't be scored then.
+	score(value float64) (float64, bool)
+
+	// add adds a value to the baseline.
+	add(value float64)
+
+	// samples returns the number of values added to the baseline.
+	samples() int
+}
+
+// ewma is a baseline of the exponentially weighted moving average and
+// standard deviation of the values.
+type ewma struct {
+	alpha    float64
+	mean     float64
+	variance float64
+	count    int
+}
+
+func (e *ewma) score(value float64) (float64, bool) {
+	deviation := math.Abs(value - e.mean)
+	std := math.Sqrt(e.variance)
+	if std == 0 {
+		return 0, deviation == 0
+	}
+	return deviation / std, true
+}
+
+func (e *ewma) add(value float64) {
+	e.count++
+	if e.count == 1 {
+		e.mean = value
+		return
+	}
+	diff := value - e.mean
+	incr := e.alpha * diff
+	e.mean += incr
+	e.variance = (1 - e.alpha) * (e.variance + diff*incr)
+}
+
+func (e *ewma) samples() int {
+	return e.count
+}
+
+// mad is a baseline of the median and median absolute deviation of the
+// latest values.
+type mad struct {
+	values []float64
+	next   int
+	count  int
+}
+
+func newMAD(size int) *mad {
+	return &mad{values: make([]float64, 0, size)}
+}
+
+func (m *mad) score(value float64) (float64, bool) {
+	center := median(append([]float64(nil), m.values...))
+	deviations := make([]float64, len(m.values))
+	for i, v := range m.values {
+		deviations[i] = math.Abs(v - center)
+	}
+	spread := madScale * median(deviations)
+
+	deviation := math.Abs(value - center)
+	if spread == 0 {
+		return 0, deviation == 0
+	}
+	return deviation / spread, true
+}
+
+func (m *mad) add(value float64) {
+	m.count++
+	if len(m.values) < cap(m.values) {
+		m.values = append(m.values, value)
+		return
+	}
+	m.values[m.next] = value
+	m.next = (m.next + 1) % len(m.values)
+}
+
+func (m *mad) samples() int {
+	return m.count
+}
+
+// median returns the median of the values, sorting them.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
@@ -0,0 +1,321 @@
+package cardinality
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+const sampleConfig = `
+  ## Maximum number of series of a measurement; set to 0 for no limit.
+  limit = 10000
+
+  ## Maximum number of series of specific measurements, overriding limit.
+  # [processors.cardinality.limits]
+  #   http_requests = 500
+
+  ## Duration a series is counted for since it was last seen.
+  # window = "1h"
+
+  ## Action on the metrics of a new series once the limit is reached:
+  ##   drop:    drop the metrics.
+  ##   rewrite: replace the values of the tags in rewrite_tags with the
+  ##            placeholder, or else of the tag with the most distinct values;
+  ##            the metrics are dropped if their series is still new.
+  # action = "drop"
+
+  ## Tags whose values are replaced by the rewrite action.
+  # rewrite_tags = ["path", "user_id"]
+
+  ## Value replacing the tag values with the rewrite action.
+  # placeholder = "other"
+
+  ## Number of tags with the most distinct values per measurement reported
+  ## in the internal metrics.
+  # top_tags = 5
+`
+
+const (
+	actionDrop    = "drop"
+	actionRewrite = "rewrite"
+)
+
+type Cardinality struct {
+	Limit       int             `toml:"limit"`
+	Limits      map[string]int  `toml:"limits"`
+	Window      config.Duration `toml:"window"`
+	Action      string          `toml:"action"`
+	RewriteTags []string        `toml:"rewrite_tags"`
+	Placeholder string          `toml:"placeholder"`
+	TopTags     int             `toml:"top_tags"`
+	Log         telegraf.Logger `toml:"-"`
+
+	measurements map[string]*measurement
+	lastExpire   time.Time
+	now          func() time.Time
+}
+
+// series is a series counted towards the limit of its measurement.
+type series struct {
+	lastSeen time.Time
+	tags     []telegraf.Tag
+}
+
+// measurement holds the series of a measurement, and the number of series
+// of each value of their tags.
+type measurement struct {
+	name   string
+	limit  int
+	series map[uint64]*series
+	values map[string]map[string]int
+
+	seriesCount selfstat.Stat
+	dropped     selfstat.Stat
+	rewritten   selfstat.Stat
+	tagValues   map[string]selfstat.Stat
+}
+
+func (c *Cardinality) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *Cardinality) Description() string {
+	return "Limit the number of series of measurements, dropping or rewriting the metrics of new series"
+}
+
+func (c *Cardinality) Init() error {
+	if c.Window <= 0 {
+		return fmt.Errorf("window must be positive")
+	}
+	switch c.Action {
+	case actionDrop, actionRewrite:
+	default:
+		return fmt.Errorf("unknown action %q", c.Action)
+	}
+	if c.Action == actionRewrite && c.Placeholder == "" {
+		return fmt.Errorf("placeholder must be set for the rewrite action")
+	}
+	if c.TopTags < 0 {
+		return fmt.Errorf("top_tags must not be negative")
+	}
+
+	c.measurements = make(map[string]*measurement)
+	if c.now == nil {
+		c.now = time.Now
+	}
+	c.lastExpire = c.now()
+	return nil
+}
+
+func (c *Cardinality) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	now := c.now()
+	// Series are expired at most ten times per window, so that they don't
+	// need to be checked for every batch.
+	if now.Sub(c.lastExpire) >= time.Duration(c.Window)/10 {
+		c.expire(now)
+		c.lastExpire = now
+	}
+
+	out := in[:0]
+	for _, metric := range in {
+		m := c.measurement(metric.Name())
+
+		id := metric.HashID()
+		if s, ok := m.series[id]; ok {
+			s.lastSeen = now
+			out = append(out, metric)
+			continue
+		}
+
+		if m.limit <= 0 || len(m.series) < m.limit {
+			m.add(id, metric, now)
+			out = append(out, metric)
+			continue
+		}
+
+		if c.Action == actionDrop {
+			m.dropped.Incr(1)
+			metric.Drop()
+			continue
+		}
+
+		// The rewritten series are limited as well, as tags other than the
+		// ones replaced may still vary.
+		c.rewrite(m, metric)
+		id = metric.HashID()
+		if s, ok := m.series[id]; ok {
+			s.lastSeen = now
+		} else if len(m.series) < m.limit {
+			m.add(id, metric, now)
+		} else {
+			m.dropped.Incr(1)
+			metric.Drop()
+			continue
+		}
+		m.rewritten.Incr(1)
+		out = append(out, metric)
+	}
+	return out
+}
+
+// measurement returns the state of a measurement, creating it when it is
+// first seen.
+func (c *Cardinality) measurement(name string) *measurement {
+	if m, ok := c.measurements[name]; ok {
+		return m
+	}
+
+	limit := c.Limit
+	if l, ok := c.Limits[name]; ok {
+		limit = l
+	}
+	tags := map[string]string{"measurement": name}
+	m := &measurement{
+		name:        name,
+		limit:       limit,
+		series:      make(map[uint64]*series),
+		values:      make(map[string]map[string]int),
+		seriesCount: selfstat.Register("cardinality", "series", tags),
+		dropped:     selfstat.Register("cardinality", "dropped", tags),
+		rewritten:   selfstat.Register("cardinality", "rewritten", tags),
+		tagValues:   make(map[string]selfstat.Stat),
+	}
+	c.measurements[name] = m
+	return m
+}
+
+// rewrite replaces the values of the offending tags of a metric with the
+// placeholder.
+func (c *Cardinality) rewrite(m *measurement, metric telegraf.Metric) {
+	keys := c.RewriteTags
+	if len(keys) == 0 {
+		if key, ok := m.topTag(metric); ok {
+			keys = []string{key}
+		}
+	}
+	for _, key := range keys {
+		if metric.HasTag(key) {
+			metric.AddTag(key, c.Placeholder)
+		}
+	}
+}
+
+// expire removes the series not seen within the window, and updates the
+// internal metrics.  Measurements left without series are removed along with
+// their internal metrics.
+func (c *Cardinality) expire(now time.Time) {
+	for name, m := range c.measurements {
+		for id, s := range m.series {
+			if now.Sub(s.lastSeen) > time.Duration(c.Window) {
+				m.remove(id, s)
+			}
+		}
+		if len(m.series) == 0 {
+			m.unregister()
+			delete(c.measurements, name)
+			continue
+		}
+		m.seriesCount.Set(int64(len(m.series)))
+
+		// Tags stay reported once they were among the top tags, so that
+		// their number of values doesn't appear to stay constant when they
+		// leave them.
+		for _, key := range m.topTags(c.TopTags) {
+			if _, ok := m.tagValues[key]; !ok {
+				m.tagValues[key] = selfstat.Register("cardinality", "tag_values",
+					map[string]string{"measurement": m.name, "tag_key": key})
+			}
+		}
+		for key, stat := range m.tagValues {
+			stat.Set(int64(len(m.values[key])))
+		}
+	}
+}
+
+func (m *measurement) add(id uint64, metric telegraf.Metric, now time.Time) {
+	s := &series{lastSeen: now}
+	for _, tag := range metric.TagList() {
+		s.tags = append(s.tags, *tag)
+		values, ok := m.values[tag.Key]
+		if !ok {
+			values = make(map[string]int)
+			m.values[tag.Key] = values
+		}
+		values[tag.Value]++
+	}
+	m.series[id] = s
+	m.seriesCount.Set(int64(len(m.series)))
+}
+
+func (m *measurement) remove(id uint64, s *series) {
+	for _, tag := range s.tags {
+		values := m.values[tag.Key]
+		values[tag.Value]--
+		if values[tag.Value] <= 0 {
+			delete(values, tag.Value)
+		}
+		if len(values) == 0 {
+			delete(m.values, tag.Key)
+		}
+	}
+	delete(m.series, id)
+}
+
+// unregister removes the internal metrics of the measurement.
+func (m *measurement) unregister() {
+	selfstat.Unregister(m.seriesCount)
+	selfstat.Unregister(m.dropped)
+	selfstat.Unregister(m.rewritten)
+	for _, stat := range m.tagValues {
+		selfstat.Unregister(stat)
+	}
+}
+
+// topTag returns the tag of the metric with the most distinct values in the
+// series of the measurement.
+func (m *measurement) topTag(metric telegraf.Metric) (string, bool) {
+	var top string
+	count := 0
+	for _, tag := range metric.TagList() {
+		if n := len(m.values[tag.Key]); n > count {
+			top, count = tag.Key, n
+		}
+	}
+	return top, count > 0
+}
+
+// topTags returns up to n tags with the most distinct values.
+func (m *measurement) topTags(n int) []string {
+	keys := make([]string, 0, len(m.values))
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ci, cj := len(m.values[keys[i]]), len(m.values[keys[j]])
+		if ci != cj {
+			return ci > cj
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+func init() {
+	processors.Add("cardinality", func() telegraf.Processor {
+		return &Cardinality{
+			Limit:       10000,
+			Window:      config.Duration(time.Hour),
+			Action:      actionDrop,
+			Placeholder: "other",
+			TopTags:     5,
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package cardinality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/selfstat"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newCardinality(limit int, action string) (*Cardinality, *time.Time) {
+	now := time.Unix(1000, 0)
+	c := &Cardinality{
+		Limit:       limit,
+		Window:      config.Duration(time.Minute),
+		Action:      action,
+		Placeholder: "other",
+		TopTags:     2,
+		Log:         testutil.Logger{},
+		now:         func() time.Time { return now },
+	}
+	return c, &now
+}
+
+func request(name, path, method string) telegraf.Metric {
+	return testutil.MustMetric(name,
+		map[string]string{"path": path, "method": method},
+		map[string]interface{}{"count": int64(1)},
+		time.Unix(0, 0),
+	)
+}
+
+func TestDrop(t *testing.T) {
+	c, _ := newCardinality(2, "drop")
+	require.NoError(t, c.Init())
+
+	actual := c.Apply(
+		request("drop_requests", "/a", "GET"),
+		request("drop_requests", "/b", "GET"),
+		request("drop_requests", "/c", "GET"),
+		request("drop_requests", "/a", "GET"),
+		request("drop_other", "/c", "GET"),
+	)
+	expected := []telegraf.Metric{
+		request("drop_requests", "/a", "GET"),
+		request("drop_requests", "/b", "GET"),
+		request("drop_requests", "/a", "GET"),
+		request("drop_other", "/c", "GET"),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	m := c.measurements["drop_requests"]
+	require.Equal(t, int64(1), m.dropped.Get())
+	require.Equal(t, int64(2), m.seriesCount.Get())
+}
+
+func TestPerMeasurementLimit(t *testing.T) {
+	c, _ := newCardinality(0, "drop")
+	c.Limits = map[string]int{"limited_requests": 1}
+	require.NoError(t, c.Init())
+
+	actual := c.Apply(
+		request("limited_requests", "/a", "GET"),
+		request("limited_requests", "/b", "GET"),
+		request("unlimited_requests", "/a", "GET"),
+		request("unlimited_requests", "/b", "GET"),
+	)
+	expected := []telegraf.Metric{
+		request("limited_requests", "/a", "GET"),
+		request("unlimited_requests", "/a", "GET"),
+		request("unlimited_requests", "/b", "GET"),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestWindow(t *testing.T) {
+	c, now := newCardinality(1, "drop")
+	require.NoError(t, c.Init())
+
+	actual := c.Apply(request("window_requests", "/a", "GET"), request("window_requests", "/b", "GET"))
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{request("window_requests", "/a", "GET")}, actual)
+
+	// The first series is still counted after half the window.
+	*now = now.Add(30 * time.Second)
+	actual = c.Apply(request("window_requests", "/b", "GET"))
+	require.Len(t, actual, 0)
+
+	// The first series expired once it wasn't seen for the window.
+	*now = now.Add(31 * time.Second)
+	actual = c.Apply(request("window_requests", "/b", "GET"))
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{request("window_requests", "/b", "GET")}, actual)
+}
+
+func TestExpireMeasurement(t *testing.T) {
+	c, now := newCardinality(1, "drop")
+	require.NoError(t, c.Init())
+
+	c.Apply(request("expired_requests", "/a", "GET"))
+	require.Contains(t, c.measurements, "expired_requests")
+	require.True(t, hasStat("expired_requests"))
+
+	*now = now.Add(61 * time.Second)
+	c.Apply()
+	require.NotContains(t, c.measurements, "expired_requests")
+	require.False(t, hasStat("expired_requests"))
+}
+
+// hasStat returns true if an internal metric of the measurement is registered.
+func hasStat(name string) bool {
+	for _, m := range selfstat.Metrics() {
+		if m.Name() == "internal_cardinality" && m.Tags()["measurement"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRewrite(t *testing.T) {
+	c, _ := newCardinality(3, "rewrite")
+	require.NoError(t, c.Init())
+
+	actual := c.Apply(
+		request("rewrite_requests", "/a", "GET"),
+		request("rewrite_requests", "/b", "GET"),
+		request("rewrite_requests", "other", "GET"),
+		request("rewrite_requests", "/d", "GET"),
+		request("rewrite_requests", "/e", "GET"),
+	)
+	expected := []telegraf.Metric{
+		request("rewrite_requests", "/a", "GET"),
+		request("rewrite_requests", "/b", "GET"),
+		request("rewrite_requests", "other", "GET"),
+		request("rewrite_requests", "other", "GET"),
+		request("rewrite_requests", "other", "GET"),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	m := c.measurements["rewrite_requests"]
+	require.Equal(t, int64(2), m.rewritten.Get())
+	require.Equal(t, int64(3), m.seriesCount.Get())
+}
+
+func TestRewriteLimited(t *testing.T) {
+	c, _ := newCardinality(2, "rewrite")
+	c.RewriteTags = []string{"path"}
+	require.NoError(t, c.Init())
+
+	// The method still varies once the path is rewritten, so that the
+	// rewritten series are new and over the limit.
+	actual := c.Apply(
+		request("rewrite_limited_requests", "/a", "GET"),
+		request("rewrite_limited_requests", "/b", "GET"),
+		request("rewrite_limited_requests", "/c", "POST"),
+		request("rewrite_limited_requests", "/d", "PUT"),
+	)
+	expected := []telegraf.Metric{
+		request("rewrite_limited_requests", "/a", "GET"),
+		request("rewrite_limited_requests", "/b", "GET"),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+
+	m := c.measurements["rewrite_limited_requests"]
+	require.Equal(t, int64(0), m.rewritten.Get())
+	require.Equal(t, int64(2), m.dropped.Get())
+	require.Equal(t, int64(2), m.seriesCount.Get())
+}
+
+func TestRewriteTags(t *testing.T) {
+	c, _ := newCardinality(2, "rewrite")
+	c.RewriteTags = []string{"method", "user"}
+	require.NoError(t, c.Init())
+
+	actual := c.Apply(
+		request("rewrite_tags_requests", "/a", "GET"),
+		request("rewrite_tags_requests", "/b", "other"),
+		request("rewrite_tags_requests", "/b", "POST"),
+	)
+	expected := []telegraf.Metric{
+		request("rewrite_tags_requests", "/a", "GET"),
+		request("rewrite_tags_requests", "/b", "other"),
+		request("rewrite_tags_requests", "/b", "other"),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestTopTags(t *testing.T) {
+	c, now := newCardinality(0, "drop")
+	require.NoError(t, c.Init())
+
+	c.Apply(
+		testutil.MustMetric("top_requests",
+			map[string]string{"path": "/a", "method": "GET", "host": "web01"},
+			map[string]interface{}{"count": int64(1)}, time.Unix(0, 0)),
+		testutil.MustMetric("top_requests",
+			map[string]string{"path": "/b", "method": "GET", "host": "web01"},
+			map[string]interface{}{"count": int64(1)}, time.Unix(0, 0)),
+		testutil.MustMetric("top_requests",
+			map[string]string{"path": "/c", "method": "POST", "host": "web01"},
+			map[string]interface{}{"count": int64(1)}, time.Unix(0, 0)),
+	)
+	*now = now.Add(10 * time.Second)
+	c.Apply()
+
+	m := c.measurements["top_requests"]
+	require.Len(t, m.tagValues, 2)
+	require.Equal(t, int64(3), m.tagValues["path"].Get())
+	require.Equal(t, int64(2), m.tagValues["method"].Get())
+}
+
+func TestInitErrors(t *testing.T) {
+	c, _ := newCardinality(1, "truncate")
+	require.Error(t, c.Init())
+
+	c, _ = newCardinality(1, "rewrite")
+	c.Placeholder = ""
+	require.Error(t, c.Init())
+
+	c, _ = newCardinality(1, "drop")
+	c.Window = 0
+	require.Error(t, c.Init())
+
+	c, _ = newCardinality(1, "drop")
+	c.TopTags = -1
+	require.Error(t, c.Init())
+}
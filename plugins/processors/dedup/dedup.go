@@ -1,11 +1,15 @@
 package dedup
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/parsers/influx"
 	"github.com/influxdata/telegraf/plugins/processors"
+	serializer "github.com/influxdata/telegraf/plugins/serializers/influx"
 )
 
 var sampleConfig = `
@@ -17,6 +21,9 @@ type Dedup struct {
 	DedupInterval internal.Duration `toml:"dedup_interval"`
 	FlushTime     time.Time
 	Cache         map[uint64]telegraf.Metric
+
+	// mu guards the cache, read by GetState while running.
+	mu sync.Mutex
 }
 
 func (d *Dedup) SampleConfig() string {
@@ -57,6 +64,9 @@ func (d *Dedup) save(metric telegraf.Metric, id uint64) {
 
 // main processing method
 func (d *Dedup) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	for idx, metric := range metrics {
 		id := metric.HashID()
 		m, ok := d.Cache[id]
@@ -117,6 +127,46 @@ func (d *Dedup) Apply(metrics ...telegraf.Metric) []telegraf.Metric {
 	return metrics
 }
 
+// GetState returns the cached metrics in line protocol.
+func (d *Dedup) GetState() interface{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := serializer.NewSerializer()
+	s.SetFieldTypeSupport(serializer.UintSupport)
+	state := make([]string, 0, len(d.Cache))
+	for _, metric := range d.Cache {
+		// Metrics without any serializable field are not worth keeping.
+		buf, err := s.Serialize(metric)
+		if err != nil {
+			continue
+		}
+		state = append(state, string(buf))
+	}
+	return state
+}
+
+// SetState restores the cached metrics from line protocol.
+func (d *Dedup) SetState(state interface{}) error {
+	lines, ok := state.([]string)
+	if !ok {
+		return fmt.Errorf("invalid state of type %T", state)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	parser := influx.NewParser(influx.NewMetricHandler())
+	for _, line := range lines {
+		metric, err := parser.ParseLine(line)
+		if err != nil {
+			return fmt.Errorf("invalid cached metric %q: %v", line, err)
+		}
+		d.Cache[metric.HashID()] = metric
+	}
+	return nil
+}
+
 func init() {
 	processors.Add("dedup", func() telegraf.Processor {
 		return &Dedup{
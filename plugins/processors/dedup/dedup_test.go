@@ -192,3 +192,20 @@ func TestSameTimestamp(t *testing.T) {
 	out = dedup.Apply(in)
 	require.Equal(t, []telegraf.Metric{}, out) // drop
 }
+
+func TestState(t *testing.T) {
+	deduplicate := createDedup(time.Now())
+	// The monotonic clock reading is not part of the state.
+	source := createMetric("m1", 1, time.Now().Add(-1*time.Second).Round(0))
+	deduplicate.Apply(source)
+	state := deduplicate.GetState()
+
+	// A new instance restored from the state suppresses the repeated value.
+	restored := createDedup(time.Now())
+	require.NoError(t, restored.SetState(state))
+	assertCacheRefresh(t, &restored, source)
+
+	source = createMetric("m1", 1, time.Now())
+	target := restored.Apply(source)
+	assertMetricSuppressed(t, target, source)
+}
@@ -25,6 +25,10 @@ var sampleConfig = `
   ## Name of tag of the SNMP agent to request the interface name from
   # agent = "agent"
 
+  ## Paths of the MIB files used to look up the interface tables;
+  ## directories are searched recursively.
+  # path = ["/usr/share/snmp/mibs"]
+
   ## Timeout for each request.
   # timeout = "5s"
 
@@ -133,7 +137,7 @@ func (d *IfName) Init() error {
 
 	d.sigs = make(sigMap)
 
-	return nil
+	return snmp.LoadMibsFromPath(d.Path, d.Log)
 }
 
 func (d *IfName) addTag(metric telegraf.Metric) error {
@@ -341,6 +345,7 @@ func init() {
 				Timeout:        internal.Duration{Duration: 5 * time.Second},
 				Version:        2,
 				Community:      "public",
+				Path:           snmp.DefaultMibPath,
 			},
 			CacheTTL: config.Duration(8 * time.Hour),
 		}
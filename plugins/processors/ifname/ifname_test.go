@@ -43,6 +43,24 @@ func TestTable(t *testing.T) {
 	require.NotEmpty(t, m)
 }
 
+func TestMakeTable(t *testing.T) {
+	d := IfName{
+		ClientConfig: snmp.ClientConfig{
+			Path: []string{"testdata/mibs"},
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, d.Init())
+
+	tab, err := d.makeTable("IF-MIB::ifTable")
+	require.NoError(t, err)
+	require.Equal(t, "ifTable", tab.Name)
+	require.True(t, tab.IndexAsTag)
+	require.Len(t, tab.Fields, 3)
+	require.Equal(t, ".1.3.6.1.2.1.2.2.1.2", tab.Fields[1].Oid)
+	require.Equal(t, "ifDescr", tab.Fields[1].Name)
+}
+
 func TestIfName(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -98,7 +116,7 @@ func TestGetMap(t *testing.T) {
 		CacheTTL:  config.Duration(10 * time.Second),
 	}
 
-	// Don't run net-snmp commands to look up table names.
+	// Don't look up table names in the MIBs.
 	d.makeTable = func(agent string) (*si.Table, error) {
 		return &si.Table{}, nil
 	}
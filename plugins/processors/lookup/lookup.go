@@ -0,0 +1,229 @@
+package lookup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const sampleConfig = `
+  ## Path of the file holding the table.
+  file = "/etc/telegraf/hosts.csv"
+
+  ## Format of the file; one of "csv", "json" or "sqlite".
+  ##   csv:    the first row holds the column names.
+  ##   json:   an array of objects, with the column names as keys.
+  ##   sqlite: a database queried with the "query" option.
+  # format = "csv"
+
+  ## Query selecting the table from a SQLite database.
+  # query = "SELECT host, owner, rack, tier FROM hosts"
+
+  ## Tags whose values are matched against the key columns of the table.
+  key_tags = ["host"]
+
+  ## Columns of the table holding the key, matched in order to the values of
+  ## the key tags.  Defaults to the names of the key tags.
+  # key_columns = ["hostname"]
+
+  ## Columns added as tags to the matching metrics.  All the columns but the
+  ## key columns and the field columns are added by default.
+  # tag_columns = ["owner", "rack"]
+
+  ## Columns added as fields to the matching metrics.
+  # field_columns = ["tier"]
+
+  ## Interval of checking the file for changes, reloading the table when it
+  ## changed; set to 0 to never reload the table.
+  # reload_interval = "30s"
+`
+
+type Lookup struct {
+	File           string          `toml:"file"`
+	Format         string          `toml:"format"`
+	Query          string          `toml:"query"`
+	KeyTags        []string        `toml:"key_tags"`
+	KeyColumns     []string        `toml:"key_columns"`
+	TagColumns     []string        `toml:"tag_columns"`
+	FieldColumns   []string        `toml:"field_columns"`
+	ReloadInterval config.Duration `toml:"reload_interval"`
+	Log            telegraf.Logger `toml:"-"`
+
+	sync.RWMutex
+	table   *table
+	modTime time.Time
+	size    int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (l *Lookup) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *Lookup) Description() string {
+	return "Add tags and fields from a table of a CSV, JSON or SQLite file to the matching metrics"
+}
+
+func (l *Lookup) Init() error {
+	if l.File == "" {
+		return fmt.Errorf("file must be set")
+	}
+	if len(l.KeyTags) == 0 {
+		return fmt.Errorf("key_tags must be set")
+	}
+	if len(l.KeyColumns) == 0 {
+		l.KeyColumns = l.KeyTags
+	}
+	if len(l.KeyColumns) != len(l.KeyTags) {
+		return fmt.Errorf("key_columns must have as many columns as key_tags has tags")
+	}
+
+	switch l.Format {
+	case "":
+		l.Format = "csv"
+	case "csv", "json":
+	case "sqlite":
+		if l.Query == "" {
+			return fmt.Errorf("query must be set for the sqlite format")
+		}
+	default:
+		return fmt.Errorf("unknown format %q", l.Format)
+	}
+
+	return l.load()
+}
+
+func (l *Lookup) Start(acc telegraf.Accumulator) error {
+	l.done = make(chan struct{})
+	if l.ReloadInterval <= 0 {
+		return nil
+	}
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(time.Duration(l.ReloadInterval))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.done:
+				return
+			case <-ticker.C:
+				l.reloadIfChanged()
+			}
+		}
+	}()
+	return nil
+}
+
+func (l *Lookup) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
+	l.apply(metric)
+	acc.AddMetric(metric)
+	return nil
+}
+
+func (l *Lookup) Stop() error {
+	close(l.done)
+	l.wg.Wait()
+	return nil
+}
+
+// apply adds the tags and fields of the row matching the metric.
+func (l *Lookup) apply(metric telegraf.Metric) {
+	values := make([]string, 0, len(l.KeyTags))
+	for _, key := range l.KeyTags {
+		value, ok := metric.GetTag(key)
+		if !ok {
+			return
+		}
+		values = append(values, value)
+	}
+
+	l.RLock()
+	row, ok := l.table.rows[makeKey(values)]
+	l.RUnlock()
+	if !ok {
+		return
+	}
+
+	for key, value := range row.tags {
+		metric.AddTag(key, value)
+	}
+	for key, value := range row.fields {
+		metric.AddField(key, value)
+	}
+}
+
+// reloadIfChanged reloads the table if the file changed since it was last
+// loaded.  The previous table is kept if the file can't be loaded.
+func (l *Lookup) reloadIfChanged() {
+	info, err := os.Stat(l.File)
+	if err != nil {
+		l.Log.Errorf("Checking %q for changes failed: %v", l.File, err)
+		return
+	}
+	if info.ModTime().Equal(l.modTime) && info.Size() == l.size {
+		return
+	}
+
+	if err := l.load(); err != nil {
+		l.Log.Errorf("Reloading table failed, keeping the previous table: %v", err)
+		return
+	}
+	l.Log.Debugf("Reloaded table from %q", l.File)
+}
+
+// load reads the table from the file.
+func (l *Lookup) load() error {
+	info, err := os.Stat(l.File)
+	if err != nil {
+		return err
+	}
+
+	var records []record
+	switch l.Format {
+	case "json":
+		records, err = readJSON(l.File)
+	case "sqlite":
+		records, err = readSQLite(l.File, l.Query)
+	default:
+		records, err = readCSV(l.File)
+	}
+	if err != nil {
+		return fmt.Errorf("reading %q failed: %v", l.File, err)
+	}
+
+	t, err := newTable(records, l.KeyColumns, l.TagColumns, l.FieldColumns)
+	if err != nil {
+		return fmt.Errorf("reading %q failed: %v", l.File, err)
+	}
+
+	l.Lock()
+	l.table = t
+	l.modTime = info.ModTime()
+	l.size = info.Size()
+	l.Unlock()
+	return nil
+}
+
+// makeKey returns the key of a row from the values of its key columns.
+func makeKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+func init() {
+	processors.AddStreaming("lookup", func() telegraf.StreamingProcessor {
+		return &Lookup{
+			Format:         "csv",
+			ReloadInterval: config.Duration(30 * time.Second),
+		}
+	})
+}
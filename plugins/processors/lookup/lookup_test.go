@@ -0,0 +1,246 @@
+package lookup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const hostsCSV = `host,owner,rack,tier
+web01,alice,r1,1
+web02,bob,r2,2
+db01,carol,,3
+`
+
+const hostsJSON = `[
+  {"host": "web01", "owner": "alice", "rack": "r1", "tier": 1},
+  {"host": "web02", "owner": "bob", "rack": "r2", "tier": 2.5},
+  {"host": "db01", "owner": "carol", "rack": null, "tier": 3}
+]`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func newLookup(path, format string) *Lookup {
+	return &Lookup{
+		File:         path,
+		Format:       format,
+		KeyTags:      []string{"host"},
+		FieldColumns: []string{"tier"},
+		Log:          testutil.Logger{},
+	}
+}
+
+func process(t *testing.T, plugin *Lookup, metrics ...telegraf.Metric) []telegraf.Metric {
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	for _, m := range metrics {
+		require.NoError(t, plugin.Add(m, &acc))
+	}
+	require.NoError(t, plugin.Stop())
+	return acc.GetTelegrafMetrics()
+}
+
+func TestLookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		file     string
+		format   string
+		expected []telegraf.Metric
+	}{
+		{
+			name:   "csv",
+			file:   writeFile(t, dir, "hosts.csv", hostsCSV),
+			format: "csv",
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "web01", "owner": "alice", "rack": "r1"},
+					map[string]interface{}{"usage": 42.0, "tier": int64(1)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "db01", "owner": "carol"},
+					map[string]interface{}{"usage": 42.0, "tier": int64(3)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "unknown"},
+					map[string]interface{}{"usage": 42.0},
+					time.Unix(0, 0)),
+			},
+		},
+		{
+			name:   "json",
+			file:   writeFile(t, dir, "hosts.json", hostsJSON),
+			format: "json",
+			expected: []telegraf.Metric{
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "web01", "owner": "alice", "rack": "r1"},
+					map[string]interface{}{"usage": 42.0, "tier": int64(1)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "db01", "owner": "carol"},
+					map[string]interface{}{"usage": 42.0, "tier": int64(3)},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "unknown"},
+					map[string]interface{}{"usage": 42.0},
+					time.Unix(0, 0)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := newLookup(tt.file, tt.format)
+			require.NoError(t, plugin.Init())
+
+			actual := process(t, plugin,
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "web01"},
+					map[string]interface{}{"usage": 42.0},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "db01"},
+					map[string]interface{}{"usage": 42.0},
+					time.Unix(0, 0)),
+				testutil.MustMetric("cpu",
+					map[string]string{"host": "unknown"},
+					map[string]interface{}{"usage": 42.0},
+					time.Unix(0, 0)),
+			)
+			testutil.RequireMetricsEqual(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestLookupMultipleKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeFile(t, dir, "ports.csv", "device,port,service\nsw1,1,uplink\nsw1,2,storage\nsw2,1,backup\n")
+	plugin := &Lookup{
+		File:       path,
+		KeyTags:    []string{"agent", "ifIndex"},
+		KeyColumns: []string{"device", "port"},
+		TagColumns: []string{"service"},
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	actual := process(t, plugin,
+		testutil.MustMetric("interface",
+			map[string]string{"agent": "sw1", "ifIndex": "2"},
+			map[string]interface{}{"in_octets": int64(1)},
+			time.Unix(0, 0)),
+		testutil.MustMetric("interface",
+			map[string]string{"agent": "sw2"},
+			map[string]interface{}{"in_octets": int64(1)},
+			time.Unix(0, 0)),
+	)
+	expected := []telegraf.Metric{
+		testutil.MustMetric("interface",
+			map[string]string{"agent": "sw1", "ifIndex": "2", "service": "storage"},
+			map[string]interface{}{"in_octets": int64(1)},
+			time.Unix(0, 0)),
+		testutil.MustMetric("interface",
+			map[string]string{"agent": "sw2"},
+			map[string]interface{}{"in_octets": int64(1)},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
+
+func TestReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeFile(t, dir, "hosts.csv", hostsCSV)
+	plugin := newLookup(path, "csv")
+	require.NoError(t, plugin.Init())
+
+	input := testutil.MustMetric("cpu",
+		map[string]string{"host": "web01"},
+		map[string]interface{}{"usage": 42.0},
+		time.Unix(0, 0))
+
+	m := input.Copy()
+	plugin.apply(m)
+	require.Equal(t, "alice", m.Tags()["owner"])
+
+	writeFile(t, dir, "hosts.csv", "host,owner,rack,tier\nweb01,dave,r9,4\n")
+	plugin.reloadIfChanged()
+	m = input.Copy()
+	plugin.apply(m)
+	require.Equal(t, "dave", m.Tags()["owner"])
+
+	// A table that can't be loaded is not used.
+	writeFile(t, dir, "hosts.csv", "host,owner\nweb01,erin,extra\n")
+	plugin.reloadIfChanged()
+	m = input.Copy()
+	plugin.apply(m)
+	require.Equal(t, "dave", m.Tags()["owner"])
+}
+
+func TestInitErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeFile(t, dir, "hosts.csv", hostsCSV)
+	tests := []struct {
+		name   string
+		plugin *Lookup
+	}{
+		{
+			name:   "missing file",
+			plugin: newLookup(filepath.Join(dir, "missing.csv"), "csv"),
+		},
+		{
+			name:   "unknown format",
+			plugin: newLookup(path, "xml"),
+		},
+		{
+			name:   "sqlite without query",
+			plugin: newLookup(path, "sqlite"),
+		},
+		{
+			name:   "invalid json",
+			plugin: newLookup(path, "json"),
+		},
+		{
+			name: "key column mismatch",
+			plugin: &Lookup{
+				File:       path,
+				KeyTags:    []string{"host"},
+				KeyColumns: []string{"host", "rack"},
+			},
+		},
+		{
+			name: "key column as field",
+			plugin: &Lookup{
+				File:         path,
+				KeyTags:      []string{"host"},
+				FieldColumns: []string{"host"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
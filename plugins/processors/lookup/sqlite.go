@@ -0,0 +1,54 @@
+// +build linux
+// +build 386 amd64 arm arm64
+
+package lookup
+
+import (
+	"database/sql"
+
+	_ "modernc.org/sqlite" //to register SQLite driver
+)
+
+// readSQLite reads the records selected by a query from a SQLite database.
+func readSQLite(path string, query string) ([]record, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []record
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		rec := make(record, len(columns))
+		for i, column := range columns {
+			switch v := values[i].(type) {
+			case []byte:
+				rec[column] = string(v)
+			default:
+				rec[column] = v
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
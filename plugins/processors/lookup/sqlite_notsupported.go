@@ -0,0 +1,11 @@
+// +build !linux linux,!386,!amd64,!arm,!arm64
+
+package lookup
+
+import (
+	"fmt"
+)
+
+func readSQLite(path string, query string) ([]record, error) {
+	return nil, fmt.Errorf("the sqlite format is not supported on this platform")
+}
@@ -0,0 +1,64 @@
+// +build linux
+// +build 386 amd64 arm arm64
+
+package lookup
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lookup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "cmdb.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE hosts (name TEXT, owner TEXT, rack TEXT, tier INTEGER);
+		INSERT INTO hosts VALUES ('web01', 'alice', 'r1', 1), ('db01', 'carol', NULL, 3);`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	plugin := &Lookup{
+		File:         path,
+		Format:       "sqlite",
+		Query:        "SELECT name, owner, rack, tier FROM hosts",
+		KeyTags:      []string{"host"},
+		KeyColumns:   []string{"name"},
+		FieldColumns: []string{"tier"},
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	actual := process(t, plugin,
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "web01"},
+			map[string]interface{}{"usage": 42.0},
+			time.Unix(0, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "db01"},
+			map[string]interface{}{"usage": 42.0},
+			time.Unix(0, 0)),
+	)
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "web01", "owner": "alice", "rack": "r1"},
+			map[string]interface{}{"usage": 42.0, "tier": int64(1)},
+			time.Unix(0, 0)),
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "db01", "owner": "carol"},
+			map[string]interface{}{"usage": 42.0, "tier": int64(3)},
+			time.Unix(0, 0)),
+	}
+	testutil.RequireMetricsEqual(t, expected, actual)
+}
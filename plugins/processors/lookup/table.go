@@ -0,0 +1,173 @@
+package lookup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// record is a row read from a file, by column name.  Values are strings,
+// or numbers and booleans in formats that have them.
+type record map[string]interface{}
+
+// row holds the tags and fields added to the metrics matching a key.
+type row struct {
+	tags   map[string]string
+	fields map[string]interface{}
+}
+
+// table holds the rows by key.
+type table struct {
+	rows map[string]row
+}
+
+// newTable builds the table from the records.  Records with a missing key
+// column are ignored, and a later record replaces an earlier one with the
+// same key.
+func newTable(records []record, keyColumns, tagColumns, fieldColumns []string) (*table, error) {
+	isKey := make(map[string]bool, len(keyColumns))
+	for _, column := range keyColumns {
+		isKey[column] = true
+	}
+	isField := make(map[string]bool, len(fieldColumns))
+	for _, column := range fieldColumns {
+		if isKey[column] {
+			return nil, fmt.Errorf("key column %q can't be a field column", column)
+		}
+		isField[column] = true
+	}
+	isTag := make(map[string]bool, len(tagColumns))
+	for _, column := range tagColumns {
+		if isKey[column] {
+			return nil, fmt.Errorf("key column %q can't be a tag column", column)
+		}
+		isTag[column] = true
+	}
+
+	t := &table{rows: make(map[string]row, len(records))}
+	for _, rec := range records {
+		values := make([]string, 0, len(keyColumns))
+		for _, column := range keyColumns {
+			value, ok := rec[column]
+			if !ok || value == nil {
+				break
+			}
+			values = append(values, fmt.Sprint(value))
+		}
+		if len(values) != len(keyColumns) {
+			continue
+		}
+
+		r := row{
+			tags:   make(map[string]string),
+			fields: make(map[string]interface{}),
+		}
+		for column, value := range rec {
+			if value == nil || isKey[column] {
+				continue
+			}
+			switch {
+			case isField[column]:
+				r.fields[column] = fieldValue(value)
+			case len(tagColumns) == 0 || isTag[column]:
+				r.tags[column] = fmt.Sprint(value)
+			}
+		}
+		t.rows[makeKey(values)] = r
+	}
+	return t, nil
+}
+
+// fieldValue returns the value of a field from a value read from a file.
+// Strings holding a number or a boolean are converted.
+func fieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+		return v
+	case []byte:
+		return fieldValue(string(v))
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// readCSV reads the records of a CSV file with a header row.
+func readCSV(path string) ([]record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	header := rows[0]
+	records := make([]record, 0, len(rows)-1)
+	for _, values := range rows[1:] {
+		rec := make(record, len(header))
+		for i, column := range header {
+			// Empty cells are missing values.
+			if values[i] != "" {
+				rec[column] = values[i]
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// readJSON reads the records of a JSON file holding an array of objects.
+func readJSON(path string) ([]record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	decoder.UseNumber()
+	var objects []map[string]interface{}
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, err
+	}
+
+	records := make([]record, 0, len(objects))
+	for _, object := range objects {
+		rec := make(record, len(object))
+		for column, value := range object {
+			switch v := value.(type) {
+			case string, bool, json.Number, nil:
+				rec[column] = v
+			default:
+				return nil, fmt.Errorf("unsupported value of column %q: %v", column, v)
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
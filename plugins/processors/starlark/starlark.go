@@ -3,13 +3,13 @@ package starlark
 import (
 	"errors"
 	"fmt"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/influxdata/telegraf"
+	common "github.com/influxdata/telegraf/plugins/common/starlark"
 	"github.com/influxdata/telegraf/plugins/processors"
-	"go.starlark.net/resolve"
 	"go.starlark.net/starlark"
-	"go.starlark.net/starlarkjson"
 )
 
 const (
@@ -27,82 +27,105 @@ def apply(metric):
 
   ## File containing a Starlark script.
   # script = "/usr/local/bin/myscript.star"
+
+  ## Allow the script to modify the predeclared dict "state", which is kept
+  ## between calls of the functions of the script.
+  # mutable_state = false
+
+  ## File the state is saved to when Telegraf stops, and loaded from when it
+  ## starts, requires mutable_state.
+  # state_file = ""
 `
 )
 
 type Starlark struct {
-	Source string `toml:"source"`
-	Script string `toml:"script"`
+	Source       string `toml:"source"`
+	Script       string `toml:"script"`
+	MutableState bool   `toml:"mutable_state"`
+	StateFile    string `toml:"state_file"`
 
 	Log telegraf.Logger `toml:"-"`
 
+	// mu serializes the calls of the script, made by Add and the periodic
+	// functions.
+	mu        sync.Mutex
 	thread    *starlark.Thread
 	applyFunc *starlark.Function
 	args      starlark.Tuple
 	results   []telegraf.Metric
+	state     *starlark.Dict
+
+	// periodic are the functions registered by the script to be called at an
+	// interval; they can only be registered while the script is loaded.
+	periodic []periodicFunc
+	loading  bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+type periodicFunc struct {
+	interval time.Duration
+	fn       starlark.Callable
 }
 
 func (s *Starlark) Init() error {
-	if s.Source == "" && s.Script == "" {
-		return errors.New("one of source or script must be set")
-	}
-	if s.Source != "" && s.Script != "" {
-		return errors.New("both source or script cannot be set")
+	if s.StateFile != "" && !s.MutableState {
+		return errors.New("state_file requires mutable_state")
 	}
 
-	s.thread = &starlark.Thread{
-		Print: func(_ *starlark.Thread, msg string) { s.Log.Debug(msg) },
-		Load: func(thread *starlark.Thread, module string) (starlark.StringDict, error) {
-			return loadFunc(thread, module, s.Log)
-		},
-	}
+	s.thread = common.NewThread(s.Log)
 
-	builtins := starlark.StringDict{}
-	builtins["Metric"] = starlark.NewBuiltin("Metric", newMetric)
-	builtins["deepcopy"] = starlark.NewBuiltin("deepcopy", deepcopy)
-	builtins["catch"] = starlark.NewBuiltin("catch", catch)
+	builtins := common.Builtins()
+	builtins["register_periodic"] = starlark.NewBuiltin("register_periodic", s.registerPeriodic)
 
-	program, err := s.sourceProgram(builtins)
-	if err != nil {
-		return err
+	// The mutable state is predeclared, so that it is not frozen with the
+	// globals of the script.
+	if s.MutableState {
+		s.state = starlark.NewDict(0)
+		if s.StateFile != "" {
+			state, err := loadState(s.StateFile)
+			if err != nil {
+				return err
+			}
+			s.state = state
+		}
+		builtins["state"] = s.state
 	}
 
-	// Execute source
-	globals, err := program.Init(s.thread, builtins)
+	s.loading = true
+	globals, err := common.LoadScript(s.thread, "processor.starlark", s.Source, s.Script, builtins)
+	s.loading = false
 	if err != nil {
 		return err
 	}
 
-	// Make available a shared state to the apply function
-	globals["state"] = starlark.NewDict(0)
+	if s.MutableState {
+		if _, ok := globals["state"]; ok {
+			return errors.New("state is predeclared with mutable_state and cannot be defined")
+		}
+	} else {
+		// Make available a shared state to the apply function
+		globals["state"] = starlark.NewDict(0)
+	}
 
 	// Freeze the global state.  This prevents modifications to the processor
 	// state and prevents scripts from containing errors storing tracking
-	// metrics.  Tasks that require global state will not be possible due to
-	// this, so maybe we should relax this in the future.
+	// metrics.  Tasks that require global state must opt in to the mutable
+	// state, which is not part of the globals.
 	globals.Freeze()
 
 	// The source should define an apply function.
-	apply := globals["apply"]
-
-	if apply == nil {
-		return errors.New("apply is not defined")
-	}
-
-	var ok bool
-	if s.applyFunc, ok = apply.(*starlark.Function); !ok {
-		return errors.New("apply is not a function")
-	}
-
-	if s.applyFunc.NumParams() != 1 {
-		return errors.New("apply function must take one parameter")
+	s.applyFunc, err = common.Function(globals, "apply", 1)
+	if err != nil {
+		return err
 	}
 
 	// Reusing the same metric wrapper to skip an allocation.  This will cause
 	// any saved references to point to the new metric, but due to freezing the
-	// globals none should exist.
+	// globals none should exist.  With the mutable state a new wrapper is used
+	// for each metric instead.
 	s.args = make(starlark.Tuple, 1)
-	s.args[0] = &Metric{}
+	s.args[0] = &common.Metric{}
 
 	// Preallocate a slice for return values.
 	s.results = make([]telegraf.Metric, 0, 10)
@@ -110,15 +133,6 @@ func (s *Starlark) Init() error {
 	return nil
 }
 
-func (s *Starlark) sourceProgram(builtins starlark.StringDict) (*starlark.Program, error) {
-	if s.Source != "" {
-		_, program, err := starlark.SourceProgram("processor.starlark", s.Source, builtins.Has)
-		return program, err
-	}
-	_, program, err := starlark.SourceProgram(s.Script, nil, builtins.Has)
-	return program, err
-}
-
 func (s *Starlark) SampleConfig() string {
 	return sampleConfig
 }
@@ -127,20 +141,154 @@ func (s *Starlark) Description() string {
 	return description
 }
 
+// GetState returns the mutable state as JSON values, or nil without the
+// mutable state.
+func (s *Starlark) GetState() interface{} {
+	if !s.MutableState {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, err := toJSON(s.state)
+	if err != nil {
+		s.Log.Errorf("Saving state failed: %v", err)
+		return nil
+	}
+	return v
+}
+
+// SetState replaces the contents of the mutable state, which stays the dict
+// predeclared to the script.
+func (s *Starlark) SetState(state interface{}) error {
+	saved, ok := state.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid state of type %T", state)
+	}
+	v, err := fromJSON(saved)
+	if err != nil {
+		return err
+	}
+	dict, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("invalid state of type %s", v.Type())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.state.Clear(); err != nil {
+		return err
+	}
+	for _, item := range dict.Items() {
+		if err := s.state.SetKey(item[0], item[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Starlark) Start(acc telegraf.Accumulator) error {
+	s.done = make(chan struct{})
+	for _, p := range s.periodic {
+		s.wg.Add(1)
+		go s.runPeriodic(p, acc)
+	}
 	return nil
 }
 
-func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
-	s.args[0].(*Metric).Wrap(metric)
+// registerPeriodic implements register_periodic(interval, function), which
+// registers a function without parameters to be called every interval, such
+// as "10s".  The metrics returned by the function are emitted like those of
+// apply.
+func (s *Starlark) registerPeriodic(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var interval string
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "interval", &interval, "function", &fn); err != nil {
+		return nil, err
+	}
+	if !s.loading {
+		return nil, fmt.Errorf("%s: can only be called when the script is loaded", b.Name())
+	}
 
-	rv, err := starlark.Call(s.thread, s.applyFunc, s.args, nil)
+	d, err := time.ParseDuration(interval)
 	if err != nil {
-		if err, ok := err.(*starlark.EvalError); ok {
-			for _, line := range strings.Split(err.Backtrace(), "\n") {
-				s.Log.Error(line)
+		return nil, fmt.Errorf("%s: %v", b.Name(), err)
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("%s: interval must be positive", b.Name())
+	}
+	if f, ok := fn.(*starlark.Function); ok && f.NumParams() != 0 {
+		return nil, fmt.Errorf("%s: function must take no parameters", b.Name())
+	}
+
+	s.periodic = append(s.periodic, periodicFunc{interval: d, fn: fn})
+	return starlark.None, nil
+}
+
+func (s *Starlark) runPeriodic(p periodicFunc, acc telegraf.Accumulator) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.callPeriodic(p, acc)
+		}
+	}
+}
+
+func (s *Starlark) callPeriodic(p periodicFunc, acc telegraf.Accumulator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rv, err := starlark.Call(s.thread, p.fn, nil, nil)
+	if err != nil {
+		common.LogError(s.Log, err)
+		s.Log.Errorf("Error calling %s: %v", p.fn.Name(), err)
+		return
+	}
+
+	// The metrics are copied, as the function may return metrics it keeps in
+	// the state.
+	switch rv := rv.(type) {
+	case *starlark.List:
+		iter := rv.Iterate()
+		defer iter.Done()
+		var v starlark.Value
+		for iter.Next(&v) {
+			switch v := v.(type) {
+			case *common.Metric:
+				acc.AddMetric(v.Unwrap().Copy())
+			default:
+				s.Log.Errorf("Invalid type returned in list: %s", v.Type())
 			}
 		}
+	case *common.Metric:
+		acc.AddMetric(rv.Unwrap().Copy())
+	case starlark.NoneType:
+	default:
+		s.Log.Errorf("Invalid type returned: %T", rv)
+	}
+}
+
+func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	args := s.args
+	if s.MutableState {
+		args = starlark.Tuple{&common.Metric{}}
+	}
+	args[0].(*common.Metric).Wrap(metric)
+
+	rv, err := starlark.Call(s.thread, s.applyFunc, args, nil)
+	if err != nil {
+		common.LogError(s.Log, err)
 		metric.Reject()
 		return err
 	}
@@ -152,7 +300,7 @@ func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
 		var v starlark.Value
 		for iter.Next(&v) {
 			switch v := v.(type) {
-			case *Metric:
+			case *common.Metric:
 				m := v.Unwrap()
 				if containsMetric(s.results, m) {
 					s.Log.Errorf("Duplicate metric reference detected")
@@ -176,7 +324,7 @@ func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
 			s.results[i] = nil
 		}
 		s.results = s.results[:0]
-	case *Metric:
+	case *common.Metric:
 		m := rv.Unwrap()
 
 		// If the script returned a different metric, mark this metric as
@@ -194,7 +342,18 @@ func (s *Starlark) Add(metric telegraf.Metric, acc telegraf.Accumulator) error {
 }
 
 func (s *Starlark) Stop() error {
-	return nil
+	if s.done != nil {
+		close(s.done)
+		s.wg.Wait()
+	}
+
+	if s.StateFile == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return saveState(s.StateFile, s.state)
 }
 
 func containsMetric(metrics []telegraf.Metric, metric telegraf.Metric) bool {
@@ -206,33 +365,8 @@ func containsMetric(metrics []telegraf.Metric, metric telegraf.Metric) bool {
 	return false
 }
 
-func init() {
-	// https://github.com/bazelbuild/starlark/issues/20
-	resolve.AllowNestedDef = true
-	resolve.AllowLambda = true
-	resolve.AllowFloat = true
-	resolve.AllowSet = true
-	resolve.AllowGlobalReassign = true
-	resolve.AllowRecursion = true
-}
-
 func init() {
 	processors.AddStreaming("starlark", func() telegraf.StreamingProcessor {
 		return &Starlark{}
 	})
 }
-
-func loadFunc(thread *starlark.Thread, module string, logger telegraf.Logger) (starlark.StringDict, error) {
-	switch module {
-	case "json.star":
-		return starlark.StringDict{
-			"json": starlarkjson.Module,
-		}, nil
-	case "logging.star":
-		return starlark.StringDict{
-			"log": LogModule(logger),
-		}, nil
-	default:
-		return nil, errors.New("module " + module + " is not available")
-	}
-}
@@ -1,6 +1,7 @@
 package starlark
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -3055,6 +3056,346 @@ func TestAllScriptTestData(t *testing.T) {
 	}
 }
 
+func TestMutableState(t *testing.T) {
+	plugin := &Starlark{
+		Source: `
+def apply(metric):
+	last = state.get(metric.name)
+	state[metric.name] = metric.fields["value"]
+	if last == None:
+		return None
+	if metric.fields["value"] < last:
+		metric.fields["reset"] = True
+		return metric
+	metric.fields["delta"] = metric.fields["value"] - last
+	return metric
+`,
+		MutableState: true,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	for _, value := range []int{10, 15, 3} {
+		require.NoError(t, plugin.Add(testutil.MustMetric("requests",
+			map[string]string{},
+			map[string]interface{}{"value": value},
+			time.Unix(0, 0),
+		), &acc))
+	}
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("requests",
+			map[string]string{},
+			map[string]interface{}{"value": 15, "delta": 5},
+			time.Unix(0, 0),
+		),
+		testutil.MustMetric("requests",
+			map[string]string{},
+			map[string]interface{}{"value": 3, "reset": true},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestMutableStateInitError(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin *Starlark
+	}{
+		{
+			name: "state_file requires mutable_state",
+			plugin: &Starlark{
+				Source: `
+def apply(metric):
+	return metric
+`,
+				StateFile: "state.json",
+				Log:       testutil.Logger{},
+			},
+		},
+		{
+			name: "state cannot be defined",
+			plugin: &Starlark{
+				Source: `
+state = {}
+
+def apply(metric):
+	return metric
+`,
+				MutableState: true,
+				Log:          testutil.Logger{},
+			},
+		},
+		{
+			name: "register_periodic interval must be valid",
+			plugin: &Starlark{
+				Source: `
+def tick():
+	pass
+
+register_periodic("never", tick)
+
+def apply(metric):
+	return metric
+`,
+				Log: testutil.Logger{},
+			},
+		},
+		{
+			name: "register_periodic function must take no args",
+			plugin: &Starlark{
+				Source: `
+def tick(metric):
+	pass
+
+register_periodic("1s", tick)
+
+def apply(metric):
+	return metric
+`,
+				Log: testutil.Logger{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plugin.Init()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "starlark")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	stateFile := filepath.Join(dir, "state.json")
+
+	source := `
+def apply(metric):
+	state["count"] = state.get("count", 0) + 1
+	state["ratio"] = 2.0
+	state["names"] = [metric.name]
+	last = state.get("last")
+	state["last"] = deepcopy(metric)
+	if last != None:
+		metric.fields["count"] = state["count"]
+		metric.fields["last"] = last.fields["value"]
+		metric.fields["ratio"] = state["ratio"]
+	return metric
+`
+
+	input := testutil.MustMetric("cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"value": 1.0},
+		time.Unix(0, 0),
+	)
+
+	plugin := &Starlark{
+		Source:       source,
+		MutableState: true,
+		StateFile:    stateFile,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	require.NoError(t, plugin.Add(input, &acc))
+	require.NoError(t, plugin.Stop())
+
+	// The state is loaded by a new plugin, as when Telegraf is restarted.
+	plugin = &Starlark{
+		Source:       source,
+		MutableState: true,
+		StateFile:    stateFile,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Start(&acc))
+	require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"value": 2.0},
+		time.Unix(1, 0),
+	), &acc))
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"value": 2.0, "count": 2, "last": 1.0, "ratio": 2.0},
+			time.Unix(1, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestGetSetState(t *testing.T) {
+	source := `
+def apply(metric):
+	state["count"] = state.get("count", 0) + 1
+	last = state.get("last")
+	state["last"] = deepcopy(metric)
+	if last != None:
+		metric.fields["count"] = state["count"]
+		metric.fields["last"] = last.fields["value"]
+	return metric
+`
+
+	plugin := &Starlark{
+		Source:       source,
+		MutableState: true,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"value": 1.0},
+		time.Unix(0, 0),
+	), &acc))
+	require.NoError(t, plugin.Stop())
+
+	// The state is encoded and decoded as the agent does with the state
+	// file.
+	buf, err := json.Marshal(plugin.GetState())
+	require.NoError(t, err)
+	decoder := json.NewDecoder(strings.NewReader(string(buf)))
+	decoder.UseNumber()
+	var state map[string]interface{}
+	require.NoError(t, decoder.Decode(&state))
+
+	plugin = &Starlark{
+		Source:       source,
+		MutableState: true,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.NoError(t, plugin.SetState(state))
+	acc.ClearMetrics()
+	require.NoError(t, plugin.Start(&acc))
+	require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{"host": "a"},
+		map[string]interface{}{"value": 2.0},
+		time.Unix(1, 0),
+	), &acc))
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("cpu",
+			map[string]string{"host": "a"},
+			map[string]interface{}{"value": 2.0, "count": 2, "last": 1.0},
+			time.Unix(1, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics())
+}
+
+func TestGetStateImmutable(t *testing.T) {
+	plugin := &Starlark{
+		Source: `
+def apply(metric):
+	return metric
+`,
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	require.Nil(t, plugin.GetState())
+}
+
+func TestStateFileUnsupportedValue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "starlark")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	plugin := &Starlark{
+		Source: `
+def apply(metric):
+	state[1] = "value"
+	return metric
+`,
+		MutableState: true,
+		StateFile:    filepath.Join(dir, "state.json"),
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0),
+	), &acc))
+	require.Error(t, plugin.Stop())
+}
+
+func TestRegisterPeriodic(t *testing.T) {
+	plugin := &Starlark{
+		Source: `
+def apply(metric):
+	state["count"] = state.get("count", 0) + 1
+	return None
+
+def report():
+	m = Metric("count")
+	m.fields["value"] = state.get("count", 0)
+	return [m]
+
+register_periodic("10ms", report)
+`,
+		MutableState: true,
+		Log:          testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0),
+	), &acc))
+
+	require.NoError(t, plugin.Start(&acc))
+	acc.Wait(1)
+	require.NoError(t, plugin.Stop())
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric("count",
+			map[string]string{},
+			map[string]interface{}{"value": 1},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, acc.GetTelegrafMetrics()[:1], testutil.IgnoreTime())
+}
+
+func TestRegisterPeriodicOnlyWhenLoaded(t *testing.T) {
+	plugin := &Starlark{
+		Source: `
+def apply(metric):
+	register_periodic("1s", lambda: None)
+	return metric
+`,
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, plugin.Start(&acc))
+	err := plugin.Add(testutil.MustMetric("cpu",
+		map[string]string{},
+		map[string]interface{}{"value": 1},
+		time.Unix(0, 0),
+	), &acc)
+	require.Error(t, err)
+	require.NoError(t, plugin.Stop())
+}
+
 var parser, _ = parsers.NewInfluxParser() // literally never returns errors.
 
 // parses metric lines out of line protocol following a header, with a trailing blank line
@@ -0,0 +1,256 @@
+package starlark
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/metric"
+	common "github.com/influxdata/telegraf/plugins/common/starlark"
+	"go.starlark.net/starlark"
+)
+
+// metricKey is the key of the JSON object a metric is saved as.
+const metricKey = "$metric"
+
+type savedMetric struct {
+	Name   string                 `json:"name"`
+	Tags   map[string]string      `json:"tags"`
+	Fields map[string]interface{} `json:"fields"`
+	Time   int64                  `json:"time"`
+}
+
+// loadState reads the state saved to path by saveState.  A missing file is
+// an empty state.
+func loadState(path string) (*starlark.Dict, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return starlark.NewDict(0), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	var saved map[string]interface{}
+	if err := decoder.Decode(&saved); err != nil {
+		return nil, fmt.Errorf("reading state from %s failed: %v", path, err)
+	}
+
+	v, err := fromJSON(saved)
+	if err != nil {
+		return nil, fmt.Errorf("reading state from %s failed: %v", path, err)
+	}
+	return v.(*starlark.Dict), nil
+}
+
+// saveState writes the state to path as JSON, replacing the file only once
+// it has been written completely.
+func saveState(path string, state *starlark.Dict) error {
+	v, err := toJSON(state)
+	if err != nil {
+		return fmt.Errorf("saving state failed: %v", err)
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("saving state failed: %v", err)
+	}
+
+	return internal.WriteFileAtomic(path, buf)
+}
+
+// toJSON converts a value of the state to a value encoding/json can marshal.
+// Numbers are converted to json.Number, with floats always containing a
+// decimal point or exponent, so that their type is kept when loading them.
+func toJSON(v starlark.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		return json.Number(v.String()), nil
+	case starlark.Float:
+		return formatFloat(float64(v))
+	case starlark.String:
+		return string(v), nil
+	case *starlark.List, starlark.Tuple:
+		iterable := v.(starlark.Iterable)
+		iter := iterable.Iterate()
+		defer iter.Done()
+		values := []interface{}{}
+		var item starlark.Value
+		for iter.Next(&item) {
+			value, err := toJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case *starlark.Dict:
+		values := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict key of type %s is not supported", item[0].Type())
+			}
+			value, err := toJSON(item[1])
+			if err != nil {
+				return nil, err
+			}
+			values[string(key)] = value
+		}
+		return values, nil
+	case *common.Metric:
+		m := v.Unwrap()
+		saved := savedMetric{
+			Name:   m.Name(),
+			Tags:   m.Tags(),
+			Fields: make(map[string]interface{}, len(m.FieldList())),
+			Time:   m.Time().UnixNano(),
+		}
+		for _, field := range m.FieldList() {
+			switch value := field.Value.(type) {
+			case float64:
+				n, err := formatFloat(value)
+				if err != nil {
+					return nil, err
+				}
+				saved.Fields[field.Key] = n
+			default:
+				saved.Fields[field.Key] = value
+			}
+		}
+		return map[string]interface{}{metricKey: saved}, nil
+	default:
+		return nil, fmt.Errorf("value of type %s is not supported", v.Type())
+	}
+}
+
+func formatFloat(f float64) (json.Number, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("float value %v is not supported", f)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return json.Number(s), nil
+}
+
+// fromJSON converts a value decoded by encoding/json, with numbers as
+// json.Number, to a value of the state.
+func fromJSON(v interface{}) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case json.Number:
+		if isFloat(v) {
+			f, err := v.Float64()
+			return starlark.Float(f), err
+		}
+		if i, err := v.Int64(); err == nil {
+			return starlark.MakeInt64(i), nil
+		}
+		i, ok := new(big.Int).SetString(string(v), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %s", v)
+		}
+		return starlark.MakeBigInt(i), nil
+	case string:
+		return starlark.String(v), nil
+	case []interface{}:
+		values := make([]starlark.Value, 0, len(v))
+		for _, item := range v {
+			value, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return starlark.NewList(values), nil
+	case map[string]interface{}:
+		if saved, ok := v[metricKey]; ok && len(v) == 1 {
+			return metricFromJSON(saved)
+		}
+		dict := starlark.NewDict(len(v))
+		for key, item := range v {
+			value, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(key), value); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("value of type %T is not supported", v)
+	}
+}
+
+func metricFromJSON(v interface{}) (starlark.Value, error) {
+	saved, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid metric")
+	}
+
+	name, _ := saved["name"].(string)
+	tags := make(map[string]string)
+	if t, ok := saved["tags"].(map[string]interface{}); ok {
+		for key, value := range t {
+			if s, ok := value.(string); ok {
+				tags[key] = s
+			}
+		}
+	}
+
+	fields := make(map[string]interface{})
+	if f, ok := saved["fields"].(map[string]interface{}); ok {
+		for key, value := range f {
+			n, ok := value.(json.Number)
+			if !ok {
+				fields[key] = value
+				continue
+			}
+			if isFloat(n) {
+				fields[key], _ = n.Float64()
+			} else if i, err := n.Int64(); err == nil {
+				fields[key] = i
+			} else if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+				fields[key] = u
+			} else {
+				return nil, fmt.Errorf("invalid value %s of field %q", n, key)
+			}
+		}
+	}
+
+	var ns int64
+	if n, ok := saved["time"].(json.Number); ok {
+		ns, _ = n.Int64()
+	}
+
+	m, err := metric.New(name, tags, fields, time.Unix(0, ns))
+	if err != nil {
+		return nil, err
+	}
+	sm := &common.Metric{}
+	sm.Wrap(m)
+	return sm, nil
+}
+
+func isFloat(n json.Number) bool {
+	return strings.ContainsAny(string(n), ".eE")
+}
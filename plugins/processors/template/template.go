@@ -1,18 +1,36 @@
 package template
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/Masterminds/sprig/v3"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/processors"
 )
 
 type TemplateProcessor struct {
-	Tag      string          `toml:"tag"`
-	Template string          `toml:"template"`
-	Log      telegraf.Logger `toml:"-"`
-	tmpl     *template.Template
+	Tag         string            `toml:"tag"`
+	Template    string            `toml:"template"`
+	Measurement string            `toml:"measurement"`
+	Tags        map[string]string `toml:"tags"`
+	Fields      map[string]string `toml:"fields"`
+	FieldTypes  map[string]string `toml:"field_types"`
+	Log         telegraf.Logger   `toml:"-"`
+
+	tmpl        *template.Template
+	measurement *template.Template
+	tags        []keyTemplate
+	fields      []keyTemplate
+}
+
+// keyTemplate is the template of the value of a tag or field.
+type keyTemplate struct {
+	key  string
+	tmpl *template.Template
 }
 
 const sampleConfig = `
@@ -23,6 +41,22 @@ const sampleConfig = `
   ## escaping requirements, you may wish to use single quotes around the
   ## template string.
   template = '{{ .Tag "hostname" }}.{{ .Tag "level" }}'
+
+  ## Go template used to create the measurement name.
+  # measurement = '{{ .Name | lower }}'
+
+  ## Tags to set with the output of their template.
+  # [processors.template.tags]
+  #   region = '{{ .Tag "host" | regexFind "^[a-z]+" | default "unknown" }}'
+
+  ## Fields to set with the output of their template.
+  # [processors.template.fields]
+  #   temp_f = '{{ .Field "temp_c" | mulf 1.8 | addf 32 }}'
+
+  ## Types of the fields set from a template, one of "string", "integer",
+  ## "unsigned", "float" or "boolean".  Fields are strings by default.
+  # [processors.template.field_types]
+  #   temp_f = "float"
 `
 
 func (r *TemplateProcessor) SampleConfig() string {
@@ -30,33 +64,193 @@ func (r *TemplateProcessor) SampleConfig() string {
 }
 
 func (r *TemplateProcessor) Description() string {
-	return "Uses a Go template to create a new tag"
+	return "Uses Go templates to create new tags and fields, or rename the measurement"
 }
 
 func (r *TemplateProcessor) Apply(in ...telegraf.Metric) []telegraf.Metric {
 	// for each metric in "in" array
 	for _, metric := range in {
-		var b strings.Builder
 		newM := TemplateMetric{metric}
 
-		// supply TemplateMetric and Template from configuration to Template.Execute
-		err := r.tmpl.Execute(&b, &newM)
-		if err != nil {
-			r.Log.Errorf("failed to execute template: %v", err)
-			continue
+		if r.tmpl != nil {
+			// supply TemplateMetric and Template from configuration to Template.Execute
+			value, err := execute(r.tmpl, &newM)
+			if err != nil {
+				r.Log.Errorf("failed to execute template: %v", err)
+			} else {
+				metric.AddTag(r.Tag, value)
+			}
+		}
+
+		// All templates are executed against the metric as it was received,
+		// so that they don't depend on the order they are applied in.
+		var name string
+		if r.measurement != nil {
+			value, err := execute(r.measurement, &newM)
+			if err != nil {
+				r.Log.Errorf("failed to execute measurement template: %v", err)
+			}
+			name = value
+		}
+
+		tags := make(map[string]string, len(r.tags))
+		for _, t := range r.tags {
+			value, err := execute(t.tmpl, &newM)
+			if err != nil {
+				r.Log.Errorf("failed to execute template of tag %q: %v", t.key, err)
+				continue
+			}
+			tags[t.key] = value
+		}
+
+		fields := make(map[string]interface{}, len(r.fields))
+		for _, f := range r.fields {
+			value, err := execute(f.tmpl, &newM)
+			if err != nil {
+				r.Log.Errorf("failed to execute template of field %q: %v", f.key, err)
+				continue
+			}
+			if value == "" {
+				continue
+			}
+			v, err := convertField(value, r.FieldTypes[f.key])
+			if err != nil {
+				r.Log.Errorf("failed to convert field %q: %v", f.key, err)
+				continue
+			}
+			fields[f.key] = v
 		}
 
-		metric.AddTag(r.Tag, b.String())
+		if name != "" {
+			metric.SetName(name)
+		}
+		for key, value := range tags {
+			if value == "" {
+				metric.RemoveTag(key)
+				continue
+			}
+			metric.AddTag(key, value)
+		}
+		for key, value := range fields {
+			metric.AddField(key, value)
+		}
 	}
 	return in
 }
 
 func (r *TemplateProcessor) Init() error {
-	// create template
-	t, err := template.New("configured_template").Parse(r.Template)
+	if r.Template != "" {
+		if r.Tag == "" {
+			return fmt.Errorf("tag must be set with template")
+		}
+		// create template
+		t, err := newTemplate("configured_template", r.Template)
+		if err != nil {
+			return err
+		}
+		r.tmpl = t
+	}
+
+	if r.Measurement != "" {
+		t, err := newTemplate("measurement", r.Measurement)
+		if err != nil {
+			return fmt.Errorf("invalid measurement template: %v", err)
+		}
+		r.measurement = t
+	}
+
+	var err error
+	r.tags, err = newKeyTemplates("tag", r.Tags)
+	if err != nil {
+		return err
+	}
+	r.fields, err = newKeyTemplates("field", r.Fields)
+	if err != nil {
+		return err
+	}
 
-	r.tmpl = t
-	return err
+	for key, typ := range r.FieldTypes {
+		if _, ok := r.Fields[key]; !ok {
+			return fmt.Errorf("type of field %q without template", key)
+		}
+		switch typ {
+		case "string", "integer", "unsigned", "float", "boolean":
+		default:
+			return fmt.Errorf("invalid type %q of field %q", typ, key)
+		}
+	}
+
+	return nil
+}
+
+// newTemplate parses a template with the Sprig functions available.
+func newTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(sprig.TxtFuncMap()).Parse(text)
+}
+
+// newKeyTemplates parses the templates of tags or fields, sorted by key.
+func newKeyTemplates(kind string, templates map[string]string) ([]keyTemplate, error) {
+	keys := make([]string, 0, len(templates))
+	for key := range templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]keyTemplate, 0, len(keys))
+	for _, key := range keys {
+		t, err := newTemplate(kind+"_"+key, templates[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid template of %s %q: %v", kind, key, err)
+		}
+		result = append(result, keyTemplate{key: key, tmpl: t})
+	}
+	return result, nil
+}
+
+func execute(t *template.Template, m *TemplateMetric) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, m); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// convertField converts the output of a template to the type of the field.
+func convertField(value string, typ string) (interface{}, error) {
+	if typ == "" || typ == "string" {
+		return value, nil
+	}
+
+	value = strings.TrimSpace(value)
+	switch typ {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v, nil
+		}
+		// Accept integral values in float notation, such as the output
+		// of the float functions.
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return int64(v), nil
+	case "unsigned":
+		if v, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return v, nil
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		if v < 0 {
+			return nil, fmt.Errorf("negative value %q", value)
+		}
+		return uint64(v), nil
+	case "float":
+		return strconv.ParseFloat(value, 64)
+	default:
+		return strconv.ParseBool(value)
+	}
 }
 
 func init() {
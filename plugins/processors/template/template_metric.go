@@ -27,3 +27,11 @@ func (m *TemplateMetric) Field(key string) interface{} {
 func (m *TemplateMetric) Time() time.Time {
 	return m.metric.Time()
 }
+
+func (m *TemplateMetric) Tags() map[string]string {
+	return m.metric.Tags()
+}
+
+func (m *TemplateMetric) Fields() map[string]interface{} {
+	return m.metric.Fields()
+}
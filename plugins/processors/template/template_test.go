@@ -115,3 +115,104 @@ func TestTagAndFieldConcatenate(t *testing.T) {
 	expected := []telegraf.Metric{testutil.MustMetric("weather", map[string]string{"location": "us-midwest", "LocalTemp": "us-midwest is too warm"}, map[string]interface{}{"temperature": "too warm"}, now)}
 	testutil.RequireMetricsEqual(t, expected, actual)
 }
+
+func TestMeasurementTagsAndFields(t *testing.T) {
+	plugin := TemplateProcessor{
+		Measurement: `{{ .Name | lower | replace "-" "_" }}`,
+		Tags: map[string]string{
+			"region":  `{{ .Tag "host" | regexFind "^[a-z]+" | default "unknown" }}`,
+			"host_id": `{{ .Tag "host" | sha256sum | trunc 8 }}`,
+			"level":   `{{ .Tag "missing" }}`,
+		},
+		Fields: map[string]string{
+			"temp_f":  `{{ .Field "temp_c" | mulf 1.8 | addf 32 }}`,
+			"samples": `{{ add (.Field "count") 1 }}`,
+			"hot":     `{{ gt (.Field "temp_c") 30.0 }}`,
+			"day":     `{{ dateInZone "2006-01-02" .Time "UTC" }}`,
+		},
+		FieldTypes: map[string]string{
+			"temp_f":  "float",
+			"samples": "integer",
+			"hot":     "boolean",
+		},
+		Log: testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := testutil.MustMetric(
+		"Room-Temp",
+		map[string]string{"host": "east42", "level": "debug"},
+		map[string]interface{}{"temp_c": 25.0, "count": int64(4)},
+		time.Unix(0, 0),
+	)
+
+	expected := []telegraf.Metric{
+		testutil.MustMetric(
+			"room_temp",
+			map[string]string{"host": "east42", "region": "east", "host_id": "e03ff39a"},
+			map[string]interface{}{
+				"temp_c":  25.0,
+				"count":   int64(4),
+				"temp_f":  77.0,
+				"samples": int64(5),
+				"hot":     false,
+				"day":     "1970-01-01",
+			},
+			time.Unix(0, 0),
+		),
+	}
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(input))
+}
+
+func TestFieldConversionError(t *testing.T) {
+	plugin := TemplateProcessor{
+		Fields:     map[string]string{"value": `{{ .Tag "host" }}`},
+		FieldTypes: map[string]string{"value": "integer"},
+		Log:        testutil.Logger{},
+	}
+	require.NoError(t, plugin.Init())
+
+	input := testutil.MustMetric("cpu",
+		map[string]string{"host": "localhost"},
+		map[string]interface{}{"time_idle": 42},
+		time.Unix(0, 0),
+	)
+	expected := []telegraf.Metric{input.Copy()}
+	testutil.RequireMetricsEqual(t, expected, plugin.Apply(input))
+}
+
+func TestInitErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		plugin TemplateProcessor
+	}{
+		{
+			name:   "template without tag",
+			plugin: TemplateProcessor{Template: "{{ .Name }}"},
+		},
+		{
+			name:   "invalid template",
+			plugin: TemplateProcessor{Tags: map[string]string{"name": "{{ .Name "}},
+		},
+		{
+			name:   "unknown function",
+			plugin: TemplateProcessor{Measurement: "{{ .Name | nosuchfunc }}"},
+		},
+		{
+			name: "invalid type",
+			plugin: TemplateProcessor{
+				Fields:     map[string]string{"name": "{{ .Name }}"},
+				FieldTypes: map[string]string{"name": "duration"},
+			},
+		},
+		{
+			name:   "type without template",
+			plugin: TemplateProcessor{FieldTypes: map[string]string{"name": "string"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.plugin.Init())
+		})
+	}
+}
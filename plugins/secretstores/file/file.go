@@ -0,0 +1,68 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+)
+
+const sampleConfig = `
+  ## Identifier used to refer to the store, as in @{file:/run/secrets/token}.
+  # id = "file"
+
+  ## Directory relative paths are resolved against.
+  # directory = "/run/secrets"
+`
+
+// File reads secrets from files, such as those mounted by container
+// orchestrators.  The secret is the content of the file, or the value of a
+// key if the file is a JSON object.
+type File struct {
+	Directory string `toml:"directory"`
+}
+
+func (f *File) SampleConfig() string {
+	return sampleConfig
+}
+
+func (f *File) Description() string {
+	return "Read secrets from files"
+}
+
+func (f *File) Get(path, key string) (string, error) {
+	if f.Directory != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(f.Directory, path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("parsing %s: %v", path, err)
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("no key %q in %s", key, path)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+func init() {
+	secretstores.Add("file", func() telegraf.SecretStore {
+		return &File{}
+	})
+}
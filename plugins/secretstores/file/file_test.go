@@ -0,0 +1,46 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "token"), []byte("abc\r\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "creds.json"), []byte(`{"user": "telegraf", "port": 8086}`), 0600))
+
+	f := &File{Directory: dir}
+
+	value, err := f.Get("token", "")
+	require.NoError(t, err)
+	require.Equal(t, "abc", value)
+
+	value, err = f.Get(filepath.Join(dir, "token"), "")
+	require.NoError(t, err)
+	require.Equal(t, "abc", value)
+
+	value, err = f.Get("creds.json", "user")
+	require.NoError(t, err)
+	require.Equal(t, "telegraf", value)
+
+	value, err = f.Get("creds.json", "port")
+	require.NoError(t, err)
+	require.Equal(t, "8086", value)
+
+	_, err = f.Get("creds.json", "password")
+	require.Error(t, err)
+
+	_, err = f.Get("token", "user")
+	require.Error(t, err)
+
+	_, err = f.Get("missing", "")
+	require.Error(t, err)
+}
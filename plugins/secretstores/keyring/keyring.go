@@ -0,0 +1,234 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+	"golang.org/x/crypto/scrypt"
+)
+
+const sampleConfig = `
+  ## Identifier used to refer to the store, as in @{keyring:influxdb_token}.
+  # id = "keyring"
+
+  ## Path of the encrypted keyring file.  Secrets are added with
+  ##   telegraf --config telegraf.conf secrets set <id> <name>
+  ## which creates the file if it does not exist.
+  path = "/etc/telegraf/secrets.keyring"
+
+  ## File containing the password the keyring is encrypted with.
+  password_file = "/etc/telegraf/keyring.password"
+`
+
+// The key is derived from the password with scrypt, using the recommended
+// parameters for interactive logins.
+const (
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+	keySize = 32
+	version = 1
+)
+
+// file is the format of the keyring file.  The data is the JSON encoding of
+// the secrets, encrypted with AES-256-GCM.
+type file struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// Keyring is a local file of secrets encrypted with a password.
+type Keyring struct {
+	Path         string `toml:"path"`
+	PasswordFile string `toml:"password_file"`
+
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func (k *Keyring) SampleConfig() string {
+	return sampleConfig
+}
+
+func (k *Keyring) Description() string {
+	return "Read secrets from an encrypted local keyring file"
+}
+
+func (k *Keyring) Init() error {
+	if k.Path == "" {
+		return errors.New("path is required")
+	}
+	if k.PasswordFile == "" {
+		return errors.New("password_file is required")
+	}
+	return nil
+}
+
+func (k *Keyring) Get(path, key string) (string, error) {
+	if key != "" {
+		return "", errors.New("keyring secrets have no keys")
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.secrets == nil {
+		secrets, err := k.read()
+		if err != nil {
+			return "", err
+		}
+		k.secrets = secrets
+	}
+
+	value, ok := k.secrets[path]
+	if !ok {
+		return "", fmt.Errorf("no secret %q in %s", path, k.Path)
+	}
+	return value, nil
+}
+
+// Set stores the secret in the keyring file, creating the file if it does
+// not exist.
+func (k *Keyring) Set(name, value string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secrets, err := k.read()
+	if os.IsNotExist(err) {
+		secrets = make(map[string]string)
+	} else if err != nil {
+		return err
+	}
+
+	secrets[name] = value
+	if err := k.write(secrets); err != nil {
+		return err
+	}
+	k.secrets = secrets
+	return nil
+}
+
+// List returns the names of the secrets in the keyring.
+func (k *Keyring) List() ([]string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secrets, err := k.read()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (k *Keyring) password() ([]byte, error) {
+	password, err := ioutil.ReadFile(k.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+	password = []byte(strings.TrimRight(string(password), "\r\n"))
+	if len(password) == 0 {
+		return nil, fmt.Errorf("password file %s is empty", k.PasswordFile)
+	}
+	return password, nil
+}
+
+func (k *Keyring) aead(salt []byte) (cipher.AEAD, error) {
+	password, err := k.password()
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(password, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (k *Keyring) read() (map[string]string, error) {
+	data, err := ioutil.ReadFile(k.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", k.Path, err)
+	}
+	if f.Version != version {
+		return nil, fmt.Errorf("unsupported keyring version %d in %s", f.Version, k.Path)
+	}
+
+	aead, err := k.aead(f.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting %s: wrong password or corrupt file", k.Path)
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", k.Path, err)
+	}
+	return secrets, nil
+}
+
+func (k *Keyring) write(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	f := file{Version: version, Salt: make([]byte, 16)}
+	if _, err := rand.Read(f.Salt); err != nil {
+		return err
+	}
+	aead, err := k.aead(f.Salt)
+	if err != nil {
+		return err
+	}
+	f.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(f.Nonce); err != nil {
+		return err
+	}
+	f.Data = aead.Seal(nil, f.Nonce, plaintext, nil)
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	// Replace the file atomically so a failed write does not lose the
+	// existing secrets.
+	return internal.WriteFileAtomic(k.Path, data)
+}
+
+func init() {
+	secretstores.Add("keyring", func() telegraf.SecretStore {
+		return &Keyring{}
+	})
+}
@@ -0,0 +1,69 @@
+package keyring
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("hunter2\n"), 0600))
+
+	k := &Keyring{Path: filepath.Join(dir, "secrets.keyring"), PasswordFile: passwordFile}
+	require.NoError(t, k.Init())
+
+	_, err = k.Get("token", "")
+	require.True(t, os.IsNotExist(err))
+
+	require.NoError(t, k.Set("token", "abc"))
+	require.NoError(t, k.Set("password", "def"))
+
+	info, err := os.Stat(k.Path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := ioutil.ReadFile(k.Path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "abc")
+
+	// A new keyring reads the secrets from the file.
+	k = &Keyring{Path: k.Path, PasswordFile: passwordFile}
+	value, err := k.Get("token", "")
+	require.NoError(t, err)
+	require.Equal(t, "abc", value)
+
+	names, err := k.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"password", "token"}, names)
+
+	_, err = k.Get("missing", "")
+	require.Error(t, err)
+
+	_, err = k.Get("token", "key")
+	require.Error(t, err)
+}
+
+func TestWrongPassword(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keyring")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("hunter2"), 0600))
+
+	k := &Keyring{Path: filepath.Join(dir, "secrets.keyring"), PasswordFile: passwordFile}
+	require.NoError(t, k.Set("token", "abc"))
+
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("hunter3"), 0600))
+	k = &Keyring{Path: k.Path, PasswordFile: passwordFile}
+	_, err = k.Get("token", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,188 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/internal"
+	"github.com/influxdata/telegraf/plugins/common/tls"
+	"github.com/influxdata/telegraf/plugins/secretstores"
+)
+
+const sampleConfig = `
+  ## Identifier used to refer to the store, as in
+  ## @{vault:secret/data/influxdb#token}.
+  # id = "vault"
+
+  ## Address of the Vault server.
+  address = "https://vault.example.com:8200"
+
+  ## File containing the token to authenticate with.
+  token_file = "/etc/telegraf/vault.token"
+
+  ## Vault Enterprise namespace.
+  # namespace = ""
+
+  ## Timeout for reading a secret.
+  # timeout = "5s"
+
+  ## Optional TLS Config
+  # tls_ca = "/etc/telegraf/ca.pem"
+  # tls_cert = "/etc/telegraf/cert.pem"
+  # tls_key = "/etc/telegraf/key.pem"
+  ## Use TLS but skip chain & host verification
+  # insecure_skip_verify = false
+`
+
+const defaultTimeout = 5 * time.Second
+
+// Vault reads secrets from the key/value secrets engine of a HashiCorp Vault
+// compatible HTTP API.  Both versions of the engine are supported; the path
+// of a version 2 secret includes "data/", as in the API.
+type Vault struct {
+	Address   string            `toml:"address"`
+	TokenFile string            `toml:"token_file"`
+	Namespace string            `toml:"namespace"`
+	Timeout   internal.Duration `toml:"timeout"`
+	tls.ClientConfig
+
+	client *http.Client
+
+	mu      sync.Mutex
+	secrets map[string]map[string]interface{}
+}
+
+// response is the part of a read secret response used.  A version 2 secret
+// has its values in data.data, alongside data.metadata.
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []string        `json:"errors"`
+}
+
+type responseV2 struct {
+	Data     map[string]interface{} `json:"data"`
+	Metadata json.RawMessage        `json:"metadata"`
+}
+
+func (v *Vault) SampleConfig() string {
+	return sampleConfig
+}
+
+func (v *Vault) Description() string {
+	return "Read secrets from a HashiCorp Vault compatible server"
+}
+
+func (v *Vault) Init() error {
+	if v.Address == "" {
+		return errors.New("address is required")
+	}
+	if v.TokenFile == "" {
+		return errors.New("token_file is required")
+	}
+	if v.Timeout.Duration == 0 {
+		v.Timeout.Duration = defaultTimeout
+	}
+
+	tlsCfg, err := v.ClientConfig.TLSConfig()
+	if err != nil {
+		return err
+	}
+	v.client = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsCfg,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+		Timeout: v.Timeout.Duration,
+	}
+	v.secrets = make(map[string]map[string]interface{})
+	return nil
+}
+
+func (v *Vault) Get(path, key string) (string, error) {
+	if key == "" {
+		return "", errors.New("a key is required, as in @{id:path#key}")
+	}
+
+	values, err := v.read(path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("no key %q in secret %q", key, path)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// read returns the values of the secret, reading each secret once.  The
+// store is created again on each configuration load, so the secrets are
+// cached until Telegraf reloads.
+func (v *Vault) read(path string) (map[string]interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if values, ok := v.secrets[path]; ok {
+		return values, nil
+	}
+
+	token, err := ioutil.ReadFile(v.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	u := strings.TrimRight(v.Address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", strings.TrimSpace(string(token)))
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("reading secret %q: %v", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(r.Errors) > 0 {
+			return nil, fmt.Errorf("reading secret %q: %s: %s", path, resp.Status, strings.Join(r.Errors, "; "))
+		}
+		return nil, fmt.Errorf("reading secret %q: %s", path, resp.Status)
+	}
+
+	var v2 responseV2
+	if err := json.Unmarshal(r.Data, &v2); err == nil && v2.Data != nil && v2.Metadata != nil {
+		v.secrets[path] = v2.Data
+		return v2.Data, nil
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(r.Data, &values); err != nil {
+		return nil, fmt.Errorf("reading secret %q: %v", path, err)
+	}
+	v.secrets[path] = values
+	return values, nil
+}
+
+func init() {
+	secretstores.Add("vault", func() telegraf.SecretStore {
+		return &Vault{}
+	})
+}
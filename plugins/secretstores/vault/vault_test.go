@@ -0,0 +1,70 @@
+package vault
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "ns" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/influxdb":
+			w.Write([]byte(`{"data": {"token": "abc", "port": 8086}}`))
+		case "/v1/kv/data/influxdb":
+			w.Write([]byte(`{"data": {"data": {"token": "def"}, "metadata": {"version": 2}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": []}`))
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "vault")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte("s.token\n"), 0600))
+
+	v := &Vault{Address: ts.URL, TokenFile: tokenFile, Namespace: "ns"}
+	require.NoError(t, v.Init())
+
+	value, err := v.Get("secret/influxdb", "token")
+	require.NoError(t, err)
+	require.Equal(t, "abc", value)
+
+	value, err = v.Get("secret/influxdb", "port")
+	require.NoError(t, err)
+	require.Equal(t, "8086", value)
+	require.Equal(t, 1, requests)
+
+	value, err = v.Get("kv/data/influxdb", "token")
+	require.NoError(t, err)
+	require.Equal(t, "def", value)
+
+	_, err = v.Get("kv/data/influxdb", "password")
+	require.Error(t, err)
+
+	_, err = v.Get("secret/missing", "token")
+	require.Error(t, err)
+
+	_, err = v.Get("secret/influxdb", "")
+	require.Error(t, err)
+
+	v = &Vault{Address: ts.URL, TokenFile: tokenFile}
+	require.NoError(t, v.Init())
+	_, err = v.Get("secret/influxdb", "token")
+	require.EqualError(t, err, `reading secret "secret/influxdb": 403 Forbidden: permission denied`)
+}
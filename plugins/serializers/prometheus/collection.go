@@ -182,6 +182,17 @@ func (c *Collection) createLabels(metric telegraf.Metric) []LabelPair {
 
 func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 	labels := c.createLabels(metric)
+
+	// Histograms and summaries carried by the metric are complete, so the
+	// fields they were flattened into are not needed.
+	switch metric.Type() {
+	case telegraf.Histogram, telegraf.Summary:
+		if len(metric.DistributionList()) > 0 {
+			c.addDistributions(metric, labels, now)
+			return
+		}
+	}
+
 	for _, field := range metric.FieldList() {
 		metricName := MetricName(metric.Name(), field.Key, metric.Type())
 		metricName, ok := SanitizeMetricName(metricName)
@@ -333,6 +344,52 @@ func (c *Collection) Add(metric telegraf.Metric, now time.Time) {
 	}
 }
 
+func (c *Collection) addDistributions(metric telegraf.Metric, labels []LabelPair, now time.Time) {
+	for _, d := range metric.DistributionList() {
+		metricName, ok := SanitizeMetricName(DistributionName(metric.Name(), d.Key))
+		if !ok {
+			continue
+		}
+
+		family := MetricFamily{
+			Name: metricName,
+			Type: metric.Type(),
+		}
+
+		entry, ok := c.Entries[family]
+		if !ok {
+			entry = Entry{
+				Family:  family,
+				Metrics: make(map[MetricKey]*Metric),
+			}
+			c.Entries[family] = entry
+		}
+
+		metricKey := MakeMetricKey(labels)
+		if m, ok := entry.Metrics[metricKey]; ok && metric.Time().Before(m.Time) {
+			continue
+		}
+
+		m := &Metric{
+			Labels:  labels,
+			Time:    metric.Time(),
+			AddTime: now,
+		}
+		if metric.Type() == telegraf.Histogram {
+			m.Histogram = &Histogram{Count: d.Count, Sum: d.Sum}
+			for _, b := range d.Buckets {
+				m.Histogram.Buckets = append(m.Histogram.Buckets, Bucket{Bound: b.UpperBound, Count: b.Count})
+			}
+		} else {
+			m.Summary = &Summary{Count: d.Count, Sum: d.Sum}
+			for _, q := range d.Quantiles {
+				m.Summary.Quantiles = append(m.Summary.Quantiles, Quantile{Quantile: q.Quantile, Value: q.Value})
+			}
+		}
+		entry.Metrics[metricKey] = m
+	}
+}
+
 func (c *Collection) Expire(now time.Time, age time.Duration) {
 	expireTime := now.Add(-age)
 	for _, entry := range c.Entries {
@@ -425,3 +425,171 @@ func TestCollectionExpire(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectionDistributions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []telegraf.Metric
+		expected []*dto.MetricFamily
+	}{
+		{
+			name: "histogram",
+			input: []telegraf.Metric{
+				testutil.WithDistributions(
+					testutil.MustMetric(
+						"http_request_duration_seconds",
+						map[string]string{"code": "200"},
+						map[string]interface{}{
+							"count": 3.0,
+							"sum":   1.5,
+							"0.5":   2.0,
+							"+Inf":  3.0,
+						},
+						time.Unix(0, 0),
+						telegraf.Histogram,
+					),
+					&telegraf.Distribution{
+						Count: 3,
+						Sum:   1.5,
+						Buckets: []telegraf.Bucket{
+							{UpperBound: 0.5, Count: 2},
+							{UpperBound: math.Inf(1), Count: 3},
+						},
+					},
+				),
+			},
+			expected: []*dto.MetricFamily{
+				{
+					Name: proto.String("http_request_duration_seconds"),
+					Help: proto.String(helpString),
+					Type: dto.MetricType_HISTOGRAM.Enum(),
+					Metric: []*dto.Metric{
+						{
+							Label: []*dto.LabelPair{
+								{Name: proto.String("code"), Value: proto.String("200")},
+							},
+							Histogram: &dto.Histogram{
+								SampleCount: proto.Uint64(3),
+								SampleSum:   proto.Float64(1.5),
+								Bucket: []*dto.Bucket{
+									{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(2)},
+									{UpperBound: proto.Float64(math.Inf(1)), CumulativeCount: proto.Uint64(3)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "histogram with bucket metrics",
+			input: []telegraf.Metric{
+				testutil.WithDistributions(
+					testutil.MustMetric(
+						"prometheus",
+						map[string]string{},
+						map[string]interface{}{
+							"latency_count": 3.0,
+							"latency_sum":   1.5,
+						},
+						time.Unix(0, 0),
+						telegraf.Histogram,
+					),
+					&telegraf.Distribution{
+						Key:   "latency",
+						Count: 3,
+						Sum:   1.5,
+						Buckets: []telegraf.Bucket{
+							{UpperBound: 0.5, Count: 2},
+							{UpperBound: math.Inf(1), Count: 3},
+						},
+					},
+				),
+				testutil.MustMetric(
+					"prometheus",
+					map[string]string{"le": "0.5"},
+					map[string]interface{}{
+						"latency_bucket": 2.0,
+					},
+					time.Unix(0, 0),
+					telegraf.Histogram,
+				),
+			},
+			expected: []*dto.MetricFamily{
+				{
+					Name: proto.String("latency"),
+					Help: proto.String(helpString),
+					Type: dto.MetricType_HISTOGRAM.Enum(),
+					Metric: []*dto.Metric{
+						{
+							Label: []*dto.LabelPair{},
+							Histogram: &dto.Histogram{
+								SampleCount: proto.Uint64(3),
+								SampleSum:   proto.Float64(1.5),
+								Bucket: []*dto.Bucket{
+									{UpperBound: proto.Float64(0.5), CumulativeCount: proto.Uint64(2)},
+									{UpperBound: proto.Float64(math.Inf(1)), CumulativeCount: proto.Uint64(3)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "summary",
+			input: []telegraf.Metric{
+				testutil.WithDistributions(
+					testutil.MustMetric(
+						"prometheus",
+						map[string]string{},
+						map[string]interface{}{
+							"rpc_duration_seconds_count": 3.0,
+							"rpc_duration_seconds_sum":   6.0,
+						},
+						time.Unix(0, 0),
+						telegraf.Summary,
+					),
+					&telegraf.Distribution{
+						Key:   "rpc_duration_seconds",
+						Count: 3,
+						Sum:   6,
+						Quantiles: []telegraf.Quantile{
+							{Quantile: 0.5, Value: 2},
+							{Quantile: 0.99, Value: 3},
+						},
+					},
+				),
+			},
+			expected: []*dto.MetricFamily{
+				{
+					Name: proto.String("rpc_duration_seconds"),
+					Help: proto.String(helpString),
+					Type: dto.MetricType_SUMMARY.Enum(),
+					Metric: []*dto.Metric{
+						{
+							Label: []*dto.LabelPair{},
+							Summary: &dto.Summary{
+								SampleCount: proto.Uint64(3),
+								SampleSum:   proto.Float64(6),
+								Quantile: []*dto.Quantile{
+									{Quantile: proto.Float64(0.5), Value: proto.Float64(2)},
+									{Quantile: proto.Float64(0.99), Value: proto.Float64(3)},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollection(FormatConfig{})
+			for _, m := range tt.input {
+				c.Add(m, time.Unix(0, 0))
+			}
+			require.Equal(t, tt.expected, c.GetProto())
+		})
+	}
+}
@@ -142,6 +142,19 @@ func MetricName(measurement, fieldKey string, valueType telegraf.ValueType) stri
 	return measurement + "_" + fieldKey
 }
 
+// DistributionName returns the Prometheus metric name of a histogram or
+// summary carried by a metric.
+func DistributionName(measurement, key string) string {
+	switch {
+	case key == "":
+		return measurement
+	case measurement == "prometheus":
+		return key
+	default:
+		return measurement + "_" + key
+	}
+}
+
 func MetricType(valueType telegraf.ValueType) *dto.MetricType {
 	switch valueType {
 	case telegraf.Counter:
@@ -57,6 +57,19 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 	var entries = make(map[MetricKey]*prompb.TimeSeries)
 	for _, metric := range metrics {
 		commonLabels := s.createLabels(metric)
+
+		// Histograms and summaries carried by the metric are complete, so
+		// the fields they were flattened into are not needed.
+		switch metric.Type() {
+		case telegraf.Histogram, telegraf.Summary:
+			if len(metric.DistributionList()) > 0 {
+				for _, promts := range distributionTS(metric, commonLabels) {
+					addTS(entries, metric, promts)
+				}
+				continue
+			}
+		}
+
 		var metrickey MetricKey
 		var promts *prompb.TimeSeries
 		for _, field := range metric.FieldList() {
@@ -195,7 +208,7 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 			// sample then we can skip over it.
 			m, ok := entries[metrickey]
 			if ok {
-				if metric.Time().Before(time.Unix(m.Samples[0].Timestamp, 0)) {
+				if metric.Time().Before(sampleTime(m.Samples[0])) {
 					continue
 				}
 			}
@@ -246,6 +259,58 @@ func (s *Serializer) SerializeBatch(metrics []telegraf.Metric) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// distributionTS returns the series of the histograms or summaries carried
+// by a metric.
+func distributionTS(metric telegraf.Metric, commonLabels []*prompb.Label) []*prompb.TimeSeries {
+	var series []*prompb.TimeSeries
+	add := func(name string, labels []*prompb.Label, value float64) {
+		_, promts := getPromTS(name, labels, value, metric.Time())
+		series = append(series, promts)
+	}
+	withLabel := func(name, value string) []*prompb.Label {
+		labels := make([]*prompb.Label, len(commonLabels), len(commonLabels)+1)
+		copy(labels, commonLabels)
+		return append(labels, &prompb.Label{Name: name, Value: value})
+	}
+
+	for _, d := range metric.DistributionList() {
+		metricName, ok := prometheus.SanitizeMetricName(prometheus.DistributionName(metric.Name(), d.Key))
+		if !ok {
+			continue
+		}
+
+		add(metricName+"_sum", commonLabels, d.Sum)
+		add(metricName+"_count", commonLabels, float64(d.Count))
+		if metric.Type() == telegraf.Histogram {
+			for _, b := range d.Buckets {
+				add(metricName+"_bucket", withLabel("le", fmt.Sprint(b.UpperBound)), float64(b.Count))
+			}
+		} else {
+			for _, q := range d.Quantiles {
+				add(metricName, withLabel("quantile", fmt.Sprint(q.Quantile)), q.Value)
+			}
+		}
+	}
+	return series
+}
+
+// addTS adds a series to the entries unless a sample of the series for a
+// later time was already added.
+func addTS(entries map[MetricKey]*prompb.TimeSeries, metric telegraf.Metric, promts *prompb.TimeSeries) {
+	metrickey := MakeMetricKey(promts.Labels)
+	if m, ok := entries[metrickey]; ok {
+		if metric.Time().Before(sampleTime(m.Samples[0])) {
+			return
+		}
+	}
+	entries[metrickey] = promts
+}
+
+// sampleTime returns the time of a sample, whose timestamp is in milliseconds.
+func sampleTime(sample prompb.Sample) time.Time {
+	return time.Unix(0, sample.Timestamp*int64(time.Millisecond))
+}
+
 func hasLabel(name string, labels []*prompb.Label) bool {
 	for _, label := range labels {
 		if name == label.Name {
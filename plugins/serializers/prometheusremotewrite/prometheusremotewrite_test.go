@@ -7,6 +7,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/prompb"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -129,6 +130,67 @@ http_request_duration_seconds_count 0
 http_request_duration_seconds_sum 0
 http_request_duration_seconds_bucket{le="+Inf"} 0
 http_request_duration_seconds_bucket{le="0.5"} 129389
+`),
+		},
+		{
+			name: "histogram with distribution",
+			metric: testutil.WithDistributions(
+				testutil.MustMetric(
+					"http_request_duration_seconds",
+					map[string]string{"code": "200"},
+					map[string]interface{}{
+						"count": 144320.0,
+						"sum":   53423.0,
+						"0.5":   129389.0,
+						"+Inf":  144320.0,
+					},
+					time.Unix(0, 0),
+					telegraf.Histogram,
+				),
+				&telegraf.Distribution{
+					Count: 144320,
+					Sum:   53423,
+					Buckets: []telegraf.Bucket{
+						{UpperBound: 0.5, Count: 129389},
+						{UpperBound: math.Inf(1), Count: 144320},
+					},
+				},
+			),
+			expected: []byte(`
+http_request_duration_seconds_count{code="200"} 144320
+http_request_duration_seconds_sum{code="200"} 53423
+http_request_duration_seconds_bucket{code="200", le="+Inf"} 144320
+http_request_duration_seconds_bucket{code="200", le="0.5"} 129389
+`),
+		},
+		{
+			name: "summary with distribution",
+			metric: testutil.WithDistributions(
+				testutil.MustMetric(
+					"prometheus",
+					map[string]string{},
+					map[string]interface{}{
+						"rpc_duration_seconds_count": 3.0,
+						"rpc_duration_seconds_sum":   6.0,
+					},
+					time.Unix(0, 0),
+					telegraf.Summary,
+				),
+				&telegraf.Distribution{
+					Key:   "rpc_duration_seconds",
+					Count: 3,
+					Sum:   6,
+					Quantiles: []telegraf.Quantile{
+						{Quantile: 0.5, Value: 2},
+						{Quantile: 0.99, Value: 3},
+					},
+				},
+			),
+			expected: []byte(`
+rpc_duration_seconds_count 3
+rpc_duration_seconds_sum 6
+rpc_duration_seconds{quantile="0.5"} 2
+rpc_duration_seconds{quantile="0.99"} 3
 `),
 		},
 	}
@@ -592,6 +654,38 @@ cpu_time_user{cpu="cpu2"} 96034.08
 cpu_time_guest{cpu="cpu3"} 7517.95
 cpu_time_system{cpu="cpu3"} 24970.82
 cpu_time_user{cpu="cpu3"} 94148
+`),
+		},
+		{
+			name: "newest sample of a series",
+			metrics: []telegraf.Metric{
+				testutil.MustMetric(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"time_idle": 42.0,
+					},
+					time.Unix(1, 0),
+				),
+				testutil.MustMetric(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"time_idle": 43.0,
+					},
+					time.Unix(2, 0),
+				),
+				testutil.MustMetric(
+					"cpu",
+					map[string]string{},
+					map[string]interface{}{
+						"time_idle": 41.0,
+					},
+					time.Unix(0, 0),
+				),
+			},
+			expected: []byte(`
+cpu_time_idle 43
 `),
 		},
 		{
@@ -0,0 +1,12 @@
+package telegraf
+
+// SecretStore is a source of secrets for the configuration of other plugins.
+// A secret is referred to in the configuration as @{id:path}, or
+// @{id:path#key} to select one value of a secret holding several.
+type SecretStore interface {
+	PluginDescriber
+
+	// Get returns the secret at the path.  If key is not empty it selects
+	// one value of the secret.
+	Get(path, key string) (string, error)
+}
@@ -76,6 +76,12 @@ func RegisterTiming(measurement, field string, tags map[string]string) Stat {
 	return registry.registerTiming("internal_"+measurement, field, tags)
 }
 
+// Unregister removes the stat from the selfstat registry, so that it is no
+// longer returned by Metrics().  Registering it again returns a new stat.
+func Unregister(s Stat) {
+	registry.unregister(s)
+}
+
 // Metrics returns all registered stats as telegraf metrics.
 func Metrics() []telegraf.Metric {
 	registry.mu.Lock()
@@ -160,6 +166,20 @@ func (r *Registry) registerTiming(measurement, field string, tags map[string]str
 	return s
 }
 
+func (r *Registry) unregister(s Stat) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := key(s.Name(), s.Tags())
+	if stat, ok := r.get(key, s.FieldName()); !ok || stat != s {
+		return
+	}
+	delete(r.stats[key], s.FieldName())
+	if len(r.stats[key]) == 0 {
+		delete(r.stats, key)
+	}
+}
+
 func (r *Registry) get(key uint64, field string) (Stat, bool) {
 	if _, ok := r.stats[key]; !ok {
 		return nil, false
@@ -3,7 +3,9 @@ package selfstat
 import (
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -211,3 +213,37 @@ func TestRegisterCopy(t *testing.T) {
 	tags["new"] = "value"
 	require.NotEqual(t, tags, stat.Tags())
 }
+
+func TestUnregister(t *testing.T) {
+	testLock.Lock()
+	defer testCleanup()
+	s1 := Register("test", "test_field1", map[string]string{"test": "foo"})
+	s2 := Register("test", "test_field2", map[string]string{"test": "foo"})
+	s1.Set(1)
+	s2.Set(2)
+
+	Unregister(s1)
+	testutil.RequireMetricsEqual(t, []telegraf.Metric{
+		testutil.MustMetric("internal_test",
+			map[string]string{"test": "foo"},
+			map[string]interface{}{"test_field2": int64(2)},
+			time.Unix(0, 0)),
+	}, testMetrics(), testutil.IgnoreTime())
+
+	Unregister(s2)
+	require.Len(t, testMetrics(), 0)
+
+	s1 = Register("test", "test_field1", map[string]string{"test": "foo"})
+	require.Equal(t, int64(0), s1.Get())
+}
+
+// testMetrics returns the registered internal_test metrics.
+func testMetrics() []telegraf.Metric {
+	var metrics []telegraf.Metric
+	for _, m := range Metrics() {
+		if m.Name() == "internal_test" {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
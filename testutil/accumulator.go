@@ -29,6 +29,8 @@ type Metric struct {
 	Fields      map[string]interface{}
 	Time        time.Time
 	Type        telegraf.ValueType
+
+	Distributions []*telegraf.Distribution
 }
 
 func (p *Metric) String() string {
@@ -82,6 +84,7 @@ func (a *Accumulator) addFields(
 	measurement string,
 	tags map[string]string,
 	fields map[string]interface{},
+	distributions []*telegraf.Distribution,
 	tp telegraf.ValueType,
 	timestamp ...time.Time,
 ) {
@@ -131,11 +134,12 @@ func (a *Accumulator) addFields(
 	}
 
 	p := &Metric{
-		Measurement: measurement,
-		Fields:      fieldsCopy,
-		Tags:        tagsCopy,
-		Time:        t,
-		Type:        tp,
+		Measurement:   measurement,
+		Fields:        fieldsCopy,
+		Tags:          tagsCopy,
+		Time:          t,
+		Type:          tp,
+		Distributions: distributions,
 	}
 
 	a.Metrics = append(a.Metrics, p)
@@ -148,7 +152,7 @@ func (a *Accumulator) AddFields(
 	tags map[string]string,
 	timestamp ...time.Time,
 ) {
-	a.addFields(measurement, tags, fields, telegraf.Untyped, timestamp...)
+	a.addFields(measurement, tags, fields, nil, telegraf.Untyped, timestamp...)
 }
 
 func (a *Accumulator) AddCounter(
@@ -157,7 +161,7 @@ func (a *Accumulator) AddCounter(
 	tags map[string]string,
 	timestamp ...time.Time,
 ) {
-	a.addFields(measurement, tags, fields, telegraf.Counter, timestamp...)
+	a.addFields(measurement, tags, fields, nil, telegraf.Counter, timestamp...)
 }
 
 func (a *Accumulator) AddGauge(
@@ -166,12 +170,12 @@ func (a *Accumulator) AddGauge(
 	tags map[string]string,
 	timestamp ...time.Time,
 ) {
-	a.addFields(measurement, tags, fields, telegraf.Gauge, timestamp...)
+	a.addFields(measurement, tags, fields, nil, telegraf.Gauge, timestamp...)
 }
 
 func (a *Accumulator) AddMetrics(metrics []telegraf.Metric) {
 	for _, m := range metrics {
-		a.addFields(m.Name(), m.Tags(), m.Fields(), m.Type(), m.Time())
+		a.addFields(m.Name(), m.Tags(), m.Fields(), m.DistributionList(), m.Type(), m.Time())
 	}
 }
 
@@ -181,7 +185,7 @@ func (a *Accumulator) AddSummary(
 	tags map[string]string,
 	timestamp ...time.Time,
 ) {
-	a.addFields(measurement, tags, fields, telegraf.Summary, timestamp...)
+	a.addFields(measurement, tags, fields, nil, telegraf.Summary, timestamp...)
 }
 
 func (a *Accumulator) AddHistogram(
@@ -190,11 +194,11 @@ func (a *Accumulator) AddHistogram(
 	tags map[string]string,
 	timestamp ...time.Time,
 ) {
-	a.addFields(measurement, tags, fields, telegraf.Histogram, timestamp...)
+	a.addFields(measurement, tags, fields, nil, telegraf.Histogram, timestamp...)
 }
 
 func (a *Accumulator) AddMetric(m telegraf.Metric) {
-	a.addFields(m.Name(), m.Tags(), m.Fields(), m.Type(), m.Time())
+	a.addFields(m.Name(), m.Tags(), m.Fields(), m.DistributionList(), m.Type(), m.Time())
 }
 
 func (a *Accumulator) WithTracking(maxTracked int) telegraf.TrackingAccumulator {
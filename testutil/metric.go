@@ -13,11 +13,12 @@ import (
 )
 
 type metricDiff struct {
-	Measurement string
-	Tags        []*telegraf.Tag
-	Fields      []*telegraf.Field
-	Type        telegraf.ValueType
-	Time        time.Time
+	Measurement   string
+	Tags          []*telegraf.Tag
+	Fields        []*telegraf.Field
+	Distributions []*telegraf.Distribution
+	Type          telegraf.ValueType
+	Time          time.Time
 }
 
 func lessFunc(lhs, rhs *metricDiff) bool {
@@ -113,6 +114,9 @@ func newMetricDiff(metric telegraf.Metric) *metricDiff {
 		return m.Fields[i].Key < m.Fields[j].Key
 	})
 
+	if len(metric.DistributionList()) > 0 {
+		m.Distributions = metric.DistributionList()
+	}
 	m.Type = metric.Type()
 	m.Time = metric.Time()
 	return m
@@ -196,10 +200,22 @@ func MustMetric(
 	return m
 }
 
+// WithDistributions adds distributions to a metric, such as one created with
+// MustMetric, and returns it.
+func WithDistributions(m telegraf.Metric, distributions ...*telegraf.Distribution) telegraf.Metric {
+	for _, d := range distributions {
+		m.AddDistribution(d)
+	}
+	return m
+}
+
 func FromTestMetric(met *Metric) telegraf.Metric {
 	m, err := metric.New(met.Measurement, met.Tags, met.Fields, met.Time, met.Type)
 	if err != nil {
 		panic("MustMetric")
 	}
+	for _, d := range met.Distributions {
+		m.AddDistribution(d)
+	}
 	return m
 }